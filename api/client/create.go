@@ -9,6 +9,7 @@ import (
 
 	Cli "github.com/docker/docker/cli"
 	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/platform"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	runconfigopts "github.com/docker/docker/runconfig/opts"
@@ -19,10 +20,10 @@ import (
 )
 
 func (cli *DockerCli) pullImage(image string) error {
-	return cli.pullImageCustomOut(image, cli.out)
+	return cli.pullImageCustomOut(image, "", cli.out)
 }
 
-func (cli *DockerCli) pullImageCustomOut(image string, out io.Writer) error {
+func (cli *DockerCli) pullImageCustomOut(image, platform string, out io.Writer) error {
 	ref, err := reference.ParseNamed(image)
 	if err != nil {
 		return err
@@ -52,6 +53,7 @@ func (cli *DockerCli) pullImageCustomOut(image string, out io.Writer) error {
 		Parent:       ref.Name(),
 		Tag:          tag,
 		RegistryAuth: encodedAuth,
+		Platform:     platform,
 	}
 
 	responseBody, err := cli.client.ImageCreate(context.Background(), options)
@@ -82,7 +84,24 @@ func newCIDFile(path string) (*cidFile, error) {
 	return &cidFile{path: path, file: f}, nil
 }
 
-func (cli *DockerCli) createContainer(config *container.Config, hostConfig *container.HostConfig, networkingConfig *networktypes.NetworkingConfig, cidfile, name string) (*types.ContainerCreateResponse, error) {
+// imageMatchesPlatform reports whether the local image is for platformSpec,
+// in the os/arch[/variant] format.
+func (cli *DockerCli) imageMatchesPlatform(image, platformSpec string) (bool, error) {
+	p, err := platform.Parse(platformSpec)
+	if err != nil {
+		return false, err
+	}
+	inspect, _, err := cli.client.ImageInspectWithRaw(image, false)
+	if err != nil {
+		if client.IsErrImageNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return p.Matches(inspect.Os, inspect.Architecture, inspect.Variant), nil
+}
+
+func (cli *DockerCli) createContainer(config *container.Config, hostConfig *container.HostConfig, networkingConfig *networktypes.NetworkingConfig, cidfile, name, platform string) (*types.ContainerCreateResponse, error) {
 	var containerIDFile *cidFile
 	if cidfile != "" {
 		var err error
@@ -109,6 +128,19 @@ func (cli *DockerCli) createContainer(config *container.Config, hostConfig *cont
 		config.Image = trustedRef.String()
 	}
 
+	if platform != "" {
+		match, err := cli.imageMatchesPlatform(config.Image, platform)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			fmt.Fprintf(cli.err, "Unable to find image '%s' for %s locally\n", ref.String(), platform)
+			if err := cli.pullImageCustomOut(config.Image, platform, cli.err); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	//create the container
 	response, err := cli.client.ContainerCreate(config, hostConfig, networkingConfig, name)
 
@@ -118,7 +150,7 @@ func (cli *DockerCli) createContainer(config *container.Config, hostConfig *cont
 			fmt.Fprintf(cli.err, "Unable to find image '%s' locally\n", ref.String())
 
 			// we don't want to write to stdout anything apart from container.ID
-			if err = cli.pullImageCustomOut(config.Image, cli.err); err != nil {
+			if err = cli.pullImageCustomOut(config.Image, platform, cli.err); err != nil {
 				return nil, err
 			}
 			if ref, ok := ref.(reference.NamedTagged); ok && trustedRef != nil {
@@ -157,7 +189,8 @@ func (cli *DockerCli) CmdCreate(args ...string) error {
 
 	// These are flags not stored in Config/HostConfig
 	var (
-		flName = cmd.String([]string{"-name"}, "", "Assign a name to the container")
+		flName     = cmd.String([]string{"-name"}, "", "Assign a name to the container")
+		flPlatform = cmd.String([]string{"-platform"}, "", "Pull the image for a platform (os/arch[/variant]) if needed")
 	)
 
 	config, hostConfig, networkingConfig, cmd, err := runconfigopts.Parse(cmd, args)
@@ -170,7 +203,7 @@ func (cli *DockerCli) CmdCreate(args ...string) error {
 		cmd.Usage()
 		return nil
 	}
-	response, err := cli.createContainer(config, hostConfig, networkingConfig, hostConfig.ContainerIDFile, *flName)
+	response, err := cli.createContainer(config, hostConfig, networkingConfig, hostConfig.ContainerIDFile, *flName, *flPlatform)
 	if err != nil {
 		return err
 	}
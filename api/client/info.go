@@ -51,6 +51,8 @@ func (cli *DockerCli) CmdInfo(args ...string) error {
 	ioutils.FprintfIfNotEmpty(cli.out, "Execution Driver: %s\n", info.ExecutionDriver)
 	ioutils.FprintfIfNotEmpty(cli.out, "Logging Driver: %s\n", info.LoggingDriver)
 	ioutils.FprintfIfNotEmpty(cli.out, "Cgroup Driver: %s\n", info.CgroupDriver)
+	ioutils.FprintfIfNotEmpty(cli.out, "Seccomp Profile: %s\n", info.SeccompProfile)
+	ioutils.FprintfIfNotEmpty(cli.out, "AppArmor Template: %s\n", info.AppArmorTemplate)
 
 	fmt.Fprintf(cli.out, "Plugins: \n")
 	fmt.Fprintf(cli.out, " Volume:")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
 	"github.com/docker/engine-api/types/network"
+	"github.com/docker/go-units"
 )
 
 // CmdNetwork is the parent subcommand for all network commands
@@ -50,9 +52,16 @@ func (cli *DockerCli) CmdNetworkCreate(args ...string) error {
 	cmd.Var(flOpts, []string{"o", "-opt"}, "set driver specific options")
 	cmd.Var(flIpamOpt, []string{"-ipam-opt"}, "set IPAM driver specific options")
 
+	flLabels := opts.NewListOpts(nil)
+	cmd.Var(&flLabels, []string{"-label"}, "set metadata on a network")
+
 	flInternal := cmd.Bool([]string{"-internal"}, false, "restricts external access to the network")
 	flIPv6 := cmd.Bool([]string{"-ipv6"}, false, "enable IPv6 networking")
 
+	flEgressDefault := cmd.String([]string{"-egress-default"}, "", "default action, allow or deny, for the traffic leaving the network")
+	flEgressRules := opts.NewListOpts(nil)
+	cmd.Var(&flEgressRules, []string{"-egress-rule"}, "egress policy rule ACTION:CIDR[:PORT[/PROTO]|:PROTO]")
+
 	cmd.Require(flag.Exact, 1)
 	err := cmd.ParseFlags(args, true)
 	if err != nil {
@@ -71,15 +80,22 @@ func (cli *DockerCli) CmdNetworkCreate(args ...string) error {
 		return err
 	}
 
+	egressPolicy, err := parseEgressPolicy(*flEgressDefault, flEgressRules.GetAll())
+	if err != nil {
+		return err
+	}
+
 	// Construct network create request body
 	nc := types.NetworkCreate{
 		Name:           cmd.Arg(0),
 		Driver:         driver,
 		IPAM:           network.IPAM{Driver: *flIpamDriver, Config: ipamCfg, Options: flIpamOpt.GetAll()},
 		Options:        flOpts.GetAll(),
+		Labels:         runconfigopts.ConvertKVStringsToMap(flLabels.GetAll()),
 		CheckDuplicate: true,
 		Internal:       *flInternal,
 		EnableIPv6:     *flIPv6,
+		EgressPolicy:   egressPolicy,
 	}
 
 	resp, err := cli.client.NetworkCreate(nc)
@@ -125,21 +141,47 @@ func (cli *DockerCli) CmdNetworkConnect(args ...string) error {
 	cmd.Var(&flLinks, []string{"-link"}, "Add link to another container")
 	flAliases := opts.NewListOpts(nil)
 	cmd.Var(&flAliases, []string{"-alias"}, "Add network-scoped alias for the container")
+	flDNSRRName := cmd.String([]string{"-dns-rr-name"}, "", "Add the container to a network-scoped round-robin DNS name")
+	flIngressRate := cmd.String([]string{"-ingress-rate"}, "", "Limit incoming traffic rate (bytes per second)")
+	flEgressRate := cmd.String([]string{"-egress-rate"}, "", "Limit outgoing traffic rate (bytes per second)")
 	cmd.Require(flag.Min, 2)
 	if err := cmd.ParseFlags(args, true); err != nil {
 		return err
 	}
+	ingressRate, err := parseRate(*flIngressRate)
+	if err != nil {
+		return err
+	}
+	egressRate, err := parseRate(*flEgressRate)
+	if err != nil {
+		return err
+	}
 	epConfig := &network.EndpointSettings{
 		IPAMConfig: &network.EndpointIPAMConfig{
 			IPv4Address: *flIPAddress,
 			IPv6Address: *flIPv6Address,
 		},
-		Links:   flLinks.GetAll(),
-		Aliases: flAliases.GetAll(),
+		Links:       flLinks.GetAll(),
+		Aliases:     flAliases.GetAll(),
+		DNSRRName:   *flDNSRRName,
+		IngressRate: ingressRate,
+		EgressRate:  egressRate,
 	}
 	return cli.client.NetworkConnect(cmd.Arg(0), cmd.Arg(1), epConfig)
 }
 
+// parseRate parses a rate limit such as "512k" or "10m" into bytes per second.
+func parseRate(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	rate, err := units.RAMInBytes(value)
+	if err != nil || rate < 0 {
+		return 0, fmt.Errorf("invalid rate %q, must be a positive number of bytes per second", value)
+	}
+	return uint64(rate), nil
+}
+
 // CmdNetworkDisconnect disconnects a container from a network
 //
 // Usage: docker network disconnect <NETWORK> <CONTAINER>
@@ -344,6 +386,43 @@ func consolidateIpam(subnets, ranges, gateways []string, auxaddrs map[string]str
 	return idl, nil
 }
 
+// parseEgressPolicy builds the egress policy of a network from its default
+// action and its rules in the ACTION:CIDR[:PORT[/PROTO]|:PROTO] format, e.g.
+// "allow:10.0.0.0/8:443/tcp". A port without protocol is a TCP port.
+func parseEgressPolicy(defaultAction string, rules []string) (*network.EgressPolicy, error) {
+	if defaultAction == "" && len(rules) == 0 {
+		return nil, nil
+	}
+
+	policy := &network.EgressPolicy{Default: defaultAction}
+	for _, r := range rules {
+		parts := strings.Split(r, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid egress rule %q, must be ACTION:CIDR[:PORT[/PROTO]|:PROTO]", r)
+		}
+		rule := network.EgressRule{Action: parts[0], CIDR: parts[1]}
+		if len(parts) == 3 {
+			port, proto := parts[2], ""
+			if i := strings.Index(port, "/"); i >= 0 {
+				port, proto = port[:i], port[i+1:]
+			}
+			if p, err := strconv.ParseUint(port, 10, 16); err == nil {
+				rule.Port = uint16(p)
+				rule.Protocol = "tcp"
+				if proto != "" {
+					rule.Protocol = proto
+				}
+			} else if proto == "" {
+				rule.Protocol = port
+			} else {
+				return nil, fmt.Errorf("invalid port %q in egress rule %q", port, r)
+			}
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy, nil
+}
+
 func subnetMatches(subnet, data string) (bool, error) {
 	var (
 		ip net.IP
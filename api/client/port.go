@@ -2,6 +2,7 @@ package client
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	Cli "github.com/docker/docker/cli"
@@ -42,7 +43,7 @@ func (cli *DockerCli) CmdPort(args ...string) error {
 		}
 		if frontends, exists := c.NetworkSettings.Ports[newP]; exists && frontends != nil {
 			for _, frontend := range frontends {
-				fmt.Fprintf(cli.out, "%s:%s\n", frontend.HostIP, frontend.HostPort)
+				fmt.Fprintln(cli.out, net.JoinHostPort(frontend.HostIP, frontend.HostPort))
 			}
 			return nil
 		}
@@ -51,9 +52,55 @@ func (cli *DockerCli) CmdPort(args ...string) error {
 
 	for from, frontends := range c.NetworkSettings.Ports {
 		for _, frontend := range frontends {
-			fmt.Fprintf(cli.out, "%s -> %s:%s\n", from, frontend.HostIP, frontend.HostPort)
+			fmt.Fprintf(cli.out, "%s -> %s\n", from, net.JoinHostPort(frontend.HostIP, frontend.HostPort))
 		}
 	}
 
 	return nil
 }
+
+// CmdPortAdd publishes additional ports on a container.
+//
+// Usage: docker port add CONTAINER [IP:][HOST_PORT:]CONTAINER_PORT[/PROTO] [...]
+func (cli *DockerCli) CmdPortAdd(args ...string) error {
+	cmd := Cli.Subcmd("port add", []string{"CONTAINER [IP:][HOST_PORT:]CONTAINER_PORT[/PROTO] [[IP:][HOST_PORT:]CONTAINER_PORT[/PROTO]...]"}, "Publish one or more ports on a container", true)
+	cmd.Require(flag.Min, 2)
+
+	cmd.ParseFlags(args, true)
+
+	_, portBindings, err := nat.ParsePortSpecs(cmd.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	return cli.client.ContainerPublish(cmd.Arg(0), portBindings)
+}
+
+// CmdPortRm unpublishes ports from a container.
+// A port given without a host port removes all of its bindings.
+//
+// Usage: docker port rm CONTAINER [IP:][HOST_PORT:]CONTAINER_PORT[/PROTO] [...]
+func (cli *DockerCli) CmdPortRm(args ...string) error {
+	cmd := Cli.Subcmd("port rm", []string{"CONTAINER [IP:][HOST_PORT:]CONTAINER_PORT[/PROTO] [[IP:][HOST_PORT:]CONTAINER_PORT[/PROTO]...]"}, "Unpublish one or more ports from a container", true)
+	cmd.Require(flag.Min, 2)
+
+	cmd.ParseFlags(args, true)
+
+	portBindings := nat.PortMap{}
+	for _, spec := range cmd.Args()[1:] {
+		_, bindings, err := nat.ParsePortSpecs([]string{spec})
+		if err != nil {
+			return err
+		}
+		for port, b := range bindings {
+			if !strings.Contains(spec, ":") {
+				// No host port given, remove every binding of the port
+				portBindings[port] = nil
+				continue
+			}
+			portBindings[port] = append(portBindings[port], b...)
+		}
+	}
+
+	return cli.client.ContainerUnpublish(cmd.Arg(0), portBindings)
+}
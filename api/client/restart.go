@@ -18,9 +18,15 @@ func (cli *DockerCli) CmdRestart(args ...string) error {
 
 	cmd.ParseFlags(args, true)
 
+	// Leave the timeout to the container unless it is given explicitly.
+	var timeout *int
+	if cmd.IsSet("t") || cmd.IsSet("-time") {
+		timeout = nSeconds
+	}
+
 	var errs []string
 	for _, name := range cmd.Args() {
-		if err := cli.client.ContainerRestart(name, *nSeconds); err != nil {
+		if err := cli.client.ContainerRestart(name, timeout); err != nil {
 			errs = append(errs, err.Error())
 		} else {
 			fmt.Fprintf(cli.out, "%s\n", name)
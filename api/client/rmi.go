@@ -7,6 +7,8 @@ import (
 
 	Cli "github.com/docker/docker/cli"
 	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
 	"github.com/docker/engine-api/types"
 )
 
@@ -17,10 +19,16 @@ func (cli *DockerCli) CmdRmi(args ...string) error {
 	cmd := Cli.Subcmd("rmi", []string{"IMAGE [IMAGE...]"}, Cli.DockerCommands["rmi"].Description, true)
 	force := cmd.Bool([]string{"f", "-force"}, false, "Force removal of the image")
 	noprune := cmd.Bool([]string{"-no-prune"}, false, "Do not delete untagged parents")
+	remote := cmd.Bool([]string{"-remote"}, false, "Delete the image from its registry")
+	dryRun := cmd.Bool([]string{"-dry-run"}, false, "List what --remote would delete without deleting it")
 	cmd.Require(flag.Min, 1)
 
 	cmd.ParseFlags(args, true)
 
+	if *dryRun && !*remote {
+		return fmt.Errorf("--dry-run can only be used with --remote")
+	}
+
 	v := url.Values{}
 	if *force {
 		v.Set("force", "1")
@@ -35,17 +43,31 @@ func (cli *DockerCli) CmdRmi(args ...string) error {
 			ImageID:       name,
 			Force:         *force,
 			PruneChildren: !*noprune,
+			Remote:        *remote,
+			DryRun:        *dryRun,
+		}
+		if *remote {
+			encodedAuth, err := cli.encodeRegistryAuth(name)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			options.RegistryAuth = encodedAuth
 		}
 
 		dels, err := cli.client.ImageRemove(options)
 		if err != nil {
 			errs = append(errs, err.Error())
 		} else {
+			deleted, untagged := "Deleted", "Untagged"
+			if *dryRun {
+				deleted, untagged = "Would delete", "Would untag"
+			}
 			for _, del := range dels {
 				if del.Deleted != "" {
-					fmt.Fprintf(cli.out, "Deleted: %s\n", del.Deleted)
+					fmt.Fprintf(cli.out, "%s: %s\n", deleted, del.Deleted)
 				} else {
-					fmt.Fprintf(cli.out, "Untagged: %s\n", del.Untagged)
+					fmt.Fprintf(cli.out, "%s: %s\n", untagged, del.Untagged)
 				}
 			}
 		}
@@ -55,3 +77,17 @@ func (cli *DockerCli) CmdRmi(args ...string) error {
 	}
 	return nil
 }
+
+// encodeRegistryAuth returns the encoded credentials for the registry of
+// the image name.
+func (cli *DockerCli) encodeRegistryAuth(name string) (string, error) {
+	ref, err := reference.ParseNamed(name)
+	if err != nil {
+		return "", err
+	}
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return "", err
+	}
+	return encodeAuthToBase64(cli.resolveAuthConfig(repoInfo.Index))
+}
@@ -7,7 +7,9 @@ import (
 	"golang.org/x/net/context"
 
 	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
 	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/engine-api/types"
 )
 
 // CmdSave saves one or more images to a tar archive.
@@ -18,6 +20,8 @@ import (
 func (cli *DockerCli) CmdSave(args ...string) error {
 	cmd := Cli.Subcmd("save", []string{"IMAGE [IMAGE...]"}, Cli.DockerCommands["save"].Description+" (streamed to STDOUT by default)", true)
 	outfile := cmd.String([]string{"o", "-output"}, "", "Write to a file, instead of STDOUT")
+	flExcludeBase := opts.NewListOpts(nil)
+	cmd.Var(&flExcludeBase, []string{"-exclude-base"}, "Omit layers of a base image the receiver already has")
 	cmd.Require(flag.Min, 1)
 
 	cmd.ParseFlags(args, true)
@@ -26,7 +30,12 @@ func (cli *DockerCli) CmdSave(args ...string) error {
 		return errors.New("Cowardly refusing to save to a terminal. Use the -o flag or redirect.")
 	}
 
-	responseBody, err := cli.client.ImageSave(context.Background(), cmd.Args())
+	options := types.ImageSaveOptions{
+		ImageIDs:    cmd.Args(),
+		ExcludeBase: flExcludeBase.GetAll(),
+	}
+
+	responseBody, err := cli.client.ImageSave(context.Background(), options)
 	if err != nil {
 		return err
 	}
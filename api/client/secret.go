@@ -0,0 +1,140 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	flag "github.com/docker/docker/pkg/mflag"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
+)
+
+// CmdSecret is the parent subcommand for all secret commands
+//
+// Usage: docker secret <COMMAND> <OPTS>
+func (cli *DockerCli) CmdSecret(args ...string) error {
+	description := Cli.DockerCommands["secret"].Description + "\n\nCommands:\n"
+	commands := [][]string{
+		{"create", "Create a secret"},
+		{"ls", "List secrets"},
+		{"rm", "Remove a secret"},
+	}
+
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+
+	description += "\nRun 'docker secret COMMAND --help' for more information on a command"
+	cmd := Cli.Subcmd("secret", []string{"[COMMAND]"}, description, false)
+
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+// CmdSecretCreate creates a new secret from the content of a file, or of the
+// standard input.
+//
+// Usage: docker secret create [OPTIONS] NAME [FILE|-]
+func (cli *DockerCli) CmdSecretCreate(args ...string) error {
+	cmd := Cli.Subcmd("secret create", []string{"NAME [FILE|-]"}, "Create a secret from a file or STDIN", true)
+	flLabels := opts.NewListOpts(nil)
+	cmd.Var(&flLabels, []string{"l", "-label"}, "Set metadata on the secret")
+
+	cmd.Require(flag.Min, 1)
+	cmd.Require(flag.Max, 2)
+	cmd.ParseFlags(args, true)
+
+	var in io.Reader = cli.in
+	if file := cmd.Arg(1); file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	secret, err := cli.client.SecretCreate(types.SecretCreateRequest{
+		Name:   cmd.Arg(0),
+		Data:   data,
+		Labels: runconfigopts.ConvertKVStringsToMap(flLabels.GetAll()),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cli.out, "%s\n", secret.Name)
+	return nil
+}
+
+// CmdSecretLs outputs a list of the secrets, without their content.
+//
+// Usage: docker secret ls [OPTIONS]
+func (cli *DockerCli) CmdSecretLs(args ...string) error {
+	cmd := Cli.Subcmd("secret ls", nil, "List secrets", true)
+	quiet := cmd.Bool([]string{"q", "-quiet"}, false, "Only display secret names")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	secrets, err := cli.client.SecretList()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cli.out, 20, 1, 3, ' ', 0)
+	if !*quiet {
+		fmt.Fprintln(w, "NAME\tCREATED\tSIZE")
+	}
+	for _, secret := range secrets {
+		if *quiet {
+			fmt.Fprintln(w, secret.Name)
+			continue
+		}
+		created := secret.CreatedAt
+		if t, err := time.Parse(time.RFC3339Nano, secret.CreatedAt); err == nil {
+			created = units.HumanDuration(time.Now().UTC().Sub(t)) + " ago"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", secret.Name, created, units.HumanSize(float64(secret.Size)))
+	}
+	w.Flush()
+	return nil
+}
+
+// CmdSecretRm removes one or more secrets.
+//
+// Usage: docker secret rm SECRET [SECRET...]
+func (cli *DockerCli) CmdSecretRm(args ...string) error {
+	cmd := Cli.Subcmd("secret rm", []string{"SECRET [SECRET...]"}, "Remove a secret", true)
+	cmd.Require(flag.Min, 1)
+	cmd.ParseFlags(args, true)
+
+	var status = 0
+
+	for _, name := range cmd.Args() {
+		if err := cli.client.SecretRemove(name); err != nil {
+			fmt.Fprintf(cli.err, "%s\n", err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(cli.out, "%s\n", name)
+	}
+
+	if status != 0 {
+		return Cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
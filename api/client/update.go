@@ -19,6 +19,9 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 	flBlkioWeight := cmd.Uint16([]string{"-blkio-weight"}, 0, "Block IO (relative weight), between 10 and 1000")
 	flCPUPeriod := cmd.Int64([]string{"-cpu-period"}, 0, "Limit CPU CFS (Completely Fair Scheduler) period")
 	flCPUQuota := cmd.Int64([]string{"-cpu-quota"}, 0, "Limit CPU CFS (Completely Fair Scheduler) quota")
+	flCPURtPeriod := cmd.Int64([]string{"-cpu-rt-period"}, 0, "Limit the CPU real-time period in microseconds")
+	flCPURtRuntime := cmd.Int64([]string{"-cpu-rt-runtime"}, 0, "Limit the CPU real-time runtime in microseconds")
+	flCPUs := cmd.String([]string{"-cpus"}, "", "Number of CPUs")
 	flCpusetCpus := cmd.String([]string{"-cpuset-cpus"}, "", "CPUs in which to allow execution (0-3, 0,1)")
 	flCpusetMems := cmd.String([]string{"-cpuset-mems"}, "", "MEMs in which to allow execution (0-3, 0,1)")
 	flCPUShares := cmd.Int64([]string{"#c", "-cpu-shares"}, 0, "CPU shares (relative weight)")
@@ -71,6 +74,14 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 		}
 	}
 
+	var nanoCPUs int64
+	if *flCPUs != "" {
+		nanoCPUs, err = opts.ParseCPUs(*flCPUs)
+		if err != nil {
+			return err
+		}
+	}
+
 	var restartPolicy container.RestartPolicy
 	if *flRestartPolicy != "" {
 		restartPolicy, err = opts.ParseRestartPolicy(*flRestartPolicy)
@@ -80,16 +91,19 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 	}
 
 	resources := container.Resources{
-		BlkioWeight:       *flBlkioWeight,
-		CpusetCpus:        *flCpusetCpus,
-		CpusetMems:        *flCpusetMems,
-		CPUShares:         *flCPUShares,
-		Memory:            flMemory,
-		MemoryReservation: memoryReservation,
-		MemorySwap:        memorySwap,
-		KernelMemory:      kernelMemory,
-		CPUPeriod:         *flCPUPeriod,
-		CPUQuota:          *flCPUQuota,
+		BlkioWeight:        *flBlkioWeight,
+		CpusetCpus:         *flCpusetCpus,
+		CpusetMems:         *flCpusetMems,
+		CPUShares:          *flCPUShares,
+		Memory:             flMemory,
+		MemoryReservation:  memoryReservation,
+		MemorySwap:         memorySwap,
+		KernelMemory:       kernelMemory,
+		CPUPeriod:          *flCPUPeriod,
+		CPUQuota:           *flCPUQuota,
+		CPURealtimePeriod:  *flCPURtPeriod,
+		CPURealtimeRuntime: *flCPURtRuntime,
+		NanoCPUs:           nanoCPUs,
 	}
 
 	updateConfig := container.UpdateConfig{
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"mime"
+	"net"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -67,7 +68,7 @@ func DisplayablePorts(ports []types.Port) string {
 		portKey := port.Type
 		if port.IP != "" {
 			if port.PublicPort != current {
-				hostMappings = append(hostMappings, fmt.Sprintf("%s:%d->%d/%s", port.IP, port.PublicPort, port.PrivatePort, port.Type))
+				hostMappings = append(hostMappings, fmt.Sprintf("%s->%d/%s", net.JoinHostPort(port.IP, strconv.Itoa(port.PublicPort)), port.PrivatePort, port.Type))
 				continue
 			}
 			portKey = fmt.Sprintf("%s/%s", port.IP, port.Type)
@@ -109,7 +110,7 @@ func formGroup(key string, start, last int) string {
 		group = fmt.Sprintf("%s-%d", group, last)
 	}
 	if ip != "" {
-		group = fmt.Sprintf("%s:%s->%s", ip, group, group)
+		group = fmt.Sprintf("%s->%s", net.JoinHostPort(ip, group), group)
 	}
 	return fmt.Sprintf("%s/%s", group, groupType)
 }
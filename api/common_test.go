@@ -247,6 +247,27 @@ func TestDisplayablePorts(t *testing.T) {
 			},
 			"80/tcp, 80/udp, 1024/tcp, 1024/udp, 1.1.1.1:1024->80/tcp, 1.1.1.1:1024->80/udp, 2.1.1.1:1024->80/tcp, 2.1.1.1:1024->80/udp, 1.1.1.1:80->1024/tcp, 1.1.1.1:80->1024/udp, 2.1.1.1:80->1024/tcp, 2.1.1.1:80->1024/udp",
 		},
+		{
+			[]types.Port{
+				{
+					IP:          "0.0.0.0",
+					PublicPort:  32768,
+					PrivatePort: 80,
+					Type:        "tcp",
+				}, {
+					IP:          "::",
+					PublicPort:  32768,
+					PrivatePort: 80,
+					Type:        "tcp",
+				}, {
+					IP:          "2001:db8::2",
+					PublicPort:  443,
+					PrivatePort: 443,
+					Type:        "tcp",
+				},
+			},
+			"[2001:db8::2]:443->443/tcp, 0.0.0.0:32768->80/tcp, [::]:32768->80/tcp",
+		},
 	}
 
 	for _, port := range cases {
@@ -31,11 +31,24 @@ func (s *Server) handleWithGlobalMiddlewares(handler httputils.APIFunc) httputil
 		next = middleware.DebugRequestMiddleware(next)
 	}
 
-	if len(s.cfg.AuthorizationPluginNames) > 0 {
-		s.authZPlugins = authorization.NewPlugins(s.cfg.AuthorizationPluginNames)
+	if len(s.cfg.Authorizers) > 0 || len(s.cfg.AuthorizationPluginNames) > 0 {
+		// Built-in authorizers are evaluated before the plugins
+		s.authZPlugins = append(append([]authorization.Plugin{}, s.cfg.Authorizers...), authorization.NewPlugins(s.cfg.AuthorizationPluginNames)...)
 		handleAuthorization := middleware.NewAuthorizationMiddleware(s.authZPlugins)
 		next = handleAuthorization(next)
 	}
 
+	// Authentication must run before authorization
+	if len(s.cfg.Authenticators) > 0 {
+		handleAuthentication := middleware.NewAuthenticationMiddleware(s.cfg.Authenticators)
+		next = handleAuthentication(next)
+	}
+
+	// Auditing records the requests denied by authentication and authorization
+	if s.cfg.AuditLogger != nil {
+		handleAudit := middleware.NewAuditMiddleware(s.cfg.AuditLogger, s.cfg.AuditResolver)
+		next = handleAudit(next)
+	}
+
 	return next
 }
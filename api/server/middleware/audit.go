@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/pkg/audit"
+	"github.com/docker/docker/pkg/authentication"
+	"github.com/docker/docker/pkg/ioutils"
+	"golang.org/x/net/context"
+)
+
+// maxAuditBodySize is the size of the largest request and response bodies
+// summarized in the audit records.
+const maxAuditBodySize = 65536
+
+var (
+	versionPrefix      = regexp.MustCompile(`^/v[0-9.]+/`)
+	execSessionRoute   = regexp.MustCompile(`^/exec/[^/]+/start$`)
+	attachSessionRoute = regexp.MustCompile(`^/containers/[^/]+/attach(/ws)?$`)
+)
+
+// NewAuditMiddleware creates a new Audit middleware. It records the requests
+// changing the state of the daemon and the requests whose credentials are
+// rejected, along with the start and the end of the exec and attach sessions,
+// with logger. It must run before the authentication, whose outcome it reads
+// from the request context. The IDs of the resources referenced in the
+// request paths are looked up with resolver, if not nil.
+func NewAuditMiddleware(logger *audit.Logger, resolver audit.Resolver) Middleware {
+	return func(handler httputils.APIFunc) httputils.APIFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+			route := versionPrefix.ReplaceAllString(r.URL.Path, "/")
+			session := auditSession(route)
+			ctx, attempt := authentication.WithAttempt(ctx)
+			rec := &audit.Record{
+				PeerAddr: r.RemoteAddr,
+				Method:   r.Method,
+				Route:    route,
+				Session:  session,
+			}
+			readOnly := session == "" && (r.Method == "GET" || r.Method == "HEAD")
+			if !readOnly {
+				rec.Body = auditBody(r)
+			}
+			resourceType := routeResourceType(route)
+			for _, key := range []string{"name", "id"} {
+				if ref := vars[key]; ref != "" {
+					rec.ResourceIDs = append(rec.ResourceIDs, resourceIDs(resolver, resourceType, ref)...)
+				}
+			}
+
+			// Sessions start when their connection is hijacked, once
+			// the request is authenticated and authorized
+			rw := &auditResponseWriter{ResponseWriter: w}
+			if session != "" {
+				rw.onHijack = func() {
+					opening := *rec
+					identify(&opening, attempt)
+					opening.Stage = audit.StageStart
+					logAudit(logger, &opening)
+				}
+			}
+
+			start := time.Now()
+			err := handler(ctx, rw, r, vars)
+			if readOnly && attempt.Err == nil {
+				return err
+			}
+
+			identify(rec, attempt)
+			rec.Duration = time.Since(start).String()
+			switch {
+			case err != nil:
+				rec.Status = httputils.GetHTTPErrorStatusCode(err)
+				rec.Error = err.Error()
+			case rw.hijacked && r.Header.Get("Upgrade") != "":
+				rec.Status = http.StatusSwitchingProtocols
+			case rw.status == 0:
+				rec.Status = http.StatusOK
+			default:
+				rec.Status = rw.status
+			}
+			if rw.hijacked && session != "" {
+				rec.Stage = audit.StageEnd
+			}
+			if id := createdID(rec.Status, rw.body.Bytes()); id != "" {
+				rec.ResourceIDs = append(rec.ResourceIDs, id)
+			}
+			logAudit(logger, rec)
+
+			return err
+		}
+	}
+}
+
+// identify sets the identity of the caller of a request in its record, or
+// the authentication method which rejected its credentials.
+func identify(rec *audit.Record, attempt *authentication.Attempt) {
+	if id := attempt.Identity; id != nil {
+		rec.User, rec.Groups, rec.AuthMethod = id.User, id.Groups, id.Method
+	} else if attempt.Err != nil {
+		rec.AuthMethod = attempt.Method
+	}
+}
+
+func logAudit(logger *audit.Logger, rec *audit.Record) {
+	if err := logger.Log(rec); err != nil {
+		logrus.Errorf("Error writing the audit record of %s %s: %v", rec.Method, rec.Route, err)
+	}
+}
+
+// auditSession returns the kind of interactive session started by requests
+// to route, if any.
+func auditSession(route string) string {
+	switch {
+	case execSessionRoute.MatchString(route):
+		return "exec"
+	case attachSessionRoute.MatchString(route):
+		return "attach"
+	}
+	return ""
+}
+
+// routeResourceType returns the type of the resources referenced in route,
+// which is the first segment of the route, e.g. containers or networks.
+func routeResourceType(route string) string {
+	return strings.SplitN(strings.TrimPrefix(route, "/"), "/", 2)[0]
+}
+
+// resourceIDs returns the IDs of the resource of the given type referenced
+// by ref, or ref itself if it can't be resolved.
+func resourceIDs(resolver audit.Resolver, resourceType, ref string) []string {
+	if resolver != nil {
+		if ids := resolver.ResourceIDs(resourceType, ref); len(ids) > 0 {
+			return ids
+		}
+	}
+	return []string{ref}
+}
+
+// auditBody returns the redacted summary of the JSON body of the request,
+// restoring the body for the handlers.
+func auditBody(r *http.Request) interface{} {
+	if r.ContentLength <= 0 || r.ContentLength > maxAuditBodySize {
+		return nil
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return nil
+	}
+
+	body := r.Body
+	b, err := ioutil.ReadAll(body)
+	r.Body = ioutils.NewReadCloserWrapper(bytes.NewReader(b), func() error { return body.Close() })
+	if err != nil {
+		return nil
+	}
+	return audit.Summarize(b)
+}
+
+// createdID returns the ID of the resource created by a request from its
+// response body.
+func createdID(status int, body []byte) string {
+	if status != http.StatusCreated {
+		return ""
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return ""
+	}
+	return created.ID
+}
+
+// auditResponseWriter records the status and the beginning of the body of a
+// response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	hijacked bool
+	// onHijack is called before the connection is hijacked, if not nil
+	onHijack func()
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if n := maxAuditBodySize - w.body.Len(); n > 0 {
+		if n > len(b) {
+			n = len(b)
+		}
+		w.body.Write(b[:n])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("Internal response writer doesn't support the Hijacker interface")
+	}
+	if w.onHijack != nil && !w.hijacked {
+		w.onHijack()
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+func (w *auditResponseWriter) CloseNotify() <-chan bool {
+	closeNotifier, ok := w.ResponseWriter.(http.CloseNotifier)
+	if !ok {
+		logrus.Errorf("Internal response writer doesn't support the CloseNotifier interface")
+		return nil
+	}
+	return closeNotifier.CloseNotify()
+}
+
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/audit"
+	"github.com/docker/docker/pkg/authentication"
+	"golang.org/x/net/context"
+)
+
+type auditSink struct {
+	bytes.Buffer
+}
+
+func (*auditSink) Close() error {
+	return nil
+}
+
+func (s *auditSink) records(t *testing.T) []audit.Record {
+	var records []audit.Record
+	dec := json.NewDecoder(&s.Buffer)
+	for dec.More() {
+		var r audit.Record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+type mapResolver map[string][]string
+
+func (m mapResolver) ResourceIDs(resourceType, ref string) []string {
+	return m[resourceType+"/"+ref]
+}
+
+// hijackRecorder is a response recorder whose connection can be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	c, _ := net.Pipe()
+	return c, bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c)), nil
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	var body string
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if r.Body != nil {
+			b, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return err
+			}
+			body = string(b)
+		}
+		switch r.URL.Path {
+		case "/v1.24/containers/create":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"Id":"abc123","Warnings":null}`)
+		case "/v1.24/containers/missing/stop":
+			return errors.NewRequestNotFoundError(fmt.Errorf("No such container: missing"))
+		case "/v1.24/exec/e1/start":
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return err
+			}
+			conn.Close()
+		}
+		return nil
+	}
+
+	sink := &auditSink{}
+	resolver := mapResolver{"containers/web": {"def456"}, "exec/e1": {"e1", "def456"}, "networks/web": {"0fa1"}}
+	authn := NewAuthenticationMiddleware([]authentication.Authenticator{headerAuthenticator{}})
+	h := NewAuditMiddleware(audit.New(sink), resolver)(authn(handler))
+
+	create := `{"Image":"busybox","Env":["SECRET=hunter2"]}`
+	req, _ := http.NewRequest("POST", "/v1.24/containers/create?name=web", strings.NewReader(create))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "alice")
+	req.RemoteAddr = "10.0.0.1:41234"
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if body != create {
+		t.Fatalf("expected the handler to read the request body %s, got %s", create, body)
+	}
+
+	// Read-only requests are only recorded when their credentials are rejected
+	req, _ = http.NewRequest("GET", "/v1.24/containers/json", nil)
+	req.Header.Set("X-User", "alice")
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ = http.NewRequest("GET", "/v1.24/containers/json", nil)
+	req.Header.Set("X-User", "invalid")
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{}); err == nil {
+		t.Fatal("expected the authentication error to be returned")
+	}
+
+	req, _ = http.NewRequest("POST", "/v1.24/containers/missing/stop", nil)
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": "missing"}); err == nil {
+		t.Fatal("expected the handler error to be returned")
+	}
+
+	// Resources are resolved by the type of the route
+	req, _ = http.NewRequest("DELETE", "/v1.24/networks/web", nil)
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{"id": "web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ = http.NewRequest("POST", "/v1.24/exec/e1/start", nil)
+	req.Header.Set("X-User", "alice")
+	if err := h(context.Background(), hijackRecorder{httptest.NewRecorder()}, req, map[string]string{"name": "e1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	records := sink.records(t)
+	if len(records) != 6 {
+		t.Fatalf("expected 6 records, got %d: %+v", len(records), records)
+	}
+
+	r := records[0]
+	if r.User != "alice" || r.AuthMethod != "header" || r.PeerAddr != "10.0.0.1:41234" ||
+		r.Method != "POST" || r.Route != "/containers/create" || r.Status != http.StatusCreated ||
+		len(r.ResourceIDs) != 1 || r.ResourceIDs[0] != "abc123" {
+		t.Fatalf("unexpected create record %+v", r)
+	}
+	if env := r.Body.(map[string]interface{})["Env"].([]interface{}); env[0] != "SECRET=*****" {
+		t.Fatalf("expected the environment values to be redacted, got %v", env)
+	}
+
+	r = records[1]
+	if r.User != "" || r.AuthMethod != "header" || r.Method != "GET" || r.Status != http.StatusUnauthorized || r.Error == "" {
+		t.Fatalf("unexpected failed authentication record %+v", r)
+	}
+
+	r = records[2]
+	if r.User != "" || r.Status != http.StatusNotFound || r.Error == "" || r.ResourceIDs[0] != "missing" {
+		t.Fatalf("unexpected stop record %+v", r)
+	}
+
+	r = records[3]
+	if len(r.ResourceIDs) != 1 || r.ResourceIDs[0] != "0fa1" {
+		t.Fatalf("expected the network ID, got %v", r.ResourceIDs)
+	}
+
+	start, end := records[4], records[5]
+	if start.Session != "exec" || start.Stage != audit.StageStart || start.Status != 0 || start.User != "alice" {
+		t.Fatalf("unexpected exec start record %+v", start)
+	}
+	if end.Session != "exec" || end.Stage != audit.StageEnd || end.Status != http.StatusOK || end.Duration == "" || end.User != "alice" {
+		t.Fatalf("unexpected exec end record %+v", end)
+	}
+	if len(end.ResourceIDs) != 2 || end.ResourceIDs[0] != "e1" || end.ResourceIDs[1] != "def456" {
+		t.Fatalf("expected the exec and container IDs, got %v", end.ResourceIDs)
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/authentication"
+	"golang.org/x/net/context"
+)
+
+// NewAuthenticationMiddleware creates a new Authentication middleware. The
+// authenticators are tried in order and the first one recognizing
+// credentials in the request sets its identity in the request context.
+// Requests without credentials are served anonymously, requests with invalid
+// credentials are rejected. The outcome is also recorded in the attempt of
+// the request context, if any.
+func NewAuthenticationMiddleware(authenticators []authentication.Authenticator) Middleware {
+	return func(handler httputils.APIFunc) httputils.APIFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+			attempt := authentication.AttemptFromContext(ctx)
+			var id *authentication.Identity
+			for _, a := range authenticators {
+				var err error
+				if id, err = a.Authenticate(r); err != nil {
+					logrus.Warnf("Authentication of %s %s from %s using %s failed: %v", r.Method, r.RequestURI, r.RemoteAddr, a.Name(), err)
+					if attempt != nil {
+						attempt.Method, attempt.Err = a.Name(), err
+					}
+					return errors.NewErrorWithStatusCode(err, http.StatusUnauthorized)
+				}
+				if id != nil {
+					break
+				}
+			}
+			if id == nil {
+				return handler(ctx, w, r, vars)
+			}
+			if attempt != nil {
+				attempt.Identity = id
+			}
+
+			logrus.Debugf("Request %s %s authenticated as %s using %s", r.Method, r.RequestURI, id.User, id.Method)
+			return handler(authentication.NewContext(ctx, id), w, r, vars)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/pkg/authentication"
+	"golang.org/x/net/context"
+)
+
+type headerAuthenticator struct{}
+
+func (headerAuthenticator) Name() string {
+	return "header"
+}
+
+func (headerAuthenticator) Authenticate(r *http.Request) (*authentication.Identity, error) {
+	switch user := r.Header.Get("X-User"); user {
+	case "":
+		return nil, nil
+	case "invalid":
+		return nil, fmt.Errorf("invalid user")
+	default:
+		return &authentication.Identity{User: user, Method: "header"}, nil
+	}
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	var user string
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		user = ""
+		if id := authentication.FromContext(ctx); id != nil {
+			user = id.User
+		}
+		return nil
+	}
+
+	h := NewAuthenticationMiddleware([]authentication.Authenticator{headerAuthenticator{}})(handler)
+
+	req, _ := http.NewRequest("POST", "/containers/web/start", nil)
+	req.Header.Set("X-User", "alice")
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": "web"}); err != nil {
+		t.Fatal(err)
+	}
+	if user != "alice" {
+		t.Fatalf("Expected user alice, got %q", user)
+	}
+
+	// Anonymous requests are served without identity
+	req, _ = http.NewRequest("GET", "/info", nil)
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if user != "" {
+		t.Fatalf("Expected no user, got %q", user)
+	}
+
+	req.Header.Set("X-User", "invalid")
+	err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{})
+	if err == nil {
+		t.Fatal("Expected invalid credentials to be rejected")
+	}
+	if sc, ok := err.(interface {
+		HTTPErrorStatusCode() int
+	}); !ok || sc.HTTPErrorStatusCode() != http.StatusUnauthorized {
+		t.Fatalf("Expected an unauthorized error, got %v", err)
+	}
+}
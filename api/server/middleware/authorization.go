@@ -5,6 +5,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/pkg/authentication"
 	"github.com/docker/docker/pkg/authorization"
 	"golang.org/x/net/context"
 )
@@ -13,12 +14,18 @@ import (
 func NewAuthorizationMiddleware(plugins []authorization.Plugin) Middleware {
 	return func(handler httputils.APIFunc) httputils.APIFunc {
 		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
-			// FIXME: fill when authN gets in
-			// User and UserAuthNMethod are taken from AuthN plugins
-			// Currently tracked in https://github.com/docker/docker/pull/13994
-			user := ""
-			userAuthNMethod := ""
-			authCtx := authorization.NewCtx(plugins, user, userAuthNMethod, r.Method, r.RequestURI)
+			// User, groups and UserAuthNMethod are filled by the
+			// authentication middleware, they are empty for anonymous
+			// requests.
+			var (
+				user            string
+				userGroups      []string
+				userAuthNMethod string
+			)
+			if id := authentication.FromContext(ctx); id != nil {
+				user, userGroups, userAuthNMethod = id.User, id.Groups, id.Method
+			}
+			authCtx := authorization.NewCtx(plugins, user, userGroups, userAuthNMethod, r.Method, r.RequestURI)
 
 			if err := authCtx.AuthZRequest(w, r); err != nil {
 				logrus.Errorf("AuthZRequest for %s %s returned error: %s", r.Method, r.RequestURI, err)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/server/httputils"
@@ -43,6 +44,9 @@ func DebugRequestMiddleware(handler httputils.APIFunc) httputils.APIFunc {
 			if _, exists := postForm["password"]; exists {
 				postForm["password"] = "*****"
 			}
+			if _, exists := postForm["Data"]; exists && strings.HasSuffix(r.URL.Path, "/secrets/create") {
+				postForm["Data"] = "*****"
+			}
 			formStr, errMarshal := json.Marshal(postForm)
 			if errMarshal == nil {
 				logrus.Debugf("form data: %s", string(formStr))
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/errors"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/context"
+)
+
+// DefaultRestrictedRoutes are the routes allowed on the restricted listeners
+// when no allowlist is configured: listing and inspecting the objects, the
+// stats of the containers and the events.
+var DefaultRestrictedRoutes = []string{
+	"GET /_ping",
+	"GET /version",
+	"GET /info",
+	"GET /events",
+	"GET /containers/json",
+	"GET /containers/*/json",
+	"GET /containers/*/stats",
+	"GET /images/json",
+	"GET /images/*/json",
+	"GET /networks",
+	"GET /networks/*",
+	"GET /volumes",
+	"GET /volumes/*",
+}
+
+// RestrictedListener is a listener whose API requests are limited to an
+// allowlist of routes.
+type RestrictedListener interface {
+	net.Listener
+	// AllowedRoutes returns the allowed routes, methods and paths where
+	// `*` stands for a path parameter, like `GET /containers/*/json`.
+	AllowedRoutes() []string
+}
+
+// routeParam matches the parameters of the paths of the routes.
+var routeParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// routeKey returns the key of a route in an allowlist: its method and its
+// path, with its parameters replaced by `*`.
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + routeParam.ReplaceAllString(path, "*")
+}
+
+// ParseRoute validates a route of an allowlist and returns its key.
+func ParseRoute(route string) (string, error) {
+	fields := strings.Fields(route)
+	if len(fields) != 2 || !strings.HasPrefix(fields[1], "/") {
+		return "", fmt.Errorf("invalid route %q, expected a method and a path like \"GET /containers/*/json\"", route)
+	}
+	return routeKey(fields[0], fields[1]), nil
+}
+
+// createRestrictedMux initializes a router answering the routes of the
+// allowlist, the other routes being forbidden.
+func (s *Server) createRestrictedMux(allowedRoutes []string) *mux.Router {
+	allowed := make(map[string]bool)
+	for _, route := range allowedRoutes {
+		key, err := ParseRoute(route)
+		if err != nil {
+			logrus.Warn(err)
+			continue
+		}
+		allowed[key] = false
+	}
+
+	m := mux.NewRouter()
+	forbidden := s.makeHTTPHandler(forbiddenRoute)
+	for _, apiRouter := range s.routers {
+		for _, r := range apiRouter.Routes() {
+			f := forbidden
+			key := routeKey(r.Method(), r.Path())
+			if _, ok := allowed[key]; ok {
+				f = s.makeHTTPHandler(r.Handler())
+				allowed[key] = true
+			}
+			m.Path(versionMatcher + r.Path()).Methods(r.Method()).Handler(f)
+			m.Path(r.Path()).Methods(r.Method()).Handler(f)
+		}
+	}
+
+	for key, used := range allowed {
+		if !used {
+			logrus.Warnf("The allowed route %q of a restricted listener doesn't match any API route", key)
+		}
+	}
+	return m
+}
+
+func forbiddenRoute(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return errors.NewErrorWithStatusCode(fmt.Errorf("%s %s is not allowed on this listener", r.Method, r.URL.Path), http.StatusForbidden)
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/api/server/router"
+	"golang.org/x/net/context"
+)
+
+type testRouter struct {
+	routes []router.Route
+}
+
+func (r testRouter) Routes() []router.Route {
+	return r.routes
+}
+
+func TestParseRoute(t *testing.T) {
+	for route, expected := range map[string]string{
+		"GET /containers/json":               "GET /containers/json",
+		"get  /containers/*/json":            "GET /containers/*/json",
+		"GET /containers/{name:.*}/json":     "GET /containers/*/json",
+		"DELETE /networks/{id:.*}":           "DELETE /networks/*",
+		"HEAD /containers/{name:.*}/archive": "HEAD /containers/*/archive",
+	} {
+		key, err := ParseRoute(route)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %q: %v", route, err)
+		}
+		if key != expected {
+			t.Fatalf("Expected %q for %q, got %q", expected, route, key)
+		}
+	}
+
+	for _, route := range []string{"", "GET", "/containers/json", "GET containers/json", "GET /containers/json extra"} {
+		if _, err := ParseRoute(route); err == nil {
+			t.Fatalf("Expected an error parsing %q", route)
+		}
+	}
+}
+
+func TestRestrictedMux(t *testing.T) {
+	ok := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	srv := &Server{
+		cfg: &Config{},
+		routers: []router.Router{testRouter{[]router.Route{
+			router.NewGetRoute("/containers/json", ok),
+			router.NewGetRoute("/containers/{name:.*}/json", ok),
+			router.NewGetRoute("/containers/{name:.*}/logs", ok),
+			router.NewPostRoute("/containers/create", ok),
+		}}},
+	}
+	m := srv.createRestrictedMux(DefaultRestrictedRoutes)
+
+	for _, c := range []struct {
+		method, path string
+		status       int
+	}{
+		{"GET", "/containers/json", http.StatusOK},
+		{"GET", "/v1.23/containers/json", http.StatusOK},
+		{"GET", "/containers/foo/json", http.StatusOK},
+		{"GET", "/containers/foo/logs", http.StatusForbidden},
+		{"POST", "/v1.23/containers/create", http.StatusForbidden},
+		{"GET", "/containers/create", http.StatusNotFound},
+	} {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		resp := httptest.NewRecorder()
+		m.ServeHTTP(resp, req)
+		if resp.Code != c.status {
+			t.Fatalf("Expected status %d for %s %s, got %d: %s", c.status, c.method, c.path, resp.Code, resp.Body.String())
+		}
+	}
+}
@@ -40,6 +40,8 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 	options.SuppressOutput = httputils.BoolValue(r, "q")
 	options.NoCache = httputils.BoolValue(r, "nocache")
 	options.ForceRemove = httputils.BoolValue(r, "forcerm")
+	options.Squash = httputils.BoolValue(r, "squash")
+	options.Platform = r.FormValue("platform")
 	options.MemorySwap = httputils.Int64ValueOrZero(r, "memswap")
 	options.Memory = httputils.Int64ValueOrZero(r, "memory")
 	options.CPUShares = httputils.Int64ValueOrZero(r, "cpushares")
@@ -171,7 +173,7 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		closeNotifier = notifier.CloseNotify()
 	}
 
-	imgID, err := br.backend.Build(buildOptions,
+	imgID, err := br.backend.Build(ctx, buildOptions,
 		builder.DockerIgnoreContext{ModifiableContext: context},
 		stdout, stderr, out,
 		closeNotifier)
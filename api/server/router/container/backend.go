@@ -9,14 +9,16 @@ import (
 	"github.com/docker/docker/pkg/version"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
+	"github.com/docker/go-connections/nat"
+	"golang.org/x/net/context"
 )
 
 // execBackend includes functions to implement to provide exec functionality.
 type execBackend interface {
-	ContainerExecCreate(config *types.ExecConfig) (string, error)
+	ContainerExecCreate(ctx context.Context, config *types.ExecConfig) (string, error)
 	ContainerExecInspect(id string) (*backend.ExecInspect, error)
 	ContainerExecResize(name string, height, width int) error
-	ContainerExecStart(name string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error
+	ContainerExecStart(ctx context.Context, name string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error
 	ExecExists(name string) (bool, error)
 }
 
@@ -25,23 +27,25 @@ type copyBackend interface {
 	ContainerArchivePath(name string, path string) (content io.ReadCloser, stat *types.ContainerPathStat, err error)
 	ContainerCopy(name string, res string) (io.ReadCloser, error)
 	ContainerExport(name string, out io.Writer) error
-	ContainerExtractToDir(name, path string, noOverwriteDirNonDir bool, content io.Reader) error
+	ContainerExtractToDir(ctx context.Context, name, path string, noOverwriteDirNonDir bool, content io.Reader) error
 	ContainerStatPath(name string, path string) (stat *types.ContainerPathStat, err error)
 }
 
 // stateBackend includes functions to implement to provide container state lifecycle functionality.
 type stateBackend interface {
-	ContainerCreate(types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
-	ContainerKill(name string, sig uint64) error
-	ContainerPause(name string) error
-	ContainerRename(oldName, newName string) error
-	ContainerResize(name string, height, width int) error
-	ContainerRestart(name string, seconds int) error
-	ContainerRm(name string, config *types.ContainerRmConfig) error
-	ContainerStart(name string, hostConfig *container.HostConfig) error
-	ContainerStop(name string, seconds int) error
-	ContainerUnpause(name string) error
-	ContainerUpdate(name string, hostConfig *container.HostConfig) ([]string, error)
+	ContainerCreate(ctx context.Context, config types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
+	ContainerKill(ctx context.Context, name string, sig uint64) error
+	ContainerPause(ctx context.Context, name string) error
+	ContainerPublish(name string, portBindings nat.PortMap) error
+	ContainerRename(ctx context.Context, oldName, newName string) error
+	ContainerResize(ctx context.Context, name string, height, width int) error
+	ContainerRestart(ctx context.Context, name string, seconds *int) error
+	ContainerRm(ctx context.Context, name string, config *types.ContainerRmConfig) error
+	ContainerStart(ctx context.Context, name string, hostConfig *container.HostConfig) error
+	ContainerStop(ctx context.Context, name string, seconds *int) error
+	ContainerUnpause(ctx context.Context, name string) error
+	ContainerUnpublish(name string, portBindings nat.PortMap) error
+	ContainerUpdate(ctx context.Context, name string, hostConfig *container.HostConfig) ([]string, error)
 	ContainerWait(name string, timeout time.Duration) (int, error)
 }
 
@@ -157,7 +157,7 @@ func (s *containerRouter) postContainersStart(ctx context.Context, w http.Respon
 		hostConfig = c
 	}
 
-	if err := s.backend.ContainerStart(vars["name"], hostConfig); err != nil {
+	if err := s.backend.ContainerStart(ctx, vars["name"], hostConfig); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -169,9 +169,16 @@ func (s *containerRouter) postContainersStop(ctx context.Context, w http.Respons
 		return err
 	}
 
-	seconds, _ := strconv.Atoi(r.Form.Get("t"))
+	var seconds *int
+	if tmpSeconds := r.Form.Get("t"); tmpSeconds != "" {
+		valSeconds, err := strconv.Atoi(tmpSeconds)
+		if err != nil {
+			return err
+		}
+		seconds = &valSeconds
+	}
 
-	if err := s.backend.ContainerStop(vars["name"], seconds); err != nil {
+	if err := s.backend.ContainerStop(ctx, vars["name"], seconds); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -199,7 +206,7 @@ func (s *containerRouter) postContainersKill(ctx context.Context, w http.Respons
 		}
 	}
 
-	if err := s.backend.ContainerKill(name, uint64(sig)); err != nil {
+	if err := s.backend.ContainerKill(ctx, name, uint64(sig)); err != nil {
 		var isStopped bool
 		if e, ok := err.(errContainerIsRunning); ok {
 			isStopped = !e.ContainerIsRunning()
@@ -223,9 +230,16 @@ func (s *containerRouter) postContainersRestart(ctx context.Context, w http.Resp
 		return err
 	}
 
-	timeout, _ := strconv.Atoi(r.Form.Get("t"))
+	var timeout *int
+	if tmpTimeout := r.Form.Get("t"); tmpTimeout != "" {
+		valTimeout, err := strconv.Atoi(tmpTimeout)
+		if err != nil {
+			return err
+		}
+		timeout = &valTimeout
+	}
 
-	if err := s.backend.ContainerRestart(vars["name"], timeout); err != nil {
+	if err := s.backend.ContainerRestart(ctx, vars["name"], timeout); err != nil {
 		return err
 	}
 
@@ -239,7 +253,7 @@ func (s *containerRouter) postContainersPause(ctx context.Context, w http.Respon
 		return err
 	}
 
-	if err := s.backend.ContainerPause(vars["name"]); err != nil {
+	if err := s.backend.ContainerPause(ctx, vars["name"]); err != nil {
 		return err
 	}
 
@@ -253,7 +267,7 @@ func (s *containerRouter) postContainersUnpause(ctx context.Context, w http.Resp
 		return err
 	}
 
-	if err := s.backend.ContainerUnpause(vars["name"]); err != nil {
+	if err := s.backend.ContainerUnpause(ctx, vars["name"]); err != nil {
 		return err
 	}
 
@@ -302,7 +316,7 @@ func (s *containerRouter) postContainerRename(ctx context.Context, w http.Respon
 
 	name := vars["name"]
 	newName := r.Form.Get("name")
-	if err := s.backend.ContainerRename(name, newName); err != nil {
+	if err := s.backend.ContainerRename(ctx, name, newName); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -330,7 +344,7 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 	}
 
 	name := vars["name"]
-	warnings, err := s.backend.ContainerUpdate(name, hostConfig)
+	warnings, err := s.backend.ContainerUpdate(ctx, name, hostConfig)
 	if err != nil {
 		return err
 	}
@@ -357,7 +371,7 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 	version := httputils.VersionFromContext(ctx)
 	adjustCPUShares := version.LessThan("1.19")
 
-	ccr, err := s.backend.ContainerCreate(types.ContainerCreateConfig{
+	ccr, err := s.backend.ContainerCreate(ctx, types.ContainerCreateConfig{
 		Name:             name,
 		Config:           config,
 		HostConfig:       hostConfig,
@@ -383,7 +397,7 @@ func (s *containerRouter) deleteContainers(ctx context.Context, w http.ResponseW
 		RemoveLink:   httputils.BoolValue(r, "link"),
 	}
 
-	if err := s.backend.ContainerRm(name, config); err != nil {
+	if err := s.backend.ContainerRm(ctx, name, config); err != nil {
 		// Force a 404 for the empty string
 		if strings.Contains(strings.ToLower(err.Error()), "prefix can't be empty") {
 			return fmt.Errorf("no such container: \"\"")
@@ -410,7 +424,7 @@ func (s *containerRouter) postContainersResize(ctx context.Context, w http.Respo
 		return err
 	}
 
-	return s.backend.ContainerResize(vars["name"], height, width)
+	return s.backend.ContainerResize(ctx, vars["name"], height, width)
 }
 
 func (s *containerRouter) postContainersAttach(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -529,3 +543,44 @@ func (s *containerRouter) wsContainersAttach(ctx context.Context, w http.Respons
 	}
 	return err
 }
+
+func (s *containerRouter) postContainerPublish(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	portConfig, err := decodePortConfig(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.backend.ContainerPublish(vars["name"], portConfig.PortBindings); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (s *containerRouter) postContainerUnpublish(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	portConfig, err := decodePortConfig(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.backend.ContainerUnpublish(vars["name"], portConfig.PortBindings); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func decodePortConfig(r *http.Request) (*container.PortConfig, error) {
+	if err := httputils.ParseForm(r); err != nil {
+		return nil, err
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return nil, err
+	}
+
+	var portConfig container.PortConfig
+	if err := json.NewDecoder(r.Body).Decode(&portConfig); err != nil {
+		return nil, err
+	}
+	return &portConfig, nil
+}
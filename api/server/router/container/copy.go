@@ -108,5 +108,5 @@ func (s *containerRouter) putContainersArchive(ctx context.Context, w http.Respo
 	}
 
 	noOverwriteDirNonDir := httputils.BoolValue(r, "noOverwriteDirNonDir")
-	return s.backend.ContainerExtractToDir(v.Name, v.Path, noOverwriteDirNonDir, r.Body)
+	return s.backend.ContainerExtractToDir(ctx, v.Name, v.Path, noOverwriteDirNonDir, r.Body)
 }
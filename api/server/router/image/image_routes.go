@@ -63,7 +63,7 @@ func (s *imageRouter) postCommit(ctx context.Context, w http.ResponseWriter, r *
 		MergeConfigs: true,
 	}
 
-	imgID, err := s.backend.Commit(cname, commitCfg)
+	imgID, err := s.backend.Commit(ctx, cname, commitCfg)
 	if err != nil {
 		return err
 	}
@@ -80,12 +80,13 @@ func (s *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrite
 	}
 
 	var (
-		image   = r.Form.Get("fromImage")
-		repo    = r.Form.Get("repo")
-		tag     = r.Form.Get("tag")
-		message = r.Form.Get("message")
-		err     error
-		output  = ioutils.NewWriteFlusher(w)
+		image    = r.Form.Get("fromImage")
+		repo     = r.Form.Get("repo")
+		tag      = r.Form.Get("tag")
+		message  = r.Form.Get("message")
+		platform = r.Form.Get("platform")
+		err      error
+		output   = ioutils.NewWriteFlusher(w)
 	)
 	defer output.Close()
 
@@ -129,7 +130,7 @@ func (s *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrite
 					}
 				}
 
-				err = s.backend.PullImage(ref, metaHeaders, authConfig, output)
+				err = s.backend.PullImage(ref, platform, metaHeaders, authConfig, output)
 			}
 		}
 		// Check the error from pulling an image to make sure the request
@@ -170,7 +171,7 @@ func (s *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrite
 			return err
 		}
 
-		err = s.backend.ImportImage(src, newRef, message, r.Body, output, newConfig)
+		err = s.backend.ImportImage(ctx, src, newRef, message, r.Body, output, newConfig)
 	}
 	if err != nil {
 		if !output.Flushed() {
@@ -254,7 +255,7 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	if err := s.backend.ExportImage(names, r.Form["excludebase"], output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
@@ -284,10 +285,18 @@ func (s *imageRouter) deleteImages(ctx context.Context, w http.ResponseWriter, r
 		return fmt.Errorf("image name cannot be blank")
 	}
 
-	force := httputils.BoolValue(r, "force")
-	prune := !httputils.BoolValue(r, "noprune")
+	var (
+		list []types.ImageDelete
+		err  error
+	)
+	if httputils.BoolValue(r, "remote") {
+		list, err = s.deleteRemoteImage(r, name)
+	} else {
+		force := httputils.BoolValue(r, "force")
+		prune := !httputils.BoolValue(r, "noprune")
 
-	list, err := s.backend.ImageDelete(name, force, prune)
+		list, err = s.backend.ImageDelete(ctx, name, force, prune)
+	}
 	if err != nil {
 		return err
 	}
@@ -295,6 +304,33 @@ func (s *imageRouter) deleteImages(ctx context.Context, w http.ResponseWriter, r
 	return httputils.WriteJSON(w, http.StatusOK, list)
 }
 
+// deleteRemoteImage deletes the manifest that name points to from its
+// registry, using the credentials in the X-Registry-Auth header.
+func (s *imageRouter) deleteRemoteImage(r *http.Request, name string) ([]types.ImageDelete, error) {
+	metaHeaders := map[string][]string{}
+	for k, v := range r.Header {
+		if strings.HasPrefix(k, "X-Meta-") {
+			metaHeaders[k] = v
+		}
+	}
+
+	authConfig := &types.AuthConfig{}
+	if authEncoded := r.Header.Get("X-Registry-Auth"); authEncoded != "" {
+		authJSON := base64.NewDecoder(base64.URLEncoding, strings.NewReader(authEncoded))
+		if err := json.NewDecoder(authJSON).Decode(authConfig); err != nil {
+			// for a delete it is not an error if no auth was given
+			// to increase compatibility with the existing api it is defaulting to be empty
+			authConfig = &types.AuthConfig{}
+		}
+	}
+
+	ref, err := reference.ParseNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.backend.ImageDeleteRemote(ref, metaHeaders, authConfig, httputils.BoolValue(r, "dryrun"))
+}
+
 func (s *imageRouter) getImagesByName(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	imageInspect, err := s.backend.LookupImage(vars["name"])
 	if err != nil {
@@ -343,7 +379,7 @@ func (s *imageRouter) postImagesTag(ctx context.Context, w http.ResponseWriter,
 			return err
 		}
 	}
-	if err := s.backend.TagImage(newTag, vars["name"]); err != nil {
+	if err := s.backend.TagImage(ctx, newTag, vars["name"]); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -3,6 +3,7 @@ package network
 import (
 	"github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 // Backend is all the methods that need to be implemented
@@ -12,8 +13,8 @@ type Backend interface {
 	GetNetworkByName(idName string) (libnetwork.Network, error)
 	GetNetworksByID(partialID string) []libnetwork.Network
 	GetAllNetworks() []libnetwork.Network
-	CreateNetwork(name, driver string, ipam network.IPAM, options map[string]string, internal bool, enableIPv6 bool) (libnetwork.Network, error)
-	ConnectContainerToNetwork(containerName, networkName string, endpointConfig *network.EndpointSettings) error
-	DisconnectContainerFromNetwork(containerName string, network libnetwork.Network, force bool) error
-	DeleteNetwork(name string) error
+	CreateNetwork(ctx context.Context, name, driver string, ipam network.IPAM, options map[string]string, labels map[string]string, internal bool, enableIPv6 bool, egressPolicy *network.EgressPolicy) (libnetwork.Network, error)
+	ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, endpointConfig *network.EndpointSettings) error
+	DisconnectContainerFromNetwork(ctx context.Context, containerName string, network libnetwork.Network, force bool) error
+	DeleteNetwork(ctx context.Context, name string) error
 }
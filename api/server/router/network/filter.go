@@ -23,15 +23,17 @@ var (
 	// acceptFilters is an acceptable filter flag list
 	// generated for validation. e.g.
 	// acceptedFilters = map[string]bool{
-	//     "type": true,
-	//     "name": true,
-	//     "id":   true,
+	//     "type":  true,
+	//     "name":  true,
+	//     "id":    true,
+	//     "label": true,
 	// }
 	acceptedFilters = func() map[string]bool {
 		ret := make(map[string]bool)
 		for k := range supportedFilters {
 			ret[k] = true
 		}
+		ret["label"] = true
 		return ret
 	}()
 )
@@ -84,6 +86,17 @@ func filterNetworkByID(nws []libnetwork.Network, id string) (retNws []libnetwork
 	return retNws, nil
 }
 
+// filterNetworksByLabel returns the networks that have all of the labels
+// given in the label filter, either as `key` or as `key=value`.
+func filterNetworksByLabel(nws []libnetwork.Network, filter filters.Args) (retNws []libnetwork.Network) {
+	for _, nw := range nws {
+		if filter.MatchKVList("label", nw.Info().Labels()) {
+			retNws = append(retNws, nw)
+		}
+	}
+	return retNws
+}
+
 // filterAllNetworks filters network list according to user specified filter
 // and returns user chosen networks
 func filterNetworks(nws []libnetwork.Network, filter filters.Args) ([]libnetwork.Network, error) {
@@ -92,6 +105,15 @@ func filterNetworks(nws []libnetwork.Network, filter filters.Args) ([]libnetwork
 		return nws, nil
 	}
 
+	// unlike the other filters, labels narrow down the list: a network
+	// must have all of the given labels to be displayed
+	if filter.Include("label") {
+		nws = filterNetworksByLabel(nws, filter)
+		if filter.Len() == 1 {
+			return nws, nil
+		}
+	}
+
 	var displayNet []libnetwork.Network
 	for fkey, fhandler := range supportedFilters {
 		errFilter := filter.WalkValues(fkey, func(fval string) error {
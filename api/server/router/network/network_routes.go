@@ -91,7 +91,7 @@ func (n *networkRouter) postNetworkCreate(ctx context.Context, w http.ResponseWr
 		warning = fmt.Sprintf("Network with name %s (id : %s) already exists", nw.Name(), nw.ID())
 	}
 
-	nw, err = n.backend.CreateNetwork(create.Name, create.Driver, create.IPAM, create.Options, create.Internal, create.EnableIPv6)
+	nw, err = n.backend.CreateNetwork(ctx, create.Name, create.Driver, create.IPAM, create.Options, create.Labels, create.Internal, create.EnableIPv6, create.EgressPolicy)
 	if err != nil {
 		return err
 	}
@@ -121,7 +121,7 @@ func (n *networkRouter) postNetworkConnect(ctx context.Context, w http.ResponseW
 		return err
 	}
 
-	return n.backend.ConnectContainerToNetwork(connect.Container, nw.Name(), connect.EndpointConfig)
+	return n.backend.ConnectContainerToNetwork(ctx, connect.Container, nw.Name(), connect.EndpointConfig)
 }
 
 func (n *networkRouter) postNetworkDisconnect(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -143,14 +143,14 @@ func (n *networkRouter) postNetworkDisconnect(ctx context.Context, w http.Respon
 		return err
 	}
 
-	return n.backend.DisconnectContainerFromNetwork(disconnect.Container, nw, disconnect.Force)
+	return n.backend.DisconnectContainerFromNetwork(ctx, disconnect.Container, nw, disconnect.Force)
 }
 
 func (n *networkRouter) deleteNetwork(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
 	}
-	if err := n.backend.DeleteNetwork(vars["id"]); err != nil {
+	if err := n.backend.DeleteNetwork(ctx, vars["id"]); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -170,6 +170,8 @@ func buildNetworkResource(nw libnetwork.Network) *types.NetworkResource {
 	r.EnableIPv6 = nw.Info().IPv6Enabled()
 	r.Internal = nw.Info().Internal()
 	r.Options = nw.Info().DriverOptions()
+	r.Labels = nw.Info().Labels()
+	r.EgressPolicy = buildEgressPolicy(nw)
 	r.Containers = make(map[string]types.EndpointResource)
 	buildIpamResources(r, nw)
 	r.Internal = nw.Info().Internal()
@@ -191,6 +193,23 @@ func buildNetworkResource(nw libnetwork.Network) *types.NetworkResource {
 	return r
 }
 
+func buildEgressPolicy(nw libnetwork.Network) *network.EgressPolicy {
+	policy := nw.Info().EgressPolicy()
+	if policy == nil {
+		return nil
+	}
+	r := &network.EgressPolicy{Default: policy.Default}
+	for _, rule := range policy.Rules {
+		r.Rules = append(r.Rules, network.EgressRule{
+			Action:   rule.Action,
+			CIDR:     rule.CIDR,
+			Protocol: rule.Protocol,
+			Port:     rule.Port,
+		})
+	}
+	return r
+}
+
 func buildIpamResources(r *types.NetworkResource, nw libnetwork.Network) {
 	id, opts, ipv4conf, ipv6conf := nw.Info().IpamConfig()
 
@@ -0,0 +1,11 @@
+package secret
+
+import "github.com/docker/engine-api/types"
+
+// Backend is the methods that need to be implemented to provide
+// secret specific functionality
+type Backend interface {
+	Secrets() ([]*types.Secret, error)
+	SecretCreate(req types.SecretCreateRequest) (*types.Secret, error)
+	SecretRm(name string) error
+}
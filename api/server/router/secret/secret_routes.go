@@ -0,0 +1,50 @@
+package secret
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+func (s *secretRouter) getSecretsList(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	secrets, err := s.backend.Secrets()
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, secrets)
+}
+
+func (s *secretRouter) postSecretsCreate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var req types.SecretCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+
+	secret, err := s.backend.SecretCreate(req)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, secret)
+}
+
+func (s *secretRouter) deleteSecrets(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := s.backend.SecretRm(vars["name"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -3,6 +3,7 @@ package volume
 import (
 	// TODO return types need to be refactored into pkg
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 // Backend is the methods that need to be implemented to provide
@@ -10,7 +11,7 @@ import (
 type Backend interface {
 	Volumes(filter string) ([]*types.Volume, []string, error)
 	VolumeInspect(name string) (*types.Volume, error)
-	VolumeCreate(name, driverName string,
+	VolumeCreate(ctx context.Context, name, driverName string,
 		opts map[string]string) (*types.Volume, error)
-	VolumeRm(name string) error
+	VolumeRm(ctx context.Context, name string) error
 }
@@ -47,7 +47,7 @@ func (v *volumeRouter) postVolumesCreate(ctx context.Context, w http.ResponseWri
 		return err
 	}
 
-	volume, err := v.backend.VolumeCreate(req.Name, req.Driver, req.DriverOpts)
+	volume, err := v.backend.VolumeCreate(ctx, req.Name, req.Driver, req.DriverOpts)
 	if err != nil {
 		return err
 	}
@@ -58,7 +58,7 @@ func (v *volumeRouter) deleteVolumes(ctx context.Context, w http.ResponseWriter,
 	if err := httputils.ParseForm(r); err != nil {
 		return err
 	}
-	if err := v.backend.VolumeRm(vars["name"]); err != nil {
+	if err := v.backend.VolumeRm(ctx, vars["name"]); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -9,6 +9,8 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/server/router"
+	"github.com/docker/docker/pkg/audit"
+	"github.com/docker/docker/pkg/authentication"
 	"github.com/docker/docker/pkg/authorization"
 	"github.com/gorilla/mux"
 	"golang.org/x/net/context"
@@ -24,6 +26,10 @@ type Config struct {
 	EnableCors               bool
 	CorsHeaders              string
 	AuthorizationPluginNames []string
+	Authorizers              []authorization.Plugin
+	Authenticators           []authentication.Authenticator
+	AuditLogger              *audit.Logger
+	AuditResolver            audit.Resolver
 	Version                  string
 	SocketGroup              string
 	TLSConfig                *tls.Config
@@ -46,7 +52,8 @@ func New(cfg *Config) *Server {
 	}
 }
 
-// Accept sets a listener the server accepts connections into.
+// Accept sets a listener the server accepts connections into. The requests
+// of a RestrictedListener are limited to its allowed routes.
 func (s *Server) Accept(addr string, listeners ...net.Listener) {
 	for _, listener := range listeners {
 		httpServer := &HTTPServer{
@@ -55,6 +62,10 @@ func (s *Server) Accept(addr string, listeners ...net.Listener) {
 			},
 			l: listener,
 		}
+		if rl, ok := listener.(RestrictedListener); ok {
+			httpServer.restricted = true
+			httpServer.allowedRoutes = rl.AllowedRoutes()
+		}
 		s.servers = append(s.servers, httpServer)
 	}
 }
@@ -74,6 +85,9 @@ func (s *Server) serveAPI() error {
 	var chErrors = make(chan error, len(s.servers))
 	for _, srv := range s.servers {
 		srv.srv.Handler = s.routerSwapper
+		if srv.restricted {
+			srv.srv.Handler = s.createRestrictedMux(srv.allowedRoutes)
+		}
 		go func(srv *HTTPServer) {
 			var err error
 			logrus.Infof("API listen on %s", srv.l.Addr())
@@ -97,9 +111,12 @@ func (s *Server) serveAPI() error {
 // HTTPServer contains an instance of http server and the listener.
 // srv *http.Server, contains configuration to create a http server and a mux router with all api end points.
 // l   net.Listener, is a TCP or Socket listener that dispatches incoming request to the router.
+// restricted and allowedRoutes limit the requests of a RestrictedListener to an allowlist of routes.
 type HTTPServer struct {
-	srv *http.Server
-	l   net.Listener
+	srv           *http.Server
+	l             net.Listener
+	restricted    bool
+	allowedRoutes []string
 }
 
 // Serve starts listening for inbound requests.
@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 const (
@@ -107,21 +108,24 @@ type Backend interface {
 	// GetImage looks up a Docker image referenced by `name`.
 	GetImageOnBuild(name string) (Image, error)
 	// Tag an image with newTag
-	TagImage(newTag reference.Named, imageName string) error
-	// Pull tells Docker to pull image referenced by `name`.
-	PullOnBuild(name string, authConfigs map[string]types.AuthConfig, output io.Writer) (Image, error)
+	TagImage(ctx context.Context, newTag reference.Named, imageName string) error
+	// Pull tells Docker to pull image referenced by `name` for `platform`.
+	PullOnBuild(name string, authConfigs map[string]types.AuthConfig, platform string, output io.Writer) (Image, error)
 	// ContainerAttach attaches to container.
 	ContainerAttachRaw(cID string, stdin io.ReadCloser, stdout, stderr io.Writer, stream bool) error
 	// ContainerCreate creates a new Docker container and returns potential warnings
-	ContainerCreate(types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
+	ContainerCreate(ctx context.Context, config types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
 	// ContainerRm removes a container specified by `id`.
-	ContainerRm(name string, config *types.ContainerRmConfig) error
+	ContainerRm(ctx context.Context, name string, config *types.ContainerRmConfig) error
 	// Commit creates a new Docker image from an existing Docker container.
-	Commit(string, *types.ContainerCommitConfig) (string, error)
+	Commit(ctx context.Context, name string, config *types.ContainerCommitConfig) (string, error)
+	// SquashImage creates a new image from `imageID` with all layers on top
+	// of `parentID` merged into a single layer.
+	SquashImage(imageID, parentID string) (string, error)
 	// Kill stops the container execution abruptly.
-	ContainerKill(containerID string, sig uint64) error
+	ContainerKill(ctx context.Context, containerID string, sig uint64) error
 	// Start starts a new container
-	ContainerStart(containerID string, hostConfig *container.HostConfig) error
+	ContainerStart(ctx context.Context, containerID string, hostConfig *container.HostConfig) error
 	// ContainerWait stops processing until the given container is stopped.
 	ContainerWait(containerID string, timeout time.Duration) (int, error)
 	// ContainerUpdateCmd updates container.Path and container.Args
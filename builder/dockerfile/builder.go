@@ -17,6 +17,7 @@ import (
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 var validCommitCommands = map[string]bool{
@@ -54,12 +55,16 @@ type Builder struct {
 
 	docker  builder.Backend
 	context builder.Context
+	// clientCtx is the context of the build request, the changes made by
+	// the build are attributed to its caller
+	clientCtx context.Context
 
 	dockerfile       *parser.Node
 	runConfig        *container.Config // runconfig for cmd, run, entrypoint etc.
 	flags            *BFlags
 	tmpContainers    map[string]struct{}
 	image            string // imageID
+	fromImage        string // imageID of the FROM image, the base when squashing
 	noBaseImage      bool
 	maintainer       string
 	cmdSet           bool
@@ -86,7 +91,7 @@ func NewBuildManager(b builder.Backend) (bm *BuildManager) {
 // NewBuilder creates a new Dockerfile builder from an optional dockerfile and a Config.
 // If dockerfile is nil, the Dockerfile specified by Config.DockerfileName,
 // will be read from the Context passed to Build().
-func NewBuilder(config *types.ImageBuildOptions, backend builder.Backend, context builder.Context, dockerfile io.ReadCloser) (b *Builder, err error) {
+func NewBuilder(config *types.ImageBuildOptions, backend builder.Backend, buildContext builder.Context, dockerfile io.ReadCloser) (b *Builder, err error) {
 	if config == nil {
 		config = new(types.ImageBuildOptions)
 	}
@@ -98,7 +103,8 @@ func NewBuilder(config *types.ImageBuildOptions, backend builder.Backend, contex
 		Stdout:           os.Stdout,
 		Stderr:           os.Stderr,
 		docker:           backend,
-		context:          context,
+		context:          buildContext,
+		clientCtx:        context.Background(),
 		runConfig:        new(container.Config),
 		tmpContainers:    map[string]struct{}{},
 		cancelled:        make(chan struct{}),
@@ -155,11 +161,12 @@ func sanitizeRepoAndTags(names []string) ([]reference.Named, error) {
 }
 
 // Build creates a NewBuilder, which builds the image.
-func (bm *BuildManager) Build(config *types.ImageBuildOptions, context builder.Context, stdout io.Writer, stderr io.Writer, out io.Writer, clientGone <-chan bool) (string, error) {
+func (bm *BuildManager) Build(clientCtx context.Context, config *types.ImageBuildOptions, context builder.Context, stdout io.Writer, stderr io.Writer, out io.Writer, clientGone <-chan bool) (string, error) {
 	b, err := NewBuilder(config, bm.backend, context, nil)
 	if err != nil {
 		return "", err
 	}
+	b.clientCtx = clientCtx
 	img, err := b.build(config, context, stdout, stderr, out, clientGone)
 	return img, err
 
@@ -249,8 +256,15 @@ func (b *Builder) build(config *types.ImageBuildOptions, context builder.Context
 		return "", fmt.Errorf("No image was generated. Is your Dockerfile empty?")
 	}
 
+	if b.options.Squash {
+		if b.image, err = b.docker.SquashImage(b.image, b.fromImage); err != nil {
+			return "", err
+		}
+		shortImgID = stringid.TruncateID(b.image)
+	}
+
 	for _, rt := range repoAndTags {
-		if err := b.docker.TagImage(rt, b.image); err != nil {
+		if err := b.docker.TagImage(b.clientCtx, rt, b.image); err != nil {
 			return "", err
 		}
 	}
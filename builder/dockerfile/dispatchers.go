@@ -206,12 +206,14 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 		b.noBaseImage = true
 	} else {
 		// TODO: don't use `name`, instead resolve it to a digest
-		if !b.options.PullParent {
+		// A local image may be for another platform, so always pull
+		// if a platform is requested.
+		if !b.options.PullParent && b.options.Platform == "" {
 			image, err = b.docker.GetImageOnBuild(name)
 			// TODO: shouldn't we error out if error is different from "not found" ?
 		}
 		if image == nil {
-			image, err = b.docker.PullOnBuild(name, b.options.AuthConfigs, b.Output)
+			image, err = b.docker.PullOnBuild(name, b.options.AuthConfigs, b.options.Platform, b.Output)
 			if err != nil {
 				return err
 			}
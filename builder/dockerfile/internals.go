@@ -77,7 +77,7 @@ func (b *Builder) commit(id string, autoCmd strslice.StrSlice, comment string) e
 	}
 
 	// Commit the container
-	imageID, err := b.docker.Commit(id, commitCfg)
+	imageID, err := b.docker.Commit(b.clientCtx, id, commitCfg)
 	if err != nil {
 		return err
 	}
@@ -183,7 +183,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 		return nil
 	}
 
-	container, err := b.docker.ContainerCreate(types.ContainerCreateConfig{Config: b.runConfig})
+	container, err := b.docker.ContainerCreate(b.clientCtx, types.ContainerCreateConfig{Config: b.runConfig})
 	if err != nil {
 		return err
 	}
@@ -401,6 +401,7 @@ func (b *Builder) processImageFrom(img builder.Image) error {
 			b.runConfig = img.RunConfig()
 		}
 	}
+	b.fromImage = b.image
 
 	// Check to see if we have a default PATH, note that windows won't
 	// have one as its set by HCS
@@ -513,7 +514,7 @@ func (b *Builder) create() (string, error) {
 	config := *b.runConfig
 
 	// Create the container
-	c, err := b.docker.ContainerCreate(types.ContainerCreateConfig{
+	c, err := b.docker.ContainerCreate(b.clientCtx, types.ContainerCreateConfig{
 		Config:     b.runConfig,
 		HostConfig: hostConfig,
 	})
@@ -549,13 +550,13 @@ func (b *Builder) run(cID string) (err error) {
 		select {
 		case <-b.cancelled:
 			logrus.Debugln("Build cancelled, killing and removing container:", cID)
-			b.docker.ContainerKill(cID, 0)
+			b.docker.ContainerKill(b.clientCtx, cID, 0)
 			b.removeContainer(cID)
 		case <-finished:
 		}
 	}()
 
-	if err := b.docker.ContainerStart(cID, nil); err != nil {
+	if err := b.docker.ContainerStart(b.clientCtx, cID, nil); err != nil {
 		return err
 	}
 
@@ -580,7 +581,7 @@ func (b *Builder) removeContainer(c string) error {
 		ForceRemove:  true,
 		RemoveVolume: true,
 	}
-	if err := b.docker.ContainerRm(c, rmConfig); err != nil {
+	if err := b.docker.ContainerRm(b.clientCtx, c, rmConfig); err != nil {
 		fmt.Fprintf(b.Stdout, "Error removing intermediate container %s: %v\n", stringid.TruncateID(c), err)
 		return err
 	}
@@ -38,7 +38,13 @@ import (
 	"github.com/opencontainers/runc/libcontainer/label"
 )
 
-const configFileName = "config.v2.json"
+const (
+	configFileName = "config.v2.json"
+
+	// DefaultStopTimeout is the timeout (in seconds) used to stop a
+	// container that doesn't set its own.
+	DefaultStopTimeout = 10
+)
 
 var (
 	errInvalidEndpoint = fmt.Errorf("invalid endpoint while building port map info")
@@ -571,6 +577,14 @@ func (container *Container) StopSignal() int {
 	return int(stopSignal)
 }
 
+// StopTimeout returns the timeout (in seconds) used to stop the container.
+func (container *Container) StopTimeout() int {
+	if container.Config.StopTimeout != nil {
+		return *container.Config.StopTimeout
+	}
+	return DefaultStopTimeout
+}
+
 // InitDNSHostConfig ensures that the dns fields are never nil.
 // New containers don't ever have those fields nil,
 // but pre created containers can still have those nil values.
@@ -621,6 +635,15 @@ func (container *Container) buildPortMapInfo(ep libnetwork.Endpoint) error {
 	return nil
 }
 
+// UpdatePortMapInfo refreshes the published ports in container.NetworkSettings
+// from the endpoints of the provided sandbox.
+func (container *Container) UpdatePortMapInfo(sb libnetwork.Sandbox) {
+	if container.NetworkSettings == nil {
+		return
+	}
+	container.NetworkSettings.Ports = getSandboxPortMapInfo(sb)
+}
+
 func getEndpointPortMapInfo(ep libnetwork.Endpoint) (nat.PortMap, error) {
 	pm := nat.PortMap{}
 	driverInfo, err := ep.DriverInfo()
@@ -765,6 +788,9 @@ func (container *Container) BuildJoinOptions(n libnetwork.Network) ([]libnetwork
 			}
 			joinOptions = append(joinOptions, libnetwork.CreateOptionAlias(name, alias))
 		}
+		if epConfig.IngressRate > 0 || epConfig.EgressRate > 0 {
+			joinOptions = append(joinOptions, libnetwork.JoinOptionBandwidth(epConfig.IngressRate, epConfig.EgressRate))
+		}
 	}
 	return joinOptions, nil
 }
@@ -794,6 +820,10 @@ func (container *Container) BuildCreateEndpointOptions(n libnetwork.Network, epC
 		for _, alias := range epConfig.Aliases {
 			createOptions = append(createOptions, libnetwork.CreateOptionMyAlias(alias))
 		}
+
+		if epConfig.DNSRRName != "" {
+			createOptions = append(createOptions, libnetwork.CreateOptionDNSRRName(epConfig.DNSRRName))
+		}
 	}
 
 	if !containertypes.NetworkMode(n.Name()).IsUserDefined() {
@@ -7,17 +7,21 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/symlink"
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/utils"
 	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 	"github.com/opencontainers/runc/libcontainer/label"
 )
 
@@ -37,6 +41,17 @@ type Container struct {
 	ResolvConfPath  string
 	SeccompProfile  string
 	NoNewPrivileges bool
+	// UIDMaps and GIDMaps are the ID mappings of the private user
+	// namespace of the container, allocated from the pool of the daemon.
+	UIDMaps []idtools.IDMap `json:",omitempty"`
+	GIDMaps []idtools.IDMap `json:",omitempty"`
+	// UsernsError is set when the ID range of the private user namespace
+	// couldn't be reserved when the container was restored, it can't be
+	// started then.
+	UsernsError error `json:"-"`
+	// LearnedSeccompProfile is the profile learned by the last run of the
+	// container with the `seccomp=learn` security option.
+	LearnedSeccompProfile *types.LearnedSeccompProfile `json:",omitempty"`
 }
 
 // CreateDaemonEnvironment returns the list of all environment variables given the list of
@@ -253,6 +268,9 @@ func updateCommand(c *execdriver.Command, resources containertypes.Resources) {
 	c.Resources.CPUShares = resources.CPUShares
 	c.Resources.CPUPeriod = resources.CPUPeriod
 	c.Resources.CPUQuota = resources.CPUQuota
+	c.Resources.CPURealtimePeriod = resources.CPURealtimePeriod
+	c.Resources.CPURealtimeRuntime = resources.CPURealtimeRuntime
+	c.Resources.NanoCPUs = resources.NanoCPUs
 	c.Resources.CpusetCpus = resources.CpusetCpus
 	c.Resources.CpusetMems = resources.CpusetMems
 	c.Resources.Memory = resources.Memory
@@ -268,6 +286,18 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	// update resources of container
 	resources := hostConfig.Resources
 	cResources := &container.HostConfig.Resources
+
+	// NanoCPUs sets the CFS period and quota itself, so it can't be
+	// updated on a container that sets them, and vice versa.
+	if resources.NanoCPUs > 0 && (cResources.CPUPeriod > 0 || cResources.CPUQuota > 0) {
+		container.Unlock()
+		return fmt.Errorf("Conflicting options: CPUs cannot be updated as CPU period or quota has already been set")
+	}
+	if (resources.CPUPeriod > 0 || resources.CPUQuota > 0) && cResources.NanoCPUs > 0 {
+		container.Unlock()
+		return fmt.Errorf("Conflicting options: CPU period or quota cannot be updated as CPUs has already been set")
+	}
+
 	if resources.BlkioWeight != 0 {
 		cResources.BlkioWeight = resources.BlkioWeight
 	}
@@ -280,6 +310,15 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	if resources.CPUQuota != 0 {
 		cResources.CPUQuota = resources.CPUQuota
 	}
+	if resources.CPURealtimePeriod != 0 {
+		cResources.CPURealtimePeriod = resources.CPURealtimePeriod
+	}
+	if resources.CPURealtimeRuntime != 0 {
+		cResources.CPURealtimeRuntime = resources.CPURealtimeRuntime
+	}
+	if resources.NanoCPUs != 0 {
+		cResources.NanoCPUs = resources.NanoCPUs
+	}
 	if resources.CpusetCpus != "" {
 		cResources.CpusetCpus = resources.CpusetCpus
 	}
@@ -408,6 +447,76 @@ func copyOwnership(source, destination string) error {
 	return os.Chmod(destination, os.FileMode(stat.Mode()))
 }
 
+// RootfsViewPath returns the path the root filesystem of a container with
+// a private user namespace is bind mounted to.
+func (container *Container) RootfsViewPath() (string, error) {
+	return container.GetRootResourcePath("rootfs")
+}
+
+// UnmountRootfsView uses the provided unmount function to unmount the view
+// of the root filesystem of a container with a private user namespace.
+func (container *Container) UnmountRootfsView(unmount func(pth string) error) {
+	if container.UIDMaps == nil {
+		return
+	}
+	viewPath, err := container.RootfsViewPath()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+	if err := unmount(viewPath); err != nil {
+		logrus.Warnf("failed to umount %s: %v", viewPath, err)
+	}
+}
+
+// SecretsResourcePath returns the path of the tmpfs holding the secrets of
+// the container.
+func (container *Container) SecretsResourcePath() (string, error) {
+	return container.GetRootResourcePath("secrets")
+}
+
+// SecretFilePath returns the path of the file holding the i-th secret of the
+// container on its secrets tmpfs.
+func (container *Container) SecretFilePath(i int) (string, error) {
+	return container.GetRootResourcePath(filepath.Join("secrets", strconv.Itoa(i)+"-"+container.HostConfig.Secrets[i].Name))
+}
+
+// SecretMounts returns the read-only bind mounts of the secret files of the
+// container.
+func (container *Container) SecretMounts() []execdriver.Mount {
+	var mounts []execdriver.Mount
+	for i, s := range container.HostConfig.Secrets {
+		// the path was checked when the secrets were set up
+		source, _ := container.SecretFilePath(i)
+		mounts = append(mounts, execdriver.Mount{
+			Source:      source,
+			Destination: s.Target,
+			Propagation: volume.DefaultPropagationMode,
+		})
+	}
+	return mounts
+}
+
+// UnmountSecrets uses the provided unmount function to unmount the secrets
+// tmpfs of the container and removes its mount point.
+func (container *Container) UnmountSecrets(unmount func(pth string) error) {
+	if len(container.HostConfig.Secrets) == 0 {
+		return
+	}
+	secretsPath, err := container.SecretsResourcePath()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+	if err := unmount(secretsPath); err != nil {
+		logrus.Warnf("failed to umount %s: %v", secretsPath, err)
+		return
+	}
+	if err := os.Remove(secretsPath); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("failed to remove %s: %v", secretsPath, err)
+	}
+}
+
 // TmpfsMounts returns the list of tmpfs mounts
 func (container *Container) TmpfsMounts() []execdriver.Mount {
 	var mounts []execdriver.Mount
@@ -418,6 +527,18 @@ func (container *Container) TmpfsMounts() []execdriver.Mount {
 			Data:        data,
 		})
 	}
+	for _, m := range container.HostConfig.Mounts {
+		if m.Type != mounttypes.TypeTmpfs {
+			continue
+		}
+		// the options were validated when the container was created
+		data, _ := volume.ConvertTmpfsOptions(m.TmpfsOptions, m.ReadOnly)
+		mounts = append(mounts, execdriver.Mount{
+			Source:      "tmpfs",
+			Destination: filepath.Clean(m.Target),
+			Data:        data,
+		})
+	}
 	return mounts
 }
 
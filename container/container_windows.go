@@ -39,6 +39,22 @@ func (container *Container) IpcMounts() []execdriver.Mount {
 	return nil
 }
 
+// SecretMounts returns the list of secret mounts.
+// This is a NOOP on windows.
+func (container *Container) SecretMounts() []execdriver.Mount {
+	return nil
+}
+
+// UnmountSecrets unmounts the secrets of the container.
+// This is a NOOP on windows.
+func (container *Container) UnmountSecrets(unmount func(pth string) error) {
+}
+
+// UnmountRootfsView unmounts the view of the root filesystem of the
+// container. This is a NOOP on windows.
+func (container *Container) UnmountRootfsView(unmount func(pth string) error) {
+}
+
 // UnmountVolumes explicitly unmounts volumes from the container.
 func (container *Container) UnmountVolumes(forceSyscall bool, volumeEventLog func(name, action string, attributes map[string]string)) error {
 	return nil
@@ -56,6 +72,7 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	resources := hostConfig.Resources
 	if resources.BlkioWeight != 0 || resources.CPUShares != 0 ||
 		resources.CPUPeriod != 0 || resources.CPUQuota != 0 ||
+		resources.NanoCPUs != 0 ||
 		resources.CpusetCpus != "" || resources.CpusetMems != "" ||
 		resources.Memory != 0 || resources.MemorySwap != 0 ||
 		resources.MemoryReservation != 0 || resources.KernelMemory != 0 {
@@ -25,6 +25,9 @@ const (
 type supervisor interface {
 	// LogContainerEvent generates events related to a given container
 	LogContainerEvent(*Container, string)
+	// LogContainerEventWithAttributes generates events related to a given
+	// container with specific given attributes
+	LogContainerEventWithAttributes(*Container, string, map[string]string)
 	// Cleanup ensures that the container is properly unmounted
 	Cleanup(*Container)
 	// StartLogging starts the logging driver for the container
@@ -74,18 +77,23 @@ type containerMonitor struct {
 
 	// lastStartTime is the time which the monitor last exec'd the container's process
 	lastStartTime time.Time
+
+	// startAttributes are the attributes of the event of the initial start
+	// of the container, the restarts are not attributed to its caller
+	startAttributes map[string]string
 }
 
 // StartMonitor initializes a containerMonitor for this container with the provided supervisor and restart policy
-// and starts the container's process.
-func (container *Container) StartMonitor(s supervisor) error {
+// and starts the container's process. The initial start event carries the given attributes.
+func (container *Container) StartMonitor(s supervisor, startAttributes map[string]string) error {
 	container.monitor = &containerMonitor{
-		supervisor:    s,
-		container:     container,
-		restartPolicy: container.HostConfig.RestartPolicy,
-		timeIncrement: defaultTimeIncrement,
-		stopChan:      make(chan struct{}),
-		startSignal:   make(chan struct{}),
+		supervisor:      s,
+		container:       container,
+		restartPolicy:   container.HostConfig.RestartPolicy,
+		timeIncrement:   defaultTimeIncrement,
+		stopChan:        make(chan struct{}),
+		startSignal:     make(chan struct{}),
+		startAttributes: startAttributes,
 	}
 
 	return container.monitor.wait()
@@ -172,7 +180,11 @@ func (m *containerMonitor) start() error {
 
 		pipes := execdriver.NewPipes(m.container.Stdin(), m.container.Stdout(), m.container.Stderr(), m.container.Config.OpenStdin)
 
-		m.logEvent("start")
+		if m.container.RestartCount == 0 {
+			m.supervisor.LogContainerEventWithAttributes(m.container, "start", m.startAttributes)
+		} else {
+			m.logEvent("start")
+		}
 
 		m.lastStartTime = time.Now()
 
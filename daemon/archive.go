@@ -14,6 +14,7 @@ import (
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 // ErrExtractPointNotDirectory is used to convey that the operation to extract
@@ -65,13 +66,13 @@ func (daemon *Daemon) ContainerArchivePath(name string, path string) (content io
 // be ErrExtractPointNotDirectory. If noOverwriteDirNonDir is true then it will
 // be an error if unpacking the given content would cause an existing directory
 // to be replaced with a non-directory and vice versa.
-func (daemon *Daemon) ContainerExtractToDir(name, path string, noOverwriteDirNonDir bool, content io.Reader) error {
+func (daemon *Daemon) ContainerExtractToDir(ctx context.Context, name, path string, noOverwriteDirNonDir bool, content io.Reader) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
 	}
 
-	return daemon.containerExtractToDir(container, path, noOverwriteDirNonDir, content)
+	return daemon.containerExtractToDir(ctx, container, path, noOverwriteDirNonDir, content)
 }
 
 // containerStatPath stats the filesystem resource at the specified path in this
@@ -173,7 +174,7 @@ func (daemon *Daemon) containerArchivePath(container *container.Container, path
 // noOverwriteDirNonDir is true then it will be an error if unpacking the
 // given content would cause an existing directory to be replaced with a non-
 // directory and vice versa.
-func (daemon *Daemon) containerExtractToDir(container *container.Container, path string, noOverwriteDirNonDir bool, content io.Reader) (err error) {
+func (daemon *Daemon) containerExtractToDir(ctx context.Context, container *container.Container, path string, noOverwriteDirNonDir bool, content io.Reader) (err error) {
 	container.Lock()
 	defer container.Unlock()
 
@@ -250,7 +251,7 @@ func (daemon *Daemon) containerExtractToDir(container *container.Container, path
 		return ErrRootFSReadOnly
 	}
 
-	uid, gid := daemon.GetRemappedUIDGID()
+	uid, gid := daemon.containerRootUIDGID(container)
 	options := &archive.TarOptions{
 		NoOverwriteDirNonDir: noOverwriteDirNonDir,
 		ChownOpts: &archive.TarChownOptions{
@@ -261,7 +262,7 @@ func (daemon *Daemon) containerExtractToDir(container *container.Container, path
 		return err
 	}
 
-	daemon.LogContainerEvent(container, "extract-to-dir")
+	daemon.LogContainerEventWithAttributes(container, "extract-to-dir", requestAttributes(ctx, nil))
 
 	return nil
 }
@@ -339,8 +340,6 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 	srcPath := src.Path()
 	destExists := true
 	destDir := false
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
-
 	// Work in daemon-local OS specific file paths
 	destPath = filepath.FromSlash(destPath)
 
@@ -348,6 +347,7 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 	if err != nil {
 		return err
 	}
+	rootUID, rootGID := daemon.containerRootUIDGID(c)
 	err = daemon.Mount(c)
 	if err != nil {
 		return err
@@ -377,7 +377,7 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 		destExists = false
 	}
 
-	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	uidMaps, gidMaps := daemon.containerIDMaps(c)
 	archiver := &archive.Archiver{
 		Untar:   chrootarchive.Untar,
 		UIDMaps: uidMaps,
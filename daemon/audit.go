@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/docker/pkg/audit"
+	"github.com/docker/go-units"
+)
+
+// auditSyslog is the --audit-log value sending the records to syslog.
+const auditSyslog = "syslog"
+
+// newAuditLogger returns the audit logger writing to the file or to syslog
+// as configured by --audit-log, or nil if the audit log is disabled.
+func newAuditLogger(config *Config) (*audit.Logger, error) {
+	if config.AuditLog == "" {
+		return nil, nil
+	}
+
+	var (
+		sink io.WriteCloser
+		err  error
+	)
+	if config.AuditLog == auditSyslog {
+		sink, err = newAuditSyslogWriter(config.AuditLogOpts)
+	} else {
+		sink, err = newAuditFileWriter(config.AuditLog, config.AuditLogOpts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error opening the audit log: %v", err)
+	}
+	return audit.New(sink), nil
+}
+
+func newAuditFileWriter(path string, opts map[string]string) (io.WriteCloser, error) {
+	var (
+		capacity int64 = -1
+		maxFiles       = 1
+		err      error
+	)
+	for k, v := range opts {
+		switch k {
+		case "max-size":
+			if capacity, err = units.FromHumanSize(v); err != nil {
+				return nil, err
+			}
+		case "max-file":
+			if maxFiles, err = strconv.Atoi(v); err != nil {
+				return nil, err
+			}
+			if maxFiles < 1 {
+				return nil, fmt.Errorf("max-file cannot be less than 1")
+			}
+		default:
+			return nil, fmt.Errorf("unknown audit log option %s for the audit log file", k)
+		}
+	}
+	return loggerutils.NewRotateFileWriter(path, capacity, maxFiles)
+}
+
+// ResourceIDs returns the IDs of the resource of the given type referenced by
+// name or ID in an API request path. The IDs of an exec instance are its own
+// and its container's. Volumes are identified by their name.
+func (daemon *Daemon) ResourceIDs(resourceType, ref string) []string {
+	switch resourceType {
+	case "containers":
+		if container, err := daemon.GetContainer(ref); err == nil {
+			return []string{container.ID}
+		}
+	case "exec":
+		if ec := daemon.execCommands.Get(ref); ec != nil {
+			return []string{ec.ID, ec.ContainerID}
+		}
+	case "images":
+		if img, err := daemon.GetImage(ref); err == nil {
+			return []string{img.ID().String()}
+		}
+	case "networks":
+		if nw, err := daemon.FindNetwork(ref); err == nil {
+			return []string{nw.ID()}
+		}
+	case "volumes":
+		if v, err := daemon.volumes.Get(ref); err == nil {
+			return []string{v.Name()}
+		}
+	}
+	return nil
+}
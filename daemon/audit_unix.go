@@ -0,0 +1,62 @@
+// +build linux freebsd
+
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	syslog "github.com/RackSec/srslog"
+)
+
+var auditSyslogFacilities = map[string]syslog.Priority{
+	"auth":     syslog.LOG_AUTH,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"daemon":   syslog.LOG_DAEMON,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+	"user":     syslog.LOG_USER,
+}
+
+func newAuditSyslogWriter(opts map[string]string) (io.WriteCloser, error) {
+	var (
+		proto, address string
+		facility       = syslog.LOG_AUTHPRIV
+		tag            = "docker-audit"
+	)
+	for k, v := range opts {
+		switch k {
+		case "syslog-address":
+			u, err := url.Parse(v)
+			if err != nil {
+				return nil, err
+			}
+			switch u.Scheme {
+			case "tcp", "udp":
+				proto, address = u.Scheme, u.Host
+			case "unix", "unixgram":
+				proto, address = u.Scheme, u.Path
+			default:
+				return nil, fmt.Errorf("syslog-address should be in form proto://address, got %v", v)
+			}
+		case "syslog-facility":
+			f, ok := auditSyslogFacilities[v]
+			if !ok {
+				return nil, fmt.Errorf("invalid syslog facility %s", v)
+			}
+			facility = f
+		case "tag":
+			tag = v
+		default:
+			return nil, fmt.Errorf("unknown audit log option %s for syslog", k)
+		}
+	}
+	return syslog.Dial(proto, address, facility|syslog.LOG_INFO, tag)
+}
@@ -0,0 +1,10 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+)
+
+func newAuditSyslogWriter(opts map[string]string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("the syslog audit log is not supported on Windows")
+}
@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/pkg/authorization/rbac"
+)
+
+// ResolveResource looks up the resource of the given type referenced by name
+// or ID for the authorization policy. The resource of an exec instance is the
+// container it runs in.
+func (daemon *Daemon) ResolveResource(resourceType, ref string) (*rbac.Resource, error) {
+	switch resourceType {
+	case rbac.ContainerResource:
+		return daemon.containerResource(ref)
+	case rbac.ExecResource:
+		ec := daemon.execCommands.Get(ref)
+		if ec == nil {
+			return nil, errExecNotFound(ref)
+		}
+		return daemon.containerResource(ec.ContainerID)
+	case rbac.ImageResource:
+		img, err := daemon.GetImage(ref)
+		if err != nil {
+			return nil, err
+		}
+		res := &rbac.Resource{ID: img.ID().String(), Name: ref}
+		if img.Config != nil {
+			res.Labels = img.Config.Labels
+		}
+		return res, nil
+	case rbac.NetworkResource:
+		nw, err := daemon.FindNetwork(ref)
+		if err != nil {
+			return nil, err
+		}
+		return &rbac.Resource{ID: nw.ID(), Name: nw.Name(), Labels: nw.Info().Labels()}, nil
+	case rbac.VolumeResource:
+		v, err := daemon.volumes.Get(ref)
+		if err != nil {
+			return nil, err
+		}
+		return &rbac.Resource{Name: v.Name()}, nil
+	}
+	return nil, fmt.Errorf("unknown resource type %s", resourceType)
+}
+
+func (daemon *Daemon) containerResource(ref string) (*rbac.Resource, error) {
+	container, err := daemon.GetContainer(ref)
+	if err != nil {
+		return nil, err
+	}
+	return &rbac.Resource{
+		ID:     container.ID,
+		Name:   container.Name,
+		Labels: container.Config.Labels,
+	}, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
 	"github.com/docker/go-connections/nat"
+	"golang.org/x/net/context"
 )
 
 // merge merges two Config, the image container configuration (defaults values),
@@ -93,12 +94,15 @@ func merge(userConf, imageConf *containertypes.Config) error {
 	if userConf.StopSignal == "" {
 		userConf.StopSignal = imageConf.StopSignal
 	}
+	if userConf.StopTimeout == nil {
+		userConf.StopTimeout = imageConf.StopTimeout
+	}
 	return nil
 }
 
 // Commit creates a new filesystem image from the current state of a container.
 // The image can optionally be tagged into a repository.
-func (daemon *Daemon) Commit(name string, c *types.ContainerCommitConfig) (string, error) {
+func (daemon *Daemon) Commit(ctx context.Context, name string, c *types.ContainerCommitConfig) (string, error) {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return "", err
@@ -110,8 +114,8 @@ func (daemon *Daemon) Commit(name string, c *types.ContainerCommitConfig) (strin
 	}
 
 	if c.Pause && !container.IsPaused() {
-		daemon.containerPause(container)
-		defer daemon.containerUnpause(container)
+		daemon.containerPause(ctx, container)
+		defer daemon.containerUnpause(ctx, container)
 	}
 
 	if c.MergeConfigs {
@@ -132,6 +136,7 @@ func (daemon *Daemon) Commit(name string, c *types.ContainerCommitConfig) (strin
 
 	var history []image.History
 	rootFS := image.NewRootFS()
+	osName, arch, variant := runtime.GOOS, runtime.GOARCH, ""
 
 	if container.ImageID != "" {
 		img, err := daemon.imageStore.Get(container.ImageID)
@@ -140,6 +145,10 @@ func (daemon *Daemon) Commit(name string, c *types.ContainerCommitConfig) (strin
 		}
 		history = img.History
 		rootFS = img.RootFS
+		// the image is for the platform of its base image
+		if img.OS != "" {
+			osName, arch, variant = img.OS, img.Architecture, img.Variant
+		}
 	}
 
 	l, err := daemon.layerStore.Register(rwTar, rootFS.ChainID())
@@ -167,8 +176,9 @@ func (daemon *Daemon) Commit(name string, c *types.ContainerCommitConfig) (strin
 		V1Image: image.V1Image{
 			DockerVersion:   dockerversion.Version,
 			Config:          c.Config,
-			Architecture:    runtime.GOARCH,
-			OS:              runtime.GOOS,
+			Architecture:    arch,
+			OS:              osName,
+			Variant:         variant,
 			Container:       container.ID,
 			ContainerConfig: *container.Config,
 			Author:          c.Author,
@@ -203,7 +213,7 @@ func (daemon *Daemon) Commit(name string, c *types.ContainerCommitConfig) (strin
 				return "", err
 			}
 		}
-		if err := daemon.TagImage(newTag, id.String()); err != nil {
+		if err := daemon.TagImage(ctx, newTag, id.String()); err != nil {
 			return "", err
 		}
 	}
@@ -211,7 +221,7 @@ func (daemon *Daemon) Commit(name string, c *types.ContainerCommitConfig) (strin
 	attributes := map[string]string{
 		"comment": c.Comment,
 	}
-	daemon.LogContainerEventWithAttributes(container, "commit", attributes)
+	daemon.LogContainerEventWithAttributes(container, "commit", requestAttributes(ctx, attributes))
 	return id.String(), nil
 }
 
@@ -224,6 +234,7 @@ func (daemon *Daemon) exportContainerRw(container *container.Container) (archive
 	if err != nil {
 		return nil, err
 	}
+	archive = excludeSecrets(container, archive)
 	return ioutils.NewReadCloserWrapper(archive, func() error {
 			archive.Close()
 			return container.RWLayer.Unmount()
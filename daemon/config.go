@@ -53,24 +53,34 @@ type CommonTLSOptions struct {
 // It includes json tags to deserialize configuration from a file
 // using the same names that the flags in the command line uses.
 type CommonConfig struct {
-	AuthorizationPlugins []string            `json:"authorization-plugins,omitempty"` // AuthorizationPlugins holds list of authorization plugins
-	AutoRestart          bool                `json:"-"`
-	Context              map[string][]string `json:"-"`
-	DisableBridge        bool                `json:"-"`
-	DNS                  []string            `json:"dns,omitempty"`
-	DNSOptions           []string            `json:"dns-opts,omitempty"`
-	DNSSearch            []string            `json:"dns-search,omitempty"`
-	ExecOptions          []string            `json:"exec-opts,omitempty"`
-	ExecRoot             string              `json:"exec-root,omitempty"`
-	GraphDriver          string              `json:"storage-driver,omitempty"`
-	GraphOptions         []string            `json:"storage-opts,omitempty"`
-	Labels               []string            `json:"labels,omitempty"`
-	Mtu                  int                 `json:"mtu,omitempty"`
-	Pidfile              string              `json:"pidfile,omitempty"`
-	RawLogs              bool                `json:"raw-logs,omitempty"`
-	Root                 string              `json:"graph,omitempty"`
-	SocketGroup          string              `json:"group,omitempty"`
-	TrustKeyPath         string              `json:"-"`
+	AuditLog                string              `json:"audit-log,omitempty"`                 // AuditLog is the file or syslog the API audit records are written to
+	AuditLogOpts            map[string]string   `json:"audit-log-opts,omitempty"`            // AuditLogOpts holds the options of the audit log
+	AuthenticationMethods   []string            `json:"authentication-methods,omitempty"`    // AuthenticationMethods holds list of API authentication methods
+	AuthenticationTokenFile string              `json:"authentication-token-file,omitempty"` // AuthenticationTokenFile is the file holding the API bearer tokens
+	AuthorizationPlugins    []string            `json:"authorization-plugins,omitempty"`     // AuthorizationPlugins holds list of authorization plugins
+	AuthorizationPolicy     string              `json:"authorization-policy,omitempty"`      // AuthorizationPolicy is the file holding the built-in authorization policy
+	AutoRestart             bool                `json:"-"`
+	Context                 map[string][]string `json:"-"`
+	DisableBridge           bool                `json:"-"`
+	DNS                     []string            `json:"dns,omitempty"`
+	DNSOptions              []string            `json:"dns-opts,omitempty"`
+	DNSSearch               []string            `json:"dns-search,omitempty"`
+	ExecOptions             []string            `json:"exec-opts,omitempty"`
+	ExecRoot                string              `json:"exec-root,omitempty"`
+	GraphDriver             string              `json:"storage-driver,omitempty"`
+	GraphOptions            []string            `json:"storage-opts,omitempty"`
+	Labels                  []string            `json:"labels,omitempty"`
+	Mtu                     int                 `json:"mtu,omitempty"`
+	Pidfile                 string              `json:"pidfile,omitempty"`
+	RawLogs                 bool                `json:"raw-logs,omitempty"`
+	RestrictedGroup         string              `json:"restricted-group,omitempty"`  // RestrictedGroup is the group of the unix sockets of the restricted listeners
+	RestrictedHosts         []string            `json:"restricted-hosts,omitempty"`  // RestrictedHosts are the addresses of the listeners restricted to RestrictedRoutes
+	RestrictedRoutes        []string            `json:"restricted-routes,omitempty"` // RestrictedRoutes are the API routes allowed on the restricted listeners
+	Root                    string              `json:"graph,omitempty"`
+	SecretsKeyFile          string              `json:"secrets-key-file,omitempty"` // SecretsKeyFile is the file holding the key encrypting the secrets
+	SocketGroup             string              `json:"group,omitempty"`
+	TrustKeyPath            string              `json:"-"`
+	TrustPolicy             string              `json:"trust-policy,omitempty"`
 
 	// ClusterStore is the storage backend used for the cluster information. It is used by both
 	// multihost networking (to store networks and endpoints information) and by the node discovery
@@ -111,10 +121,19 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	config.ServiceOptions.InstallCliFlags(cmd, usageFn)
 
 	cmd.Var(opts.NewNamedListOptsRef("storage-opts", &config.GraphOptions, nil), []string{"-storage-opt"}, usageFn("Set storage driver options"))
+	cmd.StringVar(&config.AuditLog, []string{"-audit-log"}, "", usageFn("Record the API requests changing the daemon state to a file or to syslog"))
+	cmd.Var(opts.NewNamedMapOpts("audit-log-opts", config.AuditLogOpts, nil), []string{"-audit-log-opt"}, usageFn("Set audit log options"))
+	cmd.Var(opts.NewNamedListOptsRef("authentication-methods", &config.AuthenticationMethods, nil), []string{"-authentication-method"}, usageFn("List API authentication methods (tls, peercred, token) in order of precedence"))
+	cmd.StringVar(&config.AuthenticationTokenFile, []string{"-authentication-token-file"}, "", usageFn("File holding the bearer tokens of the token authentication method"))
 	cmd.Var(opts.NewNamedListOptsRef("authorization-plugins", &config.AuthorizationPlugins, nil), []string{"-authorization-plugin"}, usageFn("List authorization plugins in order from first evaluator to last"))
+	cmd.StringVar(&config.AuthorizationPolicy, []string{"-authorization-policy"}, "", usageFn("File holding the built-in role based authorization policy"))
+	cmd.Var(opts.NewNamedListOptsRef("restricted-hosts", &config.RestrictedHosts, opts.ValidateHost), []string{"-restricted-host"}, usageFn("Daemon socket(s) only serving the restricted routes"))
+	cmd.Var(opts.NewNamedListOptsRef("restricted-routes", &config.RestrictedRoutes, nil), []string{"-restricted-route"}, usageFn("API routes allowed on the restricted sockets, read-only ones by default"))
+	cmd.StringVar(&config.RestrictedGroup, []string{"-restricted-group"}, "", usageFn("Group for the restricted unix sockets"))
 	cmd.Var(opts.NewNamedListOptsRef("exec-opts", &config.ExecOptions, nil), []string{"-exec-opt"}, usageFn("Set exec driver options"))
 	cmd.StringVar(&config.Pidfile, []string{"p", "-pidfile"}, defaultPidFile, usageFn("Path to use for daemon PID file"))
 	cmd.StringVar(&config.Root, []string{"g", "-graph"}, defaultGraph, usageFn("Root of the Docker runtime"))
+	cmd.StringVar(&config.SecretsKeyFile, []string{"-secrets-key-file"}, "", usageFn("File holding the key encrypting the secrets, generated if missing"))
 	cmd.StringVar(&config.ExecRoot, []string{"-exec-root"}, "/var/run/docker", usageFn("Root of the Docker execdriver"))
 	cmd.BoolVar(&config.AutoRestart, []string{"#r", "#-restart"}, true, usageFn("--restart on the daemon has been deprecated in favor of --restart policies on docker run"))
 	cmd.StringVar(&config.GraphDriver, []string{"s", "-storage-driver"}, "", usageFn("Storage driver to use"))
@@ -130,6 +149,7 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.ClusterAdvertise, []string{"-cluster-advertise"}, "", usageFn("Address or interface name to advertise"))
 	cmd.StringVar(&config.ClusterStore, []string{"-cluster-store"}, "", usageFn("Set the cluster store"))
 	cmd.Var(opts.NewNamedMapOpts("cluster-store-opts", config.ClusterOpts, nil), []string{"-cluster-store-opt"}, usageFn("Set cluster store options"))
+	cmd.StringVar(&config.TrustPolicy, []string{"-trust-policy"}, "", usageFn("Trust policy file requiring signed images"))
 }
 
 // IsValueSet returns true if a configuration value
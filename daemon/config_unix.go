@@ -29,8 +29,16 @@ type Config struct {
 	EnableCors           bool                     `json:"api-enable-cors,omitempty"`
 	EnableSelinuxSupport bool                     `json:"selinux-enabled,omitempty"`
 	RemappedRoot         string                   `json:"userns-remap,omitempty"`
+	UsernsPool           string                   `json:"userns-pool,omitempty"`
+	Rootless             bool                     `json:"-"`
 	CgroupParent         string                   `json:"cgroup-parent,omitempty"`
 	Ulimits              map[string]*units.Ulimit `json:"default-ulimits,omitempty"`
+	Init                 bool                     `json:"init,omitempty"`
+	CPURealtimePeriod    int64                    `json:"cpu-rt-period,omitempty"`
+	CPURealtimeRuntime   int64                    `json:"cpu-rt-runtime,omitempty"`
+	SeccompProfile       string                   `json:"seccomp-profile,omitempty"`
+	AppArmorTemplate     string                   `json:"apparmor-template,omitempty"`
+	NoNewPrivileges      bool                     `json:"no-new-privileges,omitempty"`
 }
 
 // bridgeConfig stores all the bridge driver specific
@@ -41,6 +49,7 @@ type bridgeConfig struct {
 	EnableIPForward             bool   `json:"ip-forward,omitempty"`
 	EnableIPMasq                bool   `json:"ip-mask,omitempty"`
 	EnableUserlandProxy         bool   `json:"userland-proxy,omitempty"`
+	IPv6PublishMode             string `json:"ipv6-publish,omitempty"`
 	DefaultIP                   net.IP `json:"ip,omitempty"`
 	Iface                       string `json:"bridge,omitempty"`
 	IP                          string `json:"bip,omitempty"`
@@ -68,6 +77,7 @@ func (config *Config) InstallFlags(cmd *flag.FlagSet, usageFn func(string) strin
 	cmd.BoolVar(&config.bridgeConfig.EnableIPForward, []string{"#ip-forward", "-ip-forward"}, true, usageFn("Enable net.ipv4.ip_forward"))
 	cmd.BoolVar(&config.bridgeConfig.EnableIPMasq, []string{"-ip-masq"}, true, usageFn("Enable IP masquerading"))
 	cmd.BoolVar(&config.bridgeConfig.EnableIPv6, []string{"-ipv6"}, false, usageFn("Enable IPv6 networking"))
+	cmd.StringVar(&config.bridgeConfig.IPv6PublishMode, []string{"-ipv6-publish"}, "", usageFn("Publish container ports on IPv6 (nat or routed)"))
 	cmd.StringVar(&config.bridgeConfig.IP, []string{"#bip", "-bip"}, "", usageFn("Specify network bridge IP"))
 	cmd.StringVar(&config.bridgeConfig.Iface, []string{"b", "-bridge"}, "", usageFn("Attach containers to a network bridge"))
 	cmd.StringVar(&config.bridgeConfig.FixedCIDR, []string{"-fixed-cidr"}, "", usageFn("IPv4 subnet for fixed IPs"))
@@ -81,6 +91,14 @@ func (config *Config) InstallFlags(cmd *flag.FlagSet, usageFn func(string) strin
 	cmd.StringVar(&config.CorsHeaders, []string{"-api-cors-header"}, "", usageFn("Set CORS headers in the remote API"))
 	cmd.StringVar(&config.CgroupParent, []string{"-cgroup-parent"}, "", usageFn("Set parent cgroup for all containers"))
 	cmd.StringVar(&config.RemappedRoot, []string{"-userns-remap"}, "", usageFn("User/Group setting for user namespaces"))
+	cmd.BoolVar(&config.Rootless, []string{"-rootless"}, false, usageFn("Run the daemon as an unprivileged user in a user namespace"))
+	cmd.StringVar(&config.UsernsPool, []string{"-userns-pool"}, "", usageFn("User/Group whose subordinate IDs are allocated to the containers with a private user namespace"))
+	cmd.BoolVar(&config.Init, []string{"-init"}, false, usageFn("Run an init in containers to forward signals and reap processes"))
+	cmd.Int64Var(&config.CPURealtimePeriod, []string{"-cpu-rt-period"}, 0, usageFn("Limit the CPU real-time period in microseconds"))
+	cmd.Int64Var(&config.CPURealtimeRuntime, []string{"-cpu-rt-runtime"}, 0, usageFn("Limit the CPU real-time runtime in microseconds"))
+	cmd.StringVar(&config.SeccompProfile, []string{"-seccomp-profile"}, "", usageFn("Default seccomp profile of the containers"))
+	cmd.StringVar(&config.AppArmorTemplate, []string{"-apparmor-template"}, "", usageFn("Template of the default AppArmor profile of the containers"))
+	cmd.BoolVar(&config.NoNewPrivileges, []string{"-no-new-privileges"}, false, usageFn("Set no-new-privileges by default for new containers"))
 
 	config.attachExperimentalFlags(cmd, usageFn)
 }
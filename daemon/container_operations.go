@@ -20,6 +20,7 @@ import (
 	"github.com/docker/libnetwork/netlabel"
 	"github.com/docker/libnetwork/options"
 	"github.com/docker/libnetwork/types"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -35,9 +36,6 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container, n libn
 		dns         []string
 		dnsSearch   []string
 		dnsOptions  []string
-		bindings    = make(nat.PortMap)
-		pbList      []types.PortBinding
-		exposeList  []types.TransportPort
 	)
 
 	defaultNetName := runconfig.DefaultDaemonNetworkMode().NetworkName()
@@ -113,53 +111,9 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container, n libn
 		sboxOptions = append(sboxOptions, libnetwork.OptionExtraHost(parts[0], parts[1]))
 	}
 
-	if container.HostConfig.PortBindings != nil {
-		for p, b := range container.HostConfig.PortBindings {
-			bindings[p] = []nat.PortBinding{}
-			for _, bb := range b {
-				bindings[p] = append(bindings[p], nat.PortBinding{
-					HostIP:   bb.HostIP,
-					HostPort: bb.HostPort,
-				})
-			}
-		}
-	}
-
-	portSpecs := container.Config.ExposedPorts
-	ports := make([]nat.Port, len(portSpecs))
-	var i int
-	for p := range portSpecs {
-		ports[i] = p
-		i++
-	}
-	nat.SortPortMap(ports, bindings)
-	for _, port := range ports {
-		expose := types.TransportPort{}
-		expose.Proto = types.ParseProtocol(port.Proto())
-		expose.Port = uint16(port.Int())
-		exposeList = append(exposeList, expose)
-
-		pb := types.PortBinding{Port: expose.Port, Proto: expose.Proto}
-		binding := bindings[port]
-		for i := 0; i < len(binding); i++ {
-			pbCopy := pb.GetCopy()
-			newP, err := nat.NewPort(nat.SplitProtoPort(binding[i].HostPort))
-			var portStart, portEnd int
-			if err == nil {
-				portStart, portEnd, err = newP.Range()
-			}
-			if err != nil {
-				return nil, fmt.Errorf("Error parsing HostPort value(%s):%v", binding[i].HostPort, err)
-			}
-			pbCopy.HostPort = uint16(portStart)
-			pbCopy.HostPortEnd = uint16(portEnd)
-			pbCopy.HostIP = net.ParseIP(binding[i].HostIP)
-			pbList = append(pbList, pbCopy)
-		}
-
-		if container.HostConfig.PublishAllPorts && len(binding) == 0 {
-			pbList = append(pbList, pb)
-		}
+	exposeList, pbList, err := buildPortMapping(container)
+	if err != nil {
+		return nil, err
 	}
 
 	sboxOptions = append(sboxOptions,
@@ -275,6 +229,67 @@ func (daemon *Daemon) updateEndpointNetworkSettings(container *container.Contain
 	return nil
 }
 
+// buildPortMapping returns the exposed ports and the port bindings of the
+// container in the form expected by libnetwork.
+func buildPortMapping(container *container.Container) ([]types.TransportPort, []types.PortBinding, error) {
+	var (
+		bindings   = make(nat.PortMap)
+		pbList     []types.PortBinding
+		exposeList []types.TransportPort
+	)
+
+	if container.HostConfig.PortBindings != nil {
+		for p, b := range container.HostConfig.PortBindings {
+			bindings[p] = []nat.PortBinding{}
+			for _, bb := range b {
+				bindings[p] = append(bindings[p], nat.PortBinding{
+					HostIP:   bb.HostIP,
+					HostPort: bb.HostPort,
+				})
+			}
+		}
+	}
+
+	portSpecs := container.Config.ExposedPorts
+	ports := make([]nat.Port, len(portSpecs))
+	var i int
+	for p := range portSpecs {
+		ports[i] = p
+		i++
+	}
+	nat.SortPortMap(ports, bindings)
+	for _, port := range ports {
+		expose := types.TransportPort{}
+		expose.Proto = types.ParseProtocol(port.Proto())
+		expose.Port = uint16(port.Int())
+		exposeList = append(exposeList, expose)
+
+		pb := types.PortBinding{Port: expose.Port, Proto: expose.Proto}
+		binding := bindings[port]
+		for i := 0; i < len(binding); i++ {
+			pbCopy := pb.GetCopy()
+			newP, err := nat.NewPort(nat.SplitProtoPort(binding[i].HostPort))
+			var portStart, portEnd int
+			if err == nil {
+				portStart, portEnd, err = newP.Range()
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("Error parsing HostPort value(%s):%v", binding[i].HostPort, err)
+			}
+			pbCopy.HostPort = uint16(portStart)
+			pbCopy.HostPortEnd = uint16(portEnd)
+			pbCopy.HostIP = net.ParseIP(binding[i].HostIP)
+			pbList = append(pbList, pbCopy)
+		}
+
+		if container.HostConfig.PublishAllPorts && len(binding) == 0 {
+			pbList = append(pbList, pb)
+		}
+	}
+
+	return exposeList, pbList, nil
+}
+
 // UpdateNetwork is used to update the container's network (e.g. when linked containers
 // get removed/unlinked).
 func (daemon *Daemon) updateNetwork(container *container.Container) error {
@@ -372,7 +387,7 @@ func (daemon *Daemon) updateContainerNetworkSettings(container *container.Contai
 	return nil
 }
 
-func (daemon *Daemon) allocateNetwork(container *container.Container) error {
+func (daemon *Daemon) allocateNetwork(ctx context.Context, container *container.Container) error {
 	controller := daemon.netController
 
 	if daemon.netController == nil {
@@ -398,7 +413,7 @@ func (daemon *Daemon) allocateNetwork(container *container.Container) error {
 	}
 
 	for n, nConf := range container.NetworkSettings.Networks {
-		if err := daemon.connectToNetwork(container, n, nConf, updateSettings); err != nil {
+		if err := daemon.connectToNetwork(ctx, container, n, nConf, updateSettings); err != nil {
 			return err
 		}
 	}
@@ -462,6 +477,90 @@ func validateNetworkingConfig(n libnetwork.Network, epConfig *networktypes.Endpo
 	return nil
 }
 
+// enforcesEgressInContainer tells whether the egress policy of the network is
+// programmed in the network namespace of the containers connected to it,
+// where a process with the NET_ADMIN capability can remove it.
+func enforcesEgressInContainer(n libnetwork.Network) bool {
+	return n.Type() == "macvlan" && n.Info().EgressPolicy() != nil
+}
+
+// canAdminNetwork tells whether the processes of a container have the
+// NET_ADMIN capability.
+func canAdminNetwork(hostConfig *containertypes.HostConfig) bool {
+	if hostConfig == nil {
+		return false
+	}
+	if hostConfig.Privileged {
+		return true
+	}
+	for _, c := range hostConfig.CapAdd {
+		switch strings.ToUpper(c) {
+		case "ALL", "NET_ADMIN", "CAP_NET_ADMIN":
+			return true
+		}
+	}
+	return false
+}
+
+// sandboxEgressNetwork returns the network enforcing its egress policy in the
+// network namespace of the container, or nil. The network namespace of a
+// container started with --net container:<name|id> is the one of the other
+// container.
+func (daemon *Daemon) sandboxEgressNetwork(c *container.Container) libnetwork.Network {
+	if daemon.netController == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	for c.HostConfig != nil && c.HostConfig.NetworkMode.IsContainer() && !seen[c.ID] {
+		seen[c.ID] = true
+		nc, err := daemon.GetContainer(c.HostConfig.NetworkMode.ConnectedContainer())
+		if err != nil {
+			return nil
+		}
+		c = nc
+	}
+	if c.NetworkSettings == nil {
+		return nil
+	}
+	for name := range c.NetworkSettings.Networks {
+		n, err := daemon.FindNetwork(name)
+		if err != nil {
+			continue
+		}
+		if enforcesEgressInContainer(n) {
+			return n
+		}
+	}
+	return nil
+}
+
+// sandboxJoiners returns the running containers sharing the network namespace
+// of the container with --net container:<name|id>.
+func (daemon *Daemon) sandboxJoiners(c *container.Container) []*container.Container {
+	var joiners []*container.Container
+	for _, j := range daemon.List() {
+		if j.ID == c.ID || !j.IsRunning() || j.HostConfig == nil || !j.HostConfig.NetworkMode.IsContainer() {
+			continue
+		}
+		if nc, err := daemon.GetContainer(j.HostConfig.NetworkMode.ConnectedContainer()); err == nil && nc.ID == c.ID {
+			joiners = append(joiners, j)
+		}
+	}
+	return joiners
+}
+
+// validateEgressExec rejects the privileged processes executed in a container
+// whose network namespace enforces the egress policy of a network.
+func (daemon *Daemon) validateEgressExec(container *container.Container, privileged bool) error {
+	if !privileged {
+		return nil
+	}
+	if n := daemon.sandboxEgressNetwork(container); n != nil {
+		return fmt.Errorf("Privileged exec is not allowed in container %s: the egress policy of network %s is enforced in the container", container.ID, n.Name())
+	}
+	return nil
+}
+
 // cleanOperationalData resets the operational data from the passed endpoint settings
 func cleanOperationalData(es *networktypes.EndpointSettings) {
 	es.EndpointID = ""
@@ -492,6 +591,9 @@ func (daemon *Daemon) updateNetworkConfig(container *container.Container, idOrNa
 		if endpointConfig != nil && len(endpointConfig.Aliases) > 0 {
 			return nil, runconfig.ErrUnsupportedNetworkAndAlias
 		}
+		if endpointConfig != nil && endpointConfig.DNSRRName != "" {
+			return nil, runconfig.ErrUnsupportedNetworkAndDNSRRName
+		}
 	}
 
 	n, err := daemon.FindNetwork(idOrName)
@@ -503,6 +605,17 @@ func (daemon *Daemon) updateNetworkConfig(container *container.Container, idOrNa
 		return nil, err
 	}
 
+	if enforcesEgressInContainer(n) {
+		if canAdminNetwork(container.HostConfig) {
+			return nil, fmt.Errorf("Container %s can not be connected to network %s: its egress policy is enforced in the container, which has the NET_ADMIN capability", container.ID, n.Name())
+		}
+		for _, j := range daemon.sandboxJoiners(container) {
+			if canAdminNetwork(j.HostConfig) {
+				return nil, fmt.Errorf("Container %s can not be connected to network %s: its egress policy is enforced in the container, whose network namespace is shared with container %s, which has the NET_ADMIN capability", container.ID, n.Name(), j.ID)
+			}
+		}
+	}
+
 	if updateSettings {
 		if err := daemon.updateNetworkSettings(container, n); err != nil {
 			return nil, err
@@ -511,7 +624,7 @@ func (daemon *Daemon) updateNetworkConfig(container *container.Container, idOrNa
 	return n, nil
 }
 
-func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings, updateSettings bool) (err error) {
+func (daemon *Daemon) connectToNetwork(ctx context.Context, container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings, updateSettings bool) (err error) {
 	// TODO Windows: Remove this once TP4 builds are not supported
 	// Windows TP4 build don't support libnetwork and in that case
 	// daemon.netController will be nil
@@ -582,7 +695,51 @@ func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName
 		return fmt.Errorf("Updating join info failed: %v", err)
 	}
 
-	daemon.LogNetworkEventWithAttributes(n, "connect", map[string]string{"container": container.ID})
+	daemon.LogNetworkEventWithAttributes(n, "connect", requestAttributes(ctx, map[string]string{"container": container.ID}))
+	return nil
+}
+
+// bandwidthUpdate returns the network if the container is already connected
+// to it, and either the endpoint settings or the current ones hold bandwidth
+// limits, so that connecting again updates those limits.
+func (daemon *Daemon) bandwidthUpdate(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) (libnetwork.Network, bool) {
+	n, err := daemon.FindNetwork(idOrName)
+	if err != nil {
+		return nil, false
+	}
+	settings, ok := container.NetworkSettings.Networks[n.Name()]
+	if !ok || settings == nil {
+		return nil, false
+	}
+	if settings.IngressRate > 0 || settings.EgressRate > 0 {
+		return n, true
+	}
+	return n, endpointConfig != nil && (endpointConfig.IngressRate > 0 || endpointConfig.EgressRate > 0)
+}
+
+// updateEndpointBandwidth updates the rate limits of the endpoint of a running
+// container already connected to the network n.
+func (daemon *Daemon) updateEndpointBandwidth(container *container.Container, n libnetwork.Network, endpointConfig *networktypes.EndpointSettings) error {
+	var ingressRate, egressRate uint64
+	if endpointConfig != nil {
+		if hasUserDefinedIPAddress(endpointConfig) || len(endpointConfig.Links) > 0 || len(endpointConfig.Aliases) > 0 || endpointConfig.DNSRRName != "" {
+			return fmt.Errorf("container %s is already connected to network %s, only its bandwidth limits can be updated", container.ID, n.Name())
+		}
+		ingressRate, egressRate = endpointConfig.IngressRate, endpointConfig.EgressRate
+	}
+
+	ep, err := container.GetEndpointInNetwork(n)
+	if err != nil {
+		return err
+	}
+
+	if err := ep.SetBandwidth(ingressRate, egressRate); err != nil {
+		return err
+	}
+
+	settings := container.NetworkSettings.Networks[n.Name()]
+	settings.IngressRate = ingressRate
+	settings.EgressRate = egressRate
 	return nil
 }
 
@@ -642,7 +799,7 @@ func disconnectFromNetwork(container *container.Container, n libnetwork.Network,
 	return nil
 }
 
-func (daemon *Daemon) initializeNetworking(container *container.Container) error {
+func (daemon *Daemon) initializeNetworking(ctx context.Context, container *container.Container) error {
 	var err error
 
 	// TODO Windows: Remove this once TP4 builds are not supported
@@ -658,6 +815,11 @@ func (daemon *Daemon) initializeNetworking(container *container.Container) error
 		if err != nil {
 			return err
 		}
+		if canAdminNetwork(container.HostConfig) {
+			if n := daemon.sandboxEgressNetwork(nc); n != nil {
+				return fmt.Errorf("Container %s can not join the network namespace of container %s: the egress policy of network %s is enforced in it, and the container has the NET_ADMIN capability", container.ID, nc.ID, n.Name())
+			}
+		}
 		container.HostnamePath = nc.HostnamePath
 		container.HostsPath = nc.HostsPath
 		container.ResolvConfPath = nc.ResolvConfPath
@@ -680,7 +842,7 @@ func (daemon *Daemon) initializeNetworking(container *container.Container) error
 
 	}
 
-	if err := daemon.allocateNetwork(container); err != nil {
+	if err := daemon.allocateNetwork(ctx, container); err != nil {
 		return err
 	}
 
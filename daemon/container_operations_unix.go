@@ -4,6 +4,7 @@ package daemon
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -27,6 +28,7 @@ import (
 	"github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/opencontainers/runc/libcontainer/devices"
 	"github.com/opencontainers/runc/libcontainer/label"
+	"golang.org/x/net/context"
 )
 
 func (daemon *Daemon) setupLinkedContainers(container *container.Container) ([]string, error) {
@@ -187,6 +189,9 @@ func (daemon *Daemon) populateCommand(c *container.Container, env []string) erro
 		CpusetMems:                   c.HostConfig.CpusetMems,
 		CPUPeriod:                    c.HostConfig.CPUPeriod,
 		CPUQuota:                     c.HostConfig.CPUQuota,
+		CPURealtimePeriod:            c.HostConfig.CPURealtimePeriod,
+		CPURealtimeRuntime:           c.HostConfig.CPURealtimeRuntime,
+		NanoCPUs:                     c.HostConfig.NanoCPUs,
 		Rlimits:                      rlimits,
 		BlkioWeightDevice:            weightDevices,
 		BlkioThrottleReadBpsDevice:   readBpsDevice,
@@ -218,12 +223,12 @@ func (daemon *Daemon) populateCommand(c *container.Container, env []string) erro
 	processConfig.Env = env
 
 	remappedRoot := &execdriver.User{}
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	rootUID, rootGID := daemon.containerRootUIDGID(c)
 	if rootUID != 0 {
 		remappedRoot.UID = rootUID
 		remappedRoot.GID = rootGID
 	}
-	uidMap, gidMap := daemon.GetUIDGIDMaps()
+	uidMap, gidMap := daemon.containerIDMaps(c)
 
 	if !daemon.seccompEnabled {
 		if c.SeccompProfile != "" && c.SeccompProfile != "unconfined" {
@@ -232,12 +237,20 @@ func (daemon *Daemon) populateCommand(c *container.Container, env []string) erro
 		logrus.Warn("Seccomp is not enabled in your kernel, running container without default profile.")
 		c.SeccompProfile = "unconfined"
 	}
+	seccompProfile, err := daemon.seccompProfile(c)
+	if err != nil {
+		return err
+	}
 
 	defaultCgroupParent := "/docker"
 	if daemon.configStore.CgroupParent != "" {
 		defaultCgroupParent = daemon.configStore.CgroupParent
 	} else if daemon.usingSystemd() {
 		defaultCgroupParent = "system.slice"
+	} else if daemon.configStore.Rootless {
+		// Relative to the cgroup of the daemon, which is delegated to the
+		// user running a rootless daemon.
+		defaultCgroupParent = "docker"
 	}
 	c.Command = &execdriver.Command{
 		CommonCommand: execdriver.CommonCommand{
@@ -254,16 +267,18 @@ func (daemon *Daemon) populateCommand(c *container.Container, env []string) erro
 		AppArmorProfile:    c.AppArmorProfile,
 		AutoCreatedDevices: autoCreatedDevices,
 		CapAdd:             c.HostConfig.CapAdd,
+		CapAmbient:         c.HostConfig.CapAmbient,
 		CapDrop:            c.HostConfig.CapDrop,
 		CgroupParent:       defaultCgroupParent,
 		GIDMapping:         gidMap,
 		GroupAdd:           c.HostConfig.GroupAdd,
+		Init:               daemon.configStore.Init,
 		Ipc:                ipc,
 		OomScoreAdj:        c.HostConfig.OomScoreAdj,
 		Pid:                pid,
 		ReadonlyRootfs:     c.HostConfig.ReadonlyRootfs,
 		RemappedRoot:       remappedRoot,
-		SeccompProfile:     c.SeccompProfile,
+		SeccompProfile:     seccompProfile,
 		UIDMapping:         uidMap,
 		UTS:                uts,
 		NoNewPrivileges:    c.NoNewPrivileges,
@@ -271,6 +286,14 @@ func (daemon *Daemon) populateCommand(c *container.Container, env []string) erro
 	if c.HostConfig.CgroupParent != "" {
 		c.Command.CgroupParent = c.HostConfig.CgroupParent
 	}
+	if !daemon.usingSystemd() {
+		if err := daemon.initCgroupsPath(c.Command.CgroupParent); err != nil {
+			return err
+		}
+	}
+	if c.HostConfig.Init != nil {
+		c.Command.Init = *c.HostConfig.Init
+	}
 
 	return nil
 }
@@ -309,7 +332,7 @@ func (daemon *Daemon) getSize(container *container.Container) (int64, int64) {
 }
 
 // ConnectToNetwork connects a container to a network
-func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
+func (daemon *Daemon) ConnectToNetwork(ctx context.Context, container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
 	if !container.Running {
 		if container.RemovalInProgress || container.Dead {
 			return errRemovalContainer(container.ID)
@@ -320,8 +343,13 @@ func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName
 		if endpointConfig != nil {
 			container.NetworkSettings.Networks[idOrName] = endpointConfig
 		}
+	} else if n, ok := daemon.bandwidthUpdate(container, idOrName, endpointConfig); ok {
+		// Connecting again to a network only updates the bandwidth limits
+		if err := daemon.updateEndpointBandwidth(container, n, endpointConfig); err != nil {
+			return err
+		}
 	} else {
-		if err := daemon.connectToNetwork(container, idOrName, endpointConfig, true); err != nil {
+		if err := daemon.connectToNetwork(ctx, container, idOrName, endpointConfig, true); err != nil {
 			return err
 		}
 	}
@@ -332,7 +360,7 @@ func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName
 }
 
 // DisconnectFromNetwork disconnects container from network n.
-func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n libnetwork.Network, force bool) error {
+func (daemon *Daemon) DisconnectFromNetwork(ctx context.Context, container *container.Container, n libnetwork.Network, force bool) error {
 	if container.HostConfig.NetworkMode.IsHost() && containertypes.NetworkMode(n.Type()).IsHost() {
 		return runconfig.ErrConflictHostNetwork
 	}
@@ -358,7 +386,7 @@ func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n li
 	attributes := map[string]string{
 		"container": container.ID,
 	}
-	daemon.LogNetworkEventWithAttributes(n, "disconnect", attributes)
+	daemon.LogNetworkEventWithAttributes(n, "disconnect", requestAttributes(ctx, attributes))
 	return nil
 }
 
@@ -392,7 +420,7 @@ func (daemon *Daemon) getIpcContainer(container *container.Container) (*containe
 }
 
 func (daemon *Daemon) setupIpcDirs(c *container.Container) error {
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	rootUID, rootGID := daemon.containerRootUIDGID(c)
 	if !c.HasMountFor("/dev/shm") {
 		shmPath, err := c.ShmResourcePath()
 		if err != nil {
@@ -419,6 +447,56 @@ func (daemon *Daemon) setupIpcDirs(c *container.Container) error {
 	return nil
 }
 
+// setupSecretMounts writes the secrets of the container to a tmpfs private
+// to the container, so that their data never hits the disk unencrypted.
+func (daemon *Daemon) setupSecretMounts(c *container.Container) error {
+	if len(c.HostConfig.Secrets) == 0 {
+		return nil
+	}
+	rootUID, rootGID := daemon.containerRootUIDGID(c)
+	uidMaps, _ := daemon.containerIDMaps(c)
+	secretsPath, err := c.SecretsResourcePath()
+	if err != nil {
+		return err
+	}
+	if err := idtools.MkdirAllAs(secretsPath, 0700, rootUID, rootGID); err != nil {
+		return err
+	}
+	if err := syscall.Mount("secrets", secretsPath, "tmpfs", uintptr(syscall.MS_NOEXEC|syscall.MS_NOSUID|syscall.MS_NODEV), label.FormatMountLabel("mode=0700", c.GetMountLabel())); err != nil {
+		return fmt.Errorf("mounting secrets tmpfs: %s", err)
+	}
+	if err := os.Chown(secretsPath, rootUID, rootGID); err != nil {
+		return err
+	}
+
+	for i, s := range c.HostConfig.Secrets {
+		data, err := daemon.secrets.Data(s.Name)
+		if err != nil {
+			return err
+		}
+		fPath, err := c.SecretFilePath(i)
+		if err != nil {
+			return err
+		}
+		uid, err := idtools.ToHost(s.UID, uidMaps)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(fPath, data, s.Mode); err != nil {
+			return err
+		}
+		if err := os.Chown(fPath, uid, rootGID); err != nil {
+			return err
+		}
+		// WriteFile is subject to the umask
+		if err := os.Chmod(fPath, s.Mode); err != nil {
+			return err
+		}
+		label.SetFileLabel(fPath, c.MountLabel)
+	}
+	return nil
+}
+
 func (daemon *Daemon) mountVolumes(container *container.Container) error {
 	mounts, err := daemon.setupMounts(container)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/daemon/execdriver/windows"
 	"github.com/docker/docker/layer"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 func (daemon *Daemon) setupLinkedContainers(container *container.Container) ([]string, error) {
@@ -20,12 +21,12 @@ func (daemon *Daemon) setupLinkedContainers(container *container.Container) ([]s
 }
 
 // ConnectToNetwork connects a container to a network
-func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
+func (daemon *Daemon) ConnectToNetwork(ctx context.Context, container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
 	return fmt.Errorf("Windows does not support connecting a running container to a network")
 }
 
 // DisconnectFromNetwork disconnects container from a network.
-func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n libnetwork.Network, force bool) error {
+func (daemon *Daemon) DisconnectFromNetwork(ctx context.Context, container *container.Container, n libnetwork.Network, force bool) error {
 	return fmt.Errorf("Windows does not support disconnecting a running container from a network")
 }
 
@@ -173,6 +174,10 @@ func (daemon *Daemon) setupIpcDirs(container *container.Container) error {
 	return nil
 }
 
+func (daemon *Daemon) setupSecretMounts(container *container.Container) error {
+	return nil
+}
+
 // TODO Windows: Fix Post-TP4. This is a hack to allow docker cp to work
 // against containers which have volumes. You will still be able to cp
 // to somewhere on the container drive, but not to any mounted volumes
@@ -14,10 +14,11 @@ import (
 	containertypes "github.com/docker/engine-api/types/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/opencontainers/runc/libcontainer/label"
+	"golang.org/x/net/context"
 )
 
 // ContainerCreate creates a container.
-func (daemon *Daemon) ContainerCreate(params types.ContainerCreateConfig) (types.ContainerCreateResponse, error) {
+func (daemon *Daemon) ContainerCreate(ctx context.Context, params types.ContainerCreateConfig) (types.ContainerCreateResponse, error) {
 	if params.Config == nil {
 		return types.ContainerCreateResponse{}, fmt.Errorf("Config cannot be empty in order to create a container")
 	}
@@ -40,7 +41,7 @@ func (daemon *Daemon) ContainerCreate(params types.ContainerCreateConfig) (types
 		return types.ContainerCreateResponse{Warnings: warnings}, err
 	}
 
-	container, err := daemon.create(params)
+	container, err := daemon.create(ctx, params)
 	if err != nil {
 		return types.ContainerCreateResponse{Warnings: warnings}, daemon.imageNotExistToErrcode(err)
 	}
@@ -49,7 +50,7 @@ func (daemon *Daemon) ContainerCreate(params types.ContainerCreateConfig) (types
 }
 
 // Create creates a new container from the given configuration with a given name.
-func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *container.Container, retErr error) {
+func (daemon *Daemon) create(ctx context.Context, params types.ContainerCreateConfig) (retC *container.Container, retErr error) {
 	var (
 		container *container.Container
 		img       *image.Image
@@ -63,6 +64,10 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 			return nil, err
 		}
 		imgID = img.ID()
+
+		if err := daemon.verifyImageTrust(params.Config.Image, imgID); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := daemon.mergeAndVerifyConfig(params.Config, img); err != nil {
@@ -74,7 +79,7 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 	}
 	defer func() {
 		if retErr != nil {
-			if err := daemon.ContainerRm(container.ID, &types.ContainerRmConfig{ForceRemove: true}); err != nil {
+			if err := daemon.ContainerRm(ctx, container.ID, &types.ContainerRmConfig{ForceRemove: true}); err != nil {
 				logrus.Errorf("Clean up Error! Cannot destroy container %s: %v", container.ID, err)
 			}
 		}
@@ -84,18 +89,20 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 		return nil, err
 	}
 
+	if err := daemon.allocateUserns(container, params.HostConfig); err != nil {
+		return nil, err
+	}
+
 	// Set RWLayer for container after mount labels have been set
 	if err := daemon.setRWLayer(container); err != nil {
+		daemon.releaseUserns(container)
 		return nil, err
 	}
 
 	if err := daemon.Register(container); err != nil {
 		return nil, err
 	}
-	rootUID, rootGID, err := idtools.GetRootUIDGID(daemon.uidMaps, daemon.gidMaps)
-	if err != nil {
-		return nil, err
-	}
+	rootUID, rootGID := daemon.containerRootUIDGID(container)
 	if err := idtools.MkdirAs(container.Root, 0700, rootUID, rootGID); err != nil {
 		return nil, err
 	}
@@ -128,7 +135,7 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 		logrus.Errorf("Error saving new container to disk: %v", err)
 		return nil, err
 	}
-	daemon.LogContainerEvent(container, "create")
+	daemon.LogContainerEventWithAttributes(container, "create", requestAttributes(ctx, nil))
 	return container, nil
 }
 
@@ -156,7 +163,7 @@ func (daemon *Daemon) setRWLayer(container *container.Container) error {
 		}
 		layerID = img.RootFS.ChainID()
 	}
-	rwLayer, err := daemon.layerStore.CreateRWLayer(container.ID, layerID, container.MountLabel, daemon.setupInitLayer)
+	rwLayer, err := daemon.layerStore.CreateRWLayer(container.ID, layerID, container.MountLabel, daemon.setupInitLayer, layerIDMappings(container))
 	if err != nil {
 		return err
 	}
@@ -167,7 +174,7 @@ func (daemon *Daemon) setRWLayer(container *container.Container) error {
 
 // VolumeCreate creates a volume with the specified name, driver, and opts
 // This is called directly from the remote API
-func (daemon *Daemon) VolumeCreate(name, driverName string, opts map[string]string) (*types.Volume, error) {
+func (daemon *Daemon) VolumeCreate(ctx context.Context, name, driverName string, opts map[string]string) (*types.Volume, error) {
 	if name == "" {
 		name = stringid.GenerateNonCryptoID()
 	}
@@ -180,6 +187,6 @@ func (daemon *Daemon) VolumeCreate(name, driverName string, opts map[string]stri
 		return nil, err
 	}
 
-	daemon.LogVolumeEvent(v.Name(), "create", map[string]string{"driver": v.DriverName()})
+	daemon.LogVolumeEvent(v.Name(), "create", requestAttributes(ctx, map[string]string{"driver": v.DriverName()}))
 	return volumeToAPIType(v), nil
 }
@@ -21,7 +21,7 @@ func (daemon *Daemon) createContainerPlatformSpecificSettings(container *contain
 	}
 	defer daemon.Unmount(container)
 
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	rootUID, rootGID := daemon.containerRootUIDGID(container)
 	if err := container.SetupWorkingDirectory(rootUID, rootGID); err != nil {
 		return err
 	}
@@ -45,7 +45,7 @@ func (daemon *Daemon) createContainerPlatformSpecificSettings(container *contain
 			return fmt.Errorf("cannot mount volume over existing file, file exists %s", path)
 		}
 
-		v, err := daemon.volumes.CreateWithRef(name, hostConfig.VolumeDriver, container.ID, nil)
+		v, err := daemon.createContainerVolume(container, name, hostConfig.VolumeDriver, nil)
 		if err != nil {
 			return err
 		}
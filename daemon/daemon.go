@@ -40,8 +40,10 @@ import (
 	_ "github.com/docker/docker/daemon/graphdriver/register"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/daemon/secrets"
 	"github.com/docker/docker/distribution"
 	dmetadata "github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/trust"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/image"
@@ -49,11 +51,13 @@ import (
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/migrate/v1"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/audit"
 	"github.com/docker/docker/pkg/fileutils"
 	"github.com/docker/docker/pkg/graphdb"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/pkg/namesgenerator"
+	"github.com/docker/docker/pkg/platform"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/registrar"
 	"github.com/docker/docker/pkg/signal"
@@ -114,6 +118,7 @@ type Daemon struct {
 	uploadManager             *xfer.LayerUploadManager
 	distributionMetadataStore dmetadata.Store
 	trustKey                  libtrust.PrivateKey
+	trustVerifier             *trust.Verifier
 	idIndex                   *truncindex.TruncIndex
 	configStore               *Config
 	execDriver                execdriver.Driver
@@ -121,14 +126,19 @@ type Daemon struct {
 	defaultLogConfig          containertypes.LogConfig
 	RegistryService           *registry.Service
 	EventsService             *events.Events
+	AuditLogger               *audit.Logger
 	netController             libnetwork.NetworkController
 	volumes                   *store.VolumeStore
+	secrets                   *secrets.Store
 	discoveryWatcher          discoveryReloader
 	root                      string
 	seccompEnabled            bool
+	seccompLearner            *seccompLearner
+	defaultProfiles           defaultProfiles
 	shutdown                  bool
 	uidMaps                   []idtools.IDMap
 	gidMaps                   []idtools.IDMap
+	usernsPool                *idtools.Pool
 	layerStore                layer.Store
 	imageStore                image.Store
 	nameIndex                 *registrar.Registrar
@@ -245,6 +255,8 @@ func (daemon *Daemon) Register(container *container.Container) error {
 		daemon.execDriver.Terminate(cmd)
 
 		container.UnmountIpcMounts(mount.Unmount)
+		container.UnmountSecrets(mount.Unmount)
+		container.UnmountRootfsView(mount.Unmount)
 
 		daemon.Unmount(container)
 		if err := container.ToDiskLocking(); err != nil {
@@ -289,6 +301,10 @@ func (daemon *Daemon) restore() error {
 				continue
 			}
 			container.RWLayer = rwlayer
+			if err := daemon.reserveUserns(container); err != nil {
+				// the container is loaded, only its start fails
+				logrus.Errorf("Failed to reserve the user namespace of container %v: %v", id, err)
+			}
 			logrus.Debugf("Loaded container %v", container.ID)
 
 			containers[container.ID] = container
@@ -364,7 +380,7 @@ func (daemon *Daemon) restore() error {
 					}
 				}
 			}
-			if err := daemon.containerStart(c); err != nil {
+			if err := daemon.containerStart(context.Background(), c); err != nil {
 				logrus.Errorf("Failed to start container %s: %s", c.ID, err)
 			}
 			close(chNotify)
@@ -416,6 +432,9 @@ func (daemon *Daemon) mergeAndVerifyConfig(config *containertypes.Config, img *i
 	if len(config.Entrypoint) == 0 && len(config.Cmd) == 0 {
 		return fmt.Errorf("No command specified")
 	}
+	// The user attribute of the events is reserved for the authenticated
+	// callers, a label can't impersonate them
+	delete(config.Labels, events.UserAttribute)
 	return nil
 }
 
@@ -633,6 +652,10 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	if err != nil {
 		return nil, err
 	}
+	usernsPool, err := setupUsernsPool(config)
+	if err != nil {
+		return nil, err
+	}
 
 	// get the canonical path to the Docker root directory
 	var realRoot string
@@ -692,6 +715,9 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	if driverName == "" {
 		driverName = config.GraphDriver
 	}
+	if driverName, err = rootlessGraphDriver(config, driverName); err != nil {
+		return nil, err
+	}
 	d.layerStore, err = layer.NewStoreFromOptions(layer.StoreOptions{
 		StorePath:                 config.Root,
 		MetadataStorePathTemplate: filepath.Join(config.Root, "image", "%s", "layerdb"),
@@ -707,6 +733,10 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	graphDriver := d.layerStore.DriverName()
 	imageRoot := filepath.Join(config.Root, "image", graphDriver)
 
+	if err := checkUsernsPoolDriver(config, graphDriver); err != nil {
+		return nil, err
+	}
+
 	// Configure and validate the kernels security support
 	if err := configureKernelSecuritySupport(config, graphDriver); err != nil {
 		return nil, err
@@ -731,6 +761,19 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 		return nil, err
 	}
 
+	if err := setupUsernsPoolDirs(config, daemonRepo); err != nil {
+		return nil, err
+	}
+
+	secretsKeyFile := config.SecretsKeyFile
+	if secretsKeyFile == "" {
+		secretsKeyFile = filepath.Join(config.Root, "secrets.key")
+	}
+	secretStore, err := secrets.New(filepath.Join(config.Root, "secrets"), secretsKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening the secrets store: %v", err)
+	}
+
 	trustKey, err := api.LoadOrCreateTrustKey(config.TrustKeyPath)
 	if err != nil {
 		return nil, err
@@ -742,6 +785,14 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 		return nil, err
 	}
 
+	if config.TrustPolicy != "" {
+		policy, err := trust.LoadPolicy(config.TrustPolicy)
+		if err != nil {
+			return nil, err
+		}
+		d.trustVerifier = trust.NewVerifier(policy, trustDir, registryService)
+	}
+
 	distributionMetadataStore, err := dmetadata.NewFSMetadataStore(filepath.Join(imageRoot, "distribution"))
 	if err != nil {
 		return nil, err
@@ -749,6 +800,11 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 
 	eventsService := events.New()
 
+	auditLogger, err := newAuditLogger(config)
+	if err != nil {
+		return nil, err
+	}
+
 	referenceStore, err := reference.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
 	if err != nil {
 		return nil, fmt.Errorf("Couldn't create Tag store repositories: %s", err)
@@ -804,11 +860,18 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	}
 	d.RegistryService = registryService
 	d.EventsService = eventsService
+	d.AuditLogger = auditLogger
 	d.volumes = volStore
+	d.secrets = secretStore
+	d.seccompLearner = newSeccompLearner()
 	d.root = config.Root
 	d.uidMaps = uidMaps
 	d.gidMaps = gidMaps
+	d.usernsPool = usernsPool
 	d.seccompEnabled = sysInfo.Seccomp
+	if err := d.initDefaultProfiles(config); err != nil {
+		return nil, err
+	}
 
 	d.nameIndex = registrar.NewRegistrar()
 	d.linkIndex = newLinkIndex()
@@ -826,6 +889,7 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 }
 
 func (daemon *Daemon) shutdownContainer(c *container.Container) error {
+	stopTimeout := c.StopTimeout()
 	// TODO(windows): Handle docker restart with paused containers
 	if c.IsPaused() {
 		// To terminate a process in freezer cgroup, we should send
@@ -839,11 +903,11 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 		if err := daemon.kill(c, int(sig)); err != nil {
 			return fmt.Errorf("sending SIGTERM to container %s with error: %v", c.ID, err)
 		}
-		if err := daemon.containerUnpause(c); err != nil {
+		if err := daemon.containerUnpause(context.Background(), c); err != nil {
 			return fmt.Errorf("Failed to unpause container %s with error: %v", c.ID, err)
 		}
-		if _, err := c.WaitStop(10 * time.Second); err != nil {
-			logrus.Debugf("container %s failed to exit in 10 second of SIGTERM, sending SIGKILL to force", c.ID)
+		if _, err := c.WaitStop(time.Duration(stopTimeout) * time.Second); err != nil {
+			logrus.Debugf("container %s failed to exit in %d second of SIGTERM, sending SIGKILL to force", c.ID, stopTimeout)
 			sig, ok := signal.SignalMap["KILL"]
 			if !ok {
 				return fmt.Errorf("System does not support SIGKILL")
@@ -855,8 +919,8 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 			return err
 		}
 	}
-	// If container failed to exit in 10 seconds of SIGTERM, then using the force
-	if err := daemon.containerStop(c, 10); err != nil {
+	// If container failed to exit in stopTimeout seconds of SIGTERM, then using the force
+	if err := daemon.containerStop(context.Background(), c, stopTimeout); err != nil {
 		return fmt.Errorf("Stop container %s with error: %v", c.ID, err)
 	}
 
@@ -864,6 +928,33 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 	return nil
 }
 
+// ShutdownTimeout returns the number of seconds Shutdown is expected to
+// take: the longest stop timeout of the running containers, plus some time
+// to clean up. A negative value means that a container waits forever for a
+// graceful stop.
+func (daemon *Daemon) ShutdownTimeout() int {
+	// By default we use the daemon's shutdown timeout of 15 seconds.
+	shutdownTimeout := 15
+	if daemon.containers == nil {
+		return shutdownTimeout
+	}
+
+	graceTimeout := 5
+	for _, c := range daemon.containers.List() {
+		if !c.IsRunning() {
+			continue
+		}
+		stopTimeout := c.StopTimeout()
+		if stopTimeout < 0 {
+			return -1
+		}
+		if stopTimeout+graceTimeout > shutdownTimeout {
+			shutdownTimeout = stopTimeout + graceTimeout
+		}
+	}
+	return shutdownTimeout
+}
+
 // Shutdown stops the daemon.
 func (daemon *Daemon) Shutdown() error {
 	daemon.shutdown = true
@@ -893,6 +984,12 @@ func (daemon *Daemon) Shutdown() error {
 		}
 	}
 
+	if daemon.AuditLogger != nil {
+		if err := daemon.AuditLogger.Close(); err != nil {
+			logrus.Errorf("Error closing the audit log: %v", err)
+		}
+	}
+
 	if err := daemon.cleanupMounts(); err != nil {
 		return err
 	}
@@ -963,7 +1060,7 @@ func (daemon *Daemon) changes(container *container.Container) ([]archive.Change,
 
 // TagImage creates the tag specified by newTag, pointing to the image named
 // imageName (alternatively, imageName can also be an image ID).
-func (daemon *Daemon) TagImage(newTag reference.Named, imageName string) error {
+func (daemon *Daemon) TagImage(ctx context.Context, newTag reference.Named, imageName string) error {
 	imageID, err := daemon.GetImageID(imageName)
 	if err != nil {
 		return err
@@ -972,7 +1069,7 @@ func (daemon *Daemon) TagImage(newTag reference.Named, imageName string) error {
 		return err
 	}
 
-	daemon.LogImageEvent(imageID.String(), newTag.String(), "tag")
+	daemon.LogImageEventWithAttributes(imageID.String(), newTag.String(), "tag", requestAttributes(ctx, nil))
 	return nil
 }
 
@@ -1007,8 +1104,19 @@ func isBrokenPipe(e error) bool {
 }
 
 // PullImage initiates a pull operation. image is the repository name to pull, and
-// tag may be either empty, or indicate a specific tag to pull.
-func (daemon *Daemon) PullImage(ref reference.Named, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// tag may be either empty, or indicate a specific tag to pull. platformSpec, in
+// the os/arch[/variant] format, selects the manifest list entry to pull; if empty,
+// the daemon's own platform is used.
+func (daemon *Daemon) PullImage(ref reference.Named, platformSpec string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+	var pullPlatform *platform.Spec
+	if platformSpec != "" {
+		p, err := platform.Parse(platformSpec)
+		if err != nil {
+			return err
+		}
+		pullPlatform = &p
+	}
+
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
 	progressChan := make(chan progress.Progress, 100)
@@ -1032,6 +1140,8 @@ func (daemon *Daemon) PullImage(ref reference.Named, metaHeaders map[string][]st
 		ImageStore:       daemon.imageStore,
 		ReferenceStore:   daemon.referenceStore,
 		DownloadManager:  daemon.downloadManager,
+		Platform:         pullPlatform,
+		TrustVerifier:    daemon.trustVerifier,
 	}
 
 	err := distribution.Pull(ctx, ref, imagePullConfig)
@@ -1040,8 +1150,8 @@ func (daemon *Daemon) PullImage(ref reference.Named, metaHeaders map[string][]st
 	return err
 }
 
-// PullOnBuild tells Docker to pull image referenced by `name`.
-func (daemon *Daemon) PullOnBuild(name string, authConfigs map[string]types.AuthConfig, output io.Writer) (builder.Image, error) {
+// PullOnBuild tells Docker to pull image referenced by `name` for `platform`.
+func (daemon *Daemon) PullOnBuild(name string, authConfigs map[string]types.AuthConfig, platform string, output io.Writer) (builder.Image, error) {
 	ref, err := reference.ParseNamed(name)
 	if err != nil {
 		return nil, err
@@ -1063,7 +1173,7 @@ func (daemon *Daemon) PullOnBuild(name string, authConfigs map[string]types.Auth
 		pullRegistryAuth = &resolvedConfig
 	}
 
-	if err := daemon.PullImage(ref, nil, pullRegistryAuth, output); err != nil {
+	if err := daemon.PullImage(ref, platform, nil, pullRegistryAuth, output); err != nil {
 		return nil, err
 	}
 	return daemon.GetImage(name)
@@ -1072,11 +1182,12 @@ func (daemon *Daemon) PullOnBuild(name string, authConfigs map[string]types.Auth
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
-// the same tag are exported. names is the set of tags to export, and
-// outStream is the writer which the images are written to.
-func (daemon *Daemon) ExportImage(names []string, outStream io.Writer) error {
+// the same tag are exported. names is the set of tags to export,
+// excludeBase is the set of images whose layers are left out of the
+// archive, and outStream is the writer which the images are written to.
+func (daemon *Daemon) ExportImage(names []string, excludeBase []string, outStream io.Writer) error {
 	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore)
-	return imageExporter.Save(names, outStream)
+	return imageExporter.Save(names, excludeBase, outStream)
 }
 
 // PushImage initiates a push operation on the repository named localName.
@@ -1114,6 +1225,20 @@ func (daemon *Daemon) PushImage(ref reference.Named, metaHeaders map[string][]st
 	return err
 }
 
+// ImageDeleteRemote deletes the manifest that ref points to from its
+// registry. A reference without a tag or digest refers to the latest tag.
+// With dryRun set, the tags and manifest that would be deleted are returned
+// but nothing is deleted.
+func (daemon *Daemon) ImageDeleteRemote(ref reference.Named, metaHeaders map[string][]string, authConfig *types.AuthConfig, dryRun bool) ([]types.ImageDelete, error) {
+	imageDeleteConfig := &distribution.ImageDeleteConfig{
+		MetaHeaders:     metaHeaders,
+		AuthConfig:      authConfig,
+		RegistryService: daemon.RegistryService,
+		DryRun:          dryRun,
+	}
+	return distribution.Delete(context.Background(), reference.WithDefaultTag(ref), imageDeleteConfig)
+}
+
 // LookupImage looks up an image by name and returns it as an ImageInspect
 // structure.
 func (daemon *Daemon) LookupImage(name string) (*types.ImageInspect, error) {
@@ -1172,6 +1297,7 @@ func (daemon *Daemon) LookupImage(name string) (*types.ImageInspect, error) {
 		Author:          img.Author,
 		Config:          img.Config,
 		Architecture:    img.Architecture,
+		Variant:         img.Variant,
 		Os:              img.OS,
 		Size:            size,
 		VirtualSize:     size, // TODO: field unused, deprecate
@@ -1407,7 +1533,7 @@ func tempDir(rootDir string, rootUID, rootGID int) (string, error) {
 func (daemon *Daemon) setSecurityOptions(container *container.Container, hostConfig *containertypes.HostConfig) error {
 	container.Lock()
 	defer container.Unlock()
-	return parseSecurityOpt(container, hostConfig)
+	return daemon.parseSecurityOpt(container, hostConfig)
 }
 
 func (daemon *Daemon) setHostConfig(container *container.Container, hostConfig *containertypes.HostConfig) error {
@@ -1478,6 +1604,14 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 		return nil, err
 	}
 
+	if err := validateMounts(hostConfig.Mounts); err != nil {
+		return nil, err
+	}
+
+	if err := daemon.verifySecrets(hostConfig); err != nil {
+		return nil, err
+	}
+
 	for port := range hostConfig.PortBindings {
 		_, portStr := nat.SplitProtoPort(string(port))
 		if _, err := nat.ParsePort(portStr); err != nil {
@@ -1549,6 +1683,7 @@ func (daemon *Daemon) GetContainerStats(container *container.Container) (*execdr
 		return nil, err
 	}
 	stats.Interfaces = nwStats
+	stats.EndpointStats = daemon.getEndpointStats(container)
 
 	return stats, nil
 }
@@ -1574,6 +1709,32 @@ func (daemon *Daemon) getNetworkStats(c *container.Container) ([]*libcontainer.N
 	return list, nil
 }
 
+// getEndpointStats returns the interface statistics of each network endpoint
+// of the container, keyed by network name.
+func (daemon *Daemon) getEndpointStats(c *container.Container) map[string]*libcontainer.NetworkInterface {
+	stats := make(map[string]*libcontainer.NetworkInterface)
+
+	for name := range c.NetworkSettings.Networks {
+		n, err := daemon.FindNetwork(name)
+		if err != nil {
+			continue
+		}
+		ep, err := c.GetEndpointInNetwork(n)
+		if err != nil {
+			continue
+		}
+		// Endpoints without an interface in the container, such as
+		// on the host network, have no statistics of their own
+		epStats, err := ep.Statistics()
+		if err != nil {
+			continue
+		}
+		stats[n.Name()] = convertLnNetworkStats(n.Name(), epStats)
+	}
+
+	return stats
+}
+
 // newBaseContainer creates a new container with its initial
 // configuration based on the root storage from the daemon.
 func (daemon *Daemon) newBaseContainer(id string) *container.Container {
@@ -1608,6 +1769,9 @@ func (daemon *Daemon) initDiscovery(config *Config) error {
 func (daemon *Daemon) Reload(config *Config) error {
 	daemon.configStore.reloadLock.Lock()
 	defer daemon.configStore.reloadLock.Unlock()
+	if err := daemon.reloadDefaultProfiles(config); err != nil {
+		return err
+	}
 	if config.IsValueSet("label") {
 		daemon.configStore.Labels = config.Labels
 	}
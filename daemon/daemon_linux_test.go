@@ -3,8 +3,14 @@
 package daemon
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/docker/docker/container"
+	containertypes "github.com/docker/engine-api/types/container"
 )
 
 func TestCleanupMounts(t *testing.T) {
@@ -72,3 +78,83 @@ func TestNotCleanupMounts(t *testing.T) {
 		t.Fatalf("Expected not to clean up /dev/shm")
 	}
 }
+
+func TestDaemonReloadDefaultProfiles(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-default-profiles-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	profile := `{"defaultAction": "SCMP_ACT_ERRNO", "syscalls": [{"name": "read", "action": "SCMP_ACT_ALLOW"}]}`
+	validProfile := filepath.Join(tmp, "valid.json")
+	if err := ioutil.WriteFile(validProfile, []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalidProfile := filepath.Join(tmp, "invalid.json")
+	if err := ioutil.WriteFile(invalidProfile, []byte(`{"defaultAction": "SCMP_ACT_NONE"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalidTemplate := filepath.Join(tmp, "template")
+	if err := ioutil.WriteFile(invalidTemplate, []byte("profile {{.Name flags=(attach_disconnected) {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	daemon := &Daemon{seccompEnabled: true}
+	daemon.configStore = &Config{}
+	c := &container.Container{CommonContainer: container.CommonContainer{HostConfig: &containertypes.HostConfig{}}}
+
+	reload := func(key, value string) error {
+		config := &Config{
+			CommonConfig: CommonConfig{
+				valuesSet: map[string]interface{}{key: value},
+			},
+		}
+		switch key {
+		case "seccomp-profile":
+			config.SeccompProfile = value
+		case "apparmor-template":
+			config.AppArmorTemplate = value
+		}
+		return daemon.Reload(config)
+	}
+
+	if err := reload("seccomp-profile", validProfile); err != nil {
+		t.Fatal(err)
+	}
+	if p := daemon.defaultSeccompProfile(c); p != profile {
+		t.Fatalf("Expected the default seccomp profile %s, got %q", validProfile, p)
+	}
+	if daemon.configStore.SeccompProfile != validProfile {
+		t.Fatalf("Expected the seccomp-profile configuration %s, got %q", validProfile, daemon.configStore.SeccompProfile)
+	}
+
+	c.HostConfig.Privileged = true
+	if p := daemon.defaultSeccompProfile(c); p != "" {
+		t.Fatalf("Expected no default seccomp profile for a privileged container, got %q", p)
+	}
+	c.HostConfig.Privileged = false
+
+	for _, tc := range []struct{ key, value string }{
+		{"seccomp-profile", invalidProfile},
+		{"seccomp-profile", filepath.Join(tmp, "missing.json")},
+		{"apparmor-template", invalidTemplate},
+	} {
+		if err := reload(tc.key, tc.value); err == nil {
+			t.Fatalf("Expected an error reloading %s=%s", tc.key, tc.value)
+		}
+		if p := daemon.defaultSeccompProfile(c); p != profile {
+			t.Fatalf("Expected the default seccomp profile to be kept after reloading %s=%s, got %q", tc.key, tc.value, p)
+		}
+		if daemon.configStore.SeccompProfile != validProfile || daemon.configStore.AppArmorTemplate != "" {
+			t.Fatalf("Expected the configuration to be kept after reloading %s=%s", tc.key, tc.value)
+		}
+	}
+
+	if err := reload("seccomp-profile", ""); err != nil {
+		t.Fatal(err)
+	}
+	if p := daemon.defaultSeccompProfile(c); p != "" {
+		t.Fatalf("Expected the builtin seccomp profile, got %q", p)
+	}
+}
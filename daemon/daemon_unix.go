@@ -16,11 +16,13 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/parsers"
 	"github.com/docker/docker/pkg/parsers/kernel"
+	"github.com/docker/docker/pkg/stringutils"
 	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/runconfig"
@@ -35,6 +37,7 @@ import (
 	"github.com/docker/libnetwork/options"
 	"github.com/docker/libnetwork/types"
 	blkiodev "github.com/opencontainers/runc/libcontainer/configs"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/opencontainers/runc/libcontainer/label"
 	"github.com/opencontainers/runc/libcontainer/user"
 )
@@ -66,6 +69,13 @@ func getBlkioWeightDevices(config *containertypes.HostConfig) ([]*blkiodev.Weigh
 	return blkioWeightDevices, nil
 }
 
+// parseSecurityOpt sets the security options of the container, which has the
+// no-new-privileges setting of the daemon unless it is part of them.
+func (daemon *Daemon) parseSecurityOpt(container *container.Container, hostConfig *containertypes.HostConfig) error {
+	container.NoNewPrivileges = daemon.configStore.NoNewPrivileges
+	return parseSecurityOpt(container, hostConfig)
+}
+
 func parseSecurityOpt(container *container.Container, config *containertypes.HostConfig) error {
 	var (
 		labelOpts []string
@@ -73,7 +83,7 @@ func parseSecurityOpt(container *container.Container, config *containertypes.Hos
 	)
 
 	for _, opt := range config.SecurityOpt {
-		con := strings.SplitN(opt, ":", 2)
+		con := runconfigopts.SplitSecurityOpt(opt)
 		if len(con) == 1 {
 			switch con[0] {
 			case "no-new-privileges":
@@ -83,11 +93,20 @@ func parseSecurityOpt(container *container.Container, config *containertypes.Hos
 			}
 		} else {
 			switch con[0] {
+			case "no-new-privileges":
+				noNewPrivileges, err := strconv.ParseBool(con[1])
+				if err != nil {
+					return fmt.Errorf("Invalid --security-opt 2: %q", opt)
+				}
+				container.NoNewPrivileges = noNewPrivileges
 			case "label":
 				labelOpts = append(labelOpts, con[1])
 			case "apparmor":
 				container.AppArmorProfile = con[1]
 			case "seccomp":
+				if err := validateSeccompLearn(con[1]); err != nil {
+					return err
+				}
 				container.SeccompProfile = con[1]
 			default:
 				return fmt.Errorf("Invalid --security-opt 2: %q", opt)
@@ -313,6 +332,26 @@ func verifyContainerResources(resources *containertypes.Resources, sysInfo *sysi
 		logrus.Warnf("Your kernel does not support CPU cfs quota. Quota discarded.")
 		resources.CPUQuota = 0
 	}
+	if resources.NanoCPUs > 0 && (resources.CPUPeriod > 0 || resources.CPUQuota > 0) {
+		return warnings, fmt.Errorf("Conflicting options: CPUs and CPU period or quota cannot both be set.")
+	}
+	if resources.NanoCPUs > 0 && (!sysInfo.CPUCfsPeriod || !sysInfo.CPUCfsQuota) {
+		return warnings, fmt.Errorf("NanoCPUs can not be set, as your kernel does not support CPU cfs period/quota or the cgroup is not mounted.")
+	}
+	// The number of CPUs is the largest quota that makes sense; a quota
+	// below 1% of a CPU is rounded to nothing by the CFS scheduler.
+	if resources.NanoCPUs < 0 || (resources.NanoCPUs > 0 && resources.NanoCPUs < 1e7) || resources.NanoCPUs > int64(runtime.NumCPU())*1e9 {
+		return warnings, fmt.Errorf("Range of CPUs is from 0.01 to %d.00, as there are only %d CPUs available.", runtime.NumCPU(), runtime.NumCPU())
+	}
+	if resources.CPURealtimePeriod > 0 && !sysInfo.CPURealtimePeriod {
+		return warnings, fmt.Errorf("Your kernel does not support cgroup cpu real-time period.")
+	}
+	if resources.CPURealtimeRuntime > 0 && !sysInfo.CPURealtimeRuntime {
+		return warnings, fmt.Errorf("Your kernel does not support cgroup cpu real-time runtime.")
+	}
+	if resources.CPURealtimePeriod != 0 && resources.CPURealtimeRuntime != 0 && resources.CPURealtimeRuntime > resources.CPURealtimePeriod {
+		return warnings, fmt.Errorf("CPU real-time runtime cannot be higher than CPU real-time period.")
+	}
 
 	// cpuset subsystem checks and adjustments
 	if (resources.CpusetCpus != "" || resources.CpusetMems != "") && !sysInfo.Cpuset {
@@ -424,12 +463,25 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 	if hostConfig.OomScoreAdj < -1000 || hostConfig.OomScoreAdj > 1000 {
 		return warnings, fmt.Errorf("Invalid value %d, range for oom score adj is [-1000, 1000].", hostConfig.OomScoreAdj)
 	}
+	if len(hostConfig.CapAmbient) > 0 {
+		if runtime.GOOS != "linux" || !checkKernelVersion(4, 3, 0) {
+			return warnings, fmt.Errorf("Ambient capabilities require Linux 4.3 or newer")
+		}
+		for _, cap := range hostConfig.CapAmbient {
+			if !stringutils.InSlice(execdriver.GetAllCapabilities(), strings.TrimPrefix(strings.ToUpper(cap), "CAP_")) {
+				return warnings, fmt.Errorf("Unknown ambient capability: %q", cap)
+			}
+		}
+	}
 	if sysInfo.IPv4ForwardingDisabled {
 		warnings = append(warnings, "IPv4 forwarding is disabled. Networking will not work.")
 		logrus.Warnf("IPv4 forwarding is disabled. Networking will not work")
 	}
+	if hostConfig.UsernsMode.IsPerContainer() && daemon.configStore.UsernsPool == "" {
+		return warnings, fmt.Errorf("A private user namespace requires the daemon to be started with --userns-pool")
+	}
 	// check for various conflicting options with user namespaces
-	if daemon.configStore.RemappedRoot != "" {
+	if daemon.configStore.RemappedRoot != "" || hostConfig.UsernsMode.IsPerContainer() {
 		if hostConfig.Privileged {
 			return warnings, fmt.Errorf("Privileged mode is incompatible with user namespaces")
 		}
@@ -467,11 +519,89 @@ func verifyDaemonSettings(config *Config) error {
 	if !config.bridgeConfig.EnableIPTables && config.bridgeConfig.EnableIPMasq {
 		config.bridgeConfig.EnableIPMasq = false
 	}
+	if config.Rootless {
+		if config.RemappedRoot != "" || config.UsernsPool != "" {
+			return fmt.Errorf("--rootless cannot be used with --userns-remap or --userns-pool")
+		}
+		if usingSystemd(config) {
+			return fmt.Errorf("The systemd cgroup driver is not supported by a rootless daemon")
+		}
+		if config.CPURealtimePeriod != 0 || config.CPURealtimeRuntime != 0 {
+			return fmt.Errorf("cpu-rt-period and cpu-rt-runtime are not supported by a rootless daemon")
+		}
+	}
+	switch config.bridgeConfig.IPv6PublishMode {
+	case "", bridge.IPv6PublishNAT, bridge.IPv6PublishRouted:
+	default:
+		return fmt.Errorf("Invalid --ipv6-publish mode %q: must be %q or %q", config.bridgeConfig.IPv6PublishMode, bridge.IPv6PublishNAT, bridge.IPv6PublishRouted)
+	}
+	if config.bridgeConfig.IPv6PublishMode != "" && !config.bridgeConfig.EnableIPTables {
+		return fmt.Errorf("You specified --iptables=false with --ipv6-publish. Publishing ports on IPv6 uses ip6tables to function. Please set --iptables to true")
+	}
 	if config.CgroupParent != "" && usingSystemd(config) {
 		if len(config.CgroupParent) <= 6 || !strings.HasSuffix(config.CgroupParent, ".slice") {
 			return fmt.Errorf("cgroup-parent for systemd cgroup should be a valid slice named as \"xxx.slice\"")
 		}
 	}
+	if config.CPURealtimePeriod != 0 || config.CPURealtimeRuntime != 0 {
+		if usingSystemd(config) {
+			return fmt.Errorf("cpu-rt-period and cpu-rt-runtime are not supported with the systemd cgroup driver")
+		}
+		sysInfo := sysinfo.New(true)
+		if config.CPURealtimePeriod != 0 && !sysInfo.CPURealtimePeriod {
+			return fmt.Errorf("Your kernel does not support cgroup cpu real-time period")
+		}
+		if config.CPURealtimeRuntime != 0 && !sysInfo.CPURealtimeRuntime {
+			return fmt.Errorf("Your kernel does not support cgroup cpu real-time runtime")
+		}
+		if config.CPURealtimePeriod != 0 && config.CPURealtimeRuntime > config.CPURealtimePeriod {
+			return fmt.Errorf("cpu-rt-runtime cannot be higher than cpu-rt-period")
+		}
+	}
+	return nil
+}
+
+// initCgroupsPath sets the daemon's real-time period and runtime on the
+// cgroup at path and on each of its parents, creating them as needed. The
+// kernel only lets a cgroup run real-time tasks for as long as its parent
+// allows, and gives new cgroups no real-time runtime at all.
+func (daemon *Daemon) initCgroupsPath(path string) error {
+	if path == "/" || path == "." {
+		return nil
+	}
+	if daemon.configStore.CPURealtimePeriod == 0 && daemon.configStore.CPURealtimeRuntime == 0 {
+		return nil
+	}
+
+	// Parents are set first, as they bound what their children can be set to.
+	if err := daemon.initCgroupsPath(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	mnt, root, err := cgroups.FindCgroupMountpointAndRoot("cpu")
+	if err != nil {
+		return err
+	}
+	// When docker runs inside docker, the root is the cgroup of the
+	// outer container.
+	if strings.HasPrefix(root, "/docker/") {
+		root = "/"
+	}
+
+	path = filepath.Join(mnt, root, path)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	if rtPeriod := daemon.configStore.CPURealtimePeriod; rtPeriod != 0 {
+		if err := ioutil.WriteFile(filepath.Join(path, "cpu.rt_period_us"), []byte(strconv.FormatInt(rtPeriod, 10)), 0700); err != nil {
+			return err
+		}
+	}
+	if rtRuntime := daemon.configStore.CPURealtimeRuntime; rtRuntime != 0 {
+		if err := ioutil.WriteFile(filepath.Join(path, "cpu.rt_runtime_us"), []byte(strconv.FormatInt(rtRuntime, 10)), 0700); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -500,6 +630,33 @@ func configureMaxThreads(config *Config) error {
 	return nil
 }
 
+// initDefaultProfiles sets the default seccomp profile and AppArmor template
+// of the containers from the configuration.
+func (daemon *Daemon) initDefaultProfiles(config *Config) error {
+	return daemon.setDefaultProfiles(config.SeccompProfile, config.AppArmorTemplate)
+}
+
+// reloadDefaultProfiles validates and sets the default seccomp profile and
+// AppArmor template changed in the configuration file.
+func (daemon *Daemon) reloadDefaultProfiles(config *Config) error {
+	if !config.IsValueSet("seccomp-profile") && !config.IsValueSet("apparmor-template") {
+		return nil
+	}
+	seccompPath, apparmorPath := daemon.configStore.SeccompProfile, daemon.configStore.AppArmorTemplate
+	if config.IsValueSet("seccomp-profile") {
+		seccompPath = config.SeccompProfile
+	}
+	if config.IsValueSet("apparmor-template") {
+		apparmorPath = config.AppArmorTemplate
+	}
+	if err := daemon.setDefaultProfiles(seccompPath, apparmorPath); err != nil {
+		return err
+	}
+	daemon.configStore.SeccompProfile = seccompPath
+	daemon.configStore.AppArmorTemplate = apparmorPath
+	return nil
+}
+
 // configureKernelSecuritySupport configures and validate security support for the kernel
 func configureKernelSecuritySupport(config *Config, driverName string) error {
 	if config.EnableSelinuxSupport {
@@ -553,7 +710,8 @@ func driverOptions(config *Config) []nwconfig.Option {
 	bridgeConfig := options.Generic{
 		"EnableIPForwarding":  config.bridgeConfig.EnableIPForward,
 		"EnableIPTables":      config.bridgeConfig.EnableIPTables,
-		"EnableUserlandProxy": config.bridgeConfig.EnableUserlandProxy}
+		"EnableUserlandProxy": config.bridgeConfig.EnableUserlandProxy,
+		"IPv6PublishMode":     config.bridgeConfig.IPv6PublishMode}
 	bridgeOption := options.Generic{netlabel.GenericData: bridgeConfig}
 
 	dOptions := []nwconfig.Option{}
@@ -132,6 +132,42 @@ func TestParseSecurityOpt(t *testing.T) {
 	}
 }
 
+func TestParseSecurityOptNoNewPrivileges(t *testing.T) {
+	daemon := &Daemon{configStore: &Config{}}
+	daemon.configStore.NoNewPrivileges = true
+	c := &container.Container{}
+	config := &containertypes.HostConfig{}
+
+	if err := daemon.parseSecurityOpt(c, config); err != nil {
+		t.Fatalf("Unexpected parseSecurityOpt error: %v", err)
+	}
+	if !c.NoNewPrivileges {
+		t.Fatal("Expected the no-new-privileges default of the daemon to be set")
+	}
+
+	config.SecurityOpt = []string{"no-new-privileges=false"}
+	if err := daemon.parseSecurityOpt(c, config); err != nil {
+		t.Fatalf("Unexpected parseSecurityOpt error: %v", err)
+	}
+	if c.NoNewPrivileges {
+		t.Fatal("Expected no-new-privileges=false to override the default of the daemon")
+	}
+
+	daemon.configStore.NoNewPrivileges = false
+	config.SecurityOpt = []string{"no-new-privileges"}
+	if err := daemon.parseSecurityOpt(c, config); err != nil {
+		t.Fatalf("Unexpected parseSecurityOpt error: %v", err)
+	}
+	if !c.NoNewPrivileges {
+		t.Fatal("Expected no-new-privileges to be set")
+	}
+
+	config.SecurityOpt = []string{"no-new-privileges=maybe"}
+	if err := daemon.parseSecurityOpt(c, config); err == nil {
+		t.Fatal("Expected parseSecurityOpt error, got nil")
+	}
+}
+
 func TestNetworkOptions(t *testing.T) {
 	daemon := &Daemon{}
 	dconfigCorrect := &Config{
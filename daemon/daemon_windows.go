@@ -43,6 +43,10 @@ func getBlkioWeightDevices(config *containertypes.HostConfig) ([]*blkiodev.Weigh
 	return nil, nil
 }
 
+func (daemon *Daemon) parseSecurityOpt(container *container.Container, hostConfig *containertypes.HostConfig) error {
+	return parseSecurityOpt(container, hostConfig)
+}
+
 func parseSecurityOpt(container *container.Container, config *containertypes.HostConfig) error {
 	return nil
 }
@@ -121,6 +125,14 @@ func checkSystem() error {
 	return nil
 }
 
+func (daemon *Daemon) initDefaultProfiles(config *Config) error {
+	return nil
+}
+
+func (daemon *Daemon) reloadDefaultProfiles(config *Config) error {
+	return nil
+}
+
 // configureKernelSecuritySupport configures and validate security support for the kernel
 func configureKernelSecuritySupport(config *Config, driverName string) error {
 	return nil
@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/rootless"
+	aaprofile "github.com/docker/docker/profiles/apparmor"
+	"github.com/docker/docker/profiles/seccomp"
+	"github.com/docker/engine-api/types"
+	"github.com/opencontainers/runc/libcontainer/apparmor"
+)
+
+const (
+	// defaultApparmorProfile is the name of the AppArmor profile confining
+	// the containers which don't set their own.
+	defaultApparmorProfile = "docker-default"
+	// builtinProfile is reported in place of the path of the default
+	// seccomp profile and AppArmor template when they aren't configured.
+	builtinProfile = "builtin"
+)
+
+// defaultProfiles holds the seccomp profile and the AppArmor template
+// confining the containers which don't set their own profiles, as configured
+// with --seccomp-profile and --apparmor-template.
+type defaultProfiles struct {
+	mu sync.RWMutex
+	// seccompPath is the path of the seccomp profile, empty for the builtin
+	// one, and seccomp its content.
+	seccompPath string
+	seccomp     string
+	// apparmorPath is the path of the AppArmor template, empty for the
+	// builtin one.
+	apparmorPath string
+}
+
+// loadSeccompProfile reads and validates the seccomp profile at path.
+func loadSeccompProfile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Error reading the default seccomp profile: %v", err)
+	}
+	if _, err := seccomp.LoadProfile(string(b)); err != nil {
+		return "", fmt.Errorf("Invalid default seccomp profile %s: %v", path, err)
+	}
+	return string(b), nil
+}
+
+// loadAppArmorTemplate reads and validates the AppArmor template at path.
+func loadAppArmorTemplate(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Error reading the AppArmor template: %v", err)
+	}
+	if err := aaprofile.ValidateTemplate(string(b)); err != nil {
+		return "", fmt.Errorf("Invalid AppArmor template %s: %v", path, err)
+	}
+	return string(b), nil
+}
+
+// setDefaultProfiles validates the seccomp profile and the AppArmor template
+// at the given paths, the builtin ones being used for empty paths, and makes
+// them the defaults of the containers. The AppArmor profile generated from
+// the template is installed right away, so it also applies to the running
+// containers. Nothing is changed if any of them is invalid.
+func (daemon *Daemon) setDefaultProfiles(seccompPath, apparmorPath string) error {
+	var seccompProfile, apparmorTemplate string
+	if seccompPath != "" {
+		p, err := loadSeccompProfile(seccompPath)
+		if err != nil {
+			return err
+		}
+		if !daemon.seccompEnabled {
+			logrus.Warnf("Seccomp is not enabled in the daemon or the kernel, the default seccomp profile %s won't be applied", seccompPath)
+		}
+		seccompProfile = p
+	}
+	if apparmorPath != "" {
+		t, err := loadAppArmorTemplate(apparmorPath)
+		if err != nil {
+			return err
+		}
+		apparmorTemplate = t
+	}
+
+	daemon.defaultProfiles.mu.Lock()
+	defer daemon.defaultProfiles.mu.Unlock()
+
+	// The builtin profile was installed by the execution driver, a custom
+	// template is installed every time as the file may have changed.
+	if apparmorPath != "" || daemon.defaultProfiles.apparmorPath != "" {
+		switch {
+		case !apparmor.IsEnabled() || rootless.Running():
+			if apparmorPath != "" {
+				logrus.Warnf("AppArmor is not enabled on the host, the AppArmor template %s won't be applied", apparmorPath)
+			}
+		case apparmorPath == "":
+			if err := aaprofile.InstallDefault(defaultApparmorProfile); err != nil {
+				return fmt.Errorf("Error installing the %s AppArmor profile: %v", defaultApparmorProfile, err)
+			}
+		default:
+			if err := aaprofile.InstallTemplate(defaultApparmorProfile, apparmorTemplate); err != nil {
+				return fmt.Errorf("Error installing the %s AppArmor profile from %s: %v", defaultApparmorProfile, apparmorPath, err)
+			}
+		}
+	}
+
+	daemon.defaultProfiles.seccompPath = seccompPath
+	daemon.defaultProfiles.seccomp = seccompProfile
+	daemon.defaultProfiles.apparmorPath = apparmorPath
+	return nil
+}
+
+// defaultSeccompProfile returns the seccomp profile of the container when it
+// doesn't set one. It is empty for the builtin profile, as well as for
+// privileged containers which run unconfined.
+func (daemon *Daemon) defaultSeccompProfile(c *container.Container) string {
+	if c.HostConfig.Privileged {
+		return ""
+	}
+	daemon.defaultProfiles.mu.RLock()
+	defer daemon.defaultProfiles.mu.RUnlock()
+	return daemon.defaultProfiles.seccomp
+}
+
+// fillDefaultProfilesInfo reports the default seccomp profile and AppArmor
+// template, when seccomp and AppArmor are enabled.
+func (daemon *Daemon) fillDefaultProfilesInfo(v *types.Info) {
+	daemon.defaultProfiles.mu.RLock()
+	defer daemon.defaultProfiles.mu.RUnlock()
+	if daemon.seccompEnabled {
+		v.SeccompProfile = builtinProfile
+		if daemon.defaultProfiles.seccompPath != "" {
+			v.SeccompProfile = daemon.defaultProfiles.seccompPath
+		}
+	}
+	if apparmor.IsEnabled() && !rootless.Running() {
+		v.AppArmorTemplate = builtinProfile
+		if daemon.defaultProfiles.apparmorPath != "" {
+			v.AppArmorTemplate = daemon.defaultProfiles.apparmorPath
+		}
+	}
+}
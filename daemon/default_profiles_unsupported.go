@@ -0,0 +1,26 @@
+// +build !linux
+
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/engine-api/types"
+)
+
+type defaultProfiles struct{}
+
+func (daemon *Daemon) setDefaultProfiles(seccompPath, apparmorPath string) error {
+	if seccompPath != "" || apparmorPath != "" {
+		return fmt.Errorf("default seccomp profiles and AppArmor templates are only supported on Linux")
+	}
+	return nil
+}
+
+func (daemon *Daemon) defaultSeccompProfile(c *container.Container) string {
+	return ""
+}
+
+func (daemon *Daemon) fillDefaultProfilesInfo(v *types.Info) {
+}
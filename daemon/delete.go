@@ -12,13 +12,14 @@ import (
 	"github.com/docker/docker/layer"
 	volumestore "github.com/docker/docker/volume/store"
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 // ContainerRm removes the container id from the filesystem. An error
 // is returned if the container is not found, or if the remove
 // fails. If the remove succeeds, the container name is released, and
 // network links are removed.
-func (daemon *Daemon) ContainerRm(name string, config *types.ContainerRmConfig) error {
+func (daemon *Daemon) ContainerRm(ctx context.Context, name string, config *types.ContainerRmConfig) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -39,7 +40,7 @@ func (daemon *Daemon) ContainerRm(name string, config *types.ContainerRmConfig)
 		return daemon.rmLink(container, name)
 	}
 
-	err = daemon.cleanupContainer(container, config.ForceRemove)
+	err = daemon.cleanupContainer(ctx, container, config.ForceRemove)
 	if err == nil || config.ForceRemove {
 		if e := daemon.removeMountPoints(container, config.RemoveVolume); e != nil {
 			logrus.Error(e)
@@ -77,13 +78,13 @@ func (daemon *Daemon) rmLink(container *container.Container, name string) error
 
 // cleanupContainer unregisters a container from the daemon, stops stats
 // collection and cleanly removes contents and metadata from the filesystem.
-func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemove bool) (err error) {
+func (daemon *Daemon) cleanupContainer(ctx context.Context, container *container.Container, forceRemove bool) (err error) {
 	if container.IsRunning() {
 		if !forceRemove {
 			err := fmt.Errorf("You cannot remove a running container %s. Stop the container before attempting removal or use -f", container.ID)
 			return errors.NewRequestConflictError(err)
 		}
-		if err := daemon.Kill(container); err != nil {
+		if err := daemon.Kill(ctx, container); err != nil {
 			return fmt.Errorf("Could not kill running container %s, cannot remove - %v", container.ID, err)
 		}
 	}
@@ -92,7 +93,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 	// if stats are currently getting collected.
 	daemon.statsCollector.stopCollection(container)
 
-	if err = daemon.containerStop(container, 3); err != nil {
+	if err = daemon.containerStop(ctx, container, 3); err != nil {
 		return err
 	}
 
@@ -115,7 +116,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 			selinuxFreeLxcContexts(container.ProcessLabel)
 			daemon.idIndex.Delete(container.ID)
 			daemon.containers.Delete(container.ID)
-			daemon.LogContainerEvent(container, "destroy")
+			daemon.LogContainerEventWithAttributes(container, "destroy", requestAttributes(ctx, nil))
 		}
 	}()
 
@@ -128,6 +129,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 	if err != nil && err != layer.ErrMountDoesNotExist {
 		return fmt.Errorf("Driver %s failed to remove root filesystem %s: %s", daemon.GraphDriverName(), container.ID, err)
 	}
+	daemon.releaseUserns(container)
 
 	if err = daemon.execDriver.Clean(container.ID); err != nil {
 		return fmt.Errorf("Unable to remove execdriver data for %s: %s", container.ID, err)
@@ -138,7 +140,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 // VolumeRm removes the volume with the given name.
 // If the volume is referenced by a container it is not removed
 // This is called directly from the remote API
-func (daemon *Daemon) VolumeRm(name string) error {
+func (daemon *Daemon) VolumeRm(ctx context.Context, name string) error {
 	v, err := daemon.volumes.Get(name)
 	if err != nil {
 		return err
@@ -151,6 +153,6 @@ func (daemon *Daemon) VolumeRm(name string) error {
 		}
 		return fmt.Errorf("Error while removing volume %s: %v", name, err)
 	}
-	daemon.LogVolumeEvent(v.Name(), "destroy", map[string]string{"driver": v.DriverName()})
+	daemon.LogVolumeEvent(v.Name(), "destroy", requestAttributes(ctx, map[string]string{"driver": v.DriverName()}))
 	return nil
 }
@@ -4,8 +4,11 @@ import (
 	"strings"
 
 	"github.com/docker/docker/container"
+	daemonevents "github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/pkg/authentication"
 	"github.com/docker/engine-api/types/events"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 // LogContainerEvent generates an event related to a container with only the default attributes.
@@ -77,12 +80,29 @@ func (daemon *Daemon) LogNetworkEventWithAttributes(nw libnetwork.Network, actio
 	daemon.EventsService.Log(action, events.NetworkEventType, actor)
 }
 
+// requestAttributes adds the name of the user authenticated for the request
+// of ctx to the attributes of the event the request causes.
+func requestAttributes(ctx context.Context, attributes map[string]string) map[string]string {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	if id := authentication.FromContext(ctx); id != nil {
+		attributes[daemonevents.UserAttribute] = id.User
+	}
+	return attributes
+}
+
 // copyAttributes guarantees that labels are not mutated by event triggers.
+// The user attribute is never taken from the labels, so that they can't
+// impersonate the caller of a request.
 func copyAttributes(attributes, labels map[string]string) {
 	if labels == nil {
 		return
 	}
 	for k, v := range labels {
+		if k == daemonevents.UserAttribute {
+			continue
+		}
 		attributes[k] = v
 	}
 }
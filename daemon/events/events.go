@@ -20,6 +20,11 @@ type Events struct {
 	pub    *pubsub.Publisher
 }
 
+// UserAttribute is the attribute of the events carrying the name of the user
+// whose request changed the resource. It is in the namespace reserved for
+// Docker so that it can't collide with the labels of the resources.
+const UserAttribute = "com.docker.user"
+
 // New returns new *Events instance
 func New() *Events {
 	return &Events{
@@ -6,8 +6,10 @@ import (
 
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/pkg/authentication"
 	containertypes "github.com/docker/engine-api/types/container"
 	eventtypes "github.com/docker/engine-api/types/events"
+	"golang.org/x/net/context"
 )
 
 func TestLogContainerEventCopyLabels(t *testing.T) {
@@ -75,6 +77,44 @@ func TestLogContainerEventWithAttributes(t *testing.T) {
 	})
 }
 
+func TestLogContainerEventWithRequestAttributes(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	container := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:   "container_id",
+			Name: "container_name",
+			Config: &containertypes.Config{
+				Labels: map[string]string{
+					events.UserAttribute: "mallory",
+				},
+			},
+		},
+	}
+	daemon := &Daemon{
+		EventsService: e,
+	}
+	ctx := authentication.NewContext(context.Background(), &authentication.Identity{User: "alice"})
+	daemon.LogContainerEventWithAttributes(container, "stop", requestAttributes(ctx, nil))
+
+	validateTestAttributes(t, l, map[string]string{
+		events.UserAttribute: "alice",
+	})
+
+	// Without an authenticated request the label is not used either
+	daemon.LogContainerEvent(container, "die")
+	select {
+	case ev := <-l:
+		if user, ok := ev.(eventtypes.Message).Actor.Attributes[events.UserAttribute]; ok {
+			t.Fatalf("Expected the event not to be attributed, got %q", user)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("LogEvent test timed out")
+	}
+}
+
 func validateTestAttributes(t *testing.T, l chan interface{}, expectedAttributesToTest map[string]string) {
 	select {
 	case ev := <-l:
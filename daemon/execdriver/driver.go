@@ -103,6 +103,9 @@ type ResourceStats struct {
 	Read        time.Time `json:"read"`
 	MemoryLimit int64     `json:"memory_limit"`
 	SystemUsage uint64    `json:"system_usage"`
+	// EndpointStats holds the interface statistics of the container's
+	// network endpoints, keyed by network name.
+	EndpointStats map[string]*libcontainer.NetworkInterface `json:"endpoint_stats,omitempty"`
 }
 
 // CommonProcessConfig is the common platform agnostic part of the ProcessConfig
@@ -48,6 +48,9 @@ type Resources struct {
 	CpusetCpus                   string                     `json:"cpuset_cpus"`
 	CpusetMems                   string                     `json:"cpuset_mems"`
 	CPUPeriod                    int64                      `json:"cpu_period"`
+	CPURealtimePeriod            int64                      `json:"cpu_rt_period"`
+	CPURealtimeRuntime           int64                      `json:"cpu_rt_runtime"`
+	NanoCPUs                     int64                      `json:"nano_cpus"`
 	Rlimits                      []*units.Rlimit            `json:"rlimits"`
 	OomKillDisable               bool                       `json:"oom_kill_disable"`
 	PidsLimit                    int64                      `json:"pids_limit"`
@@ -113,10 +116,12 @@ type Command struct {
 	AppArmorProfile    string            `json:"apparmor_profile"`
 	AutoCreatedDevices []*configs.Device `json:"autocreated_devices"`
 	CapAdd             []string          `json:"cap_add"`
+	CapAmbient         []string          `json:"cap_ambient"`
 	CapDrop            []string          `json:"cap_drop"`
 	CgroupParent       string            `json:"cgroup_parent"` // The parent cgroup for this command.
 	GIDMapping         []idtools.IDMap   `json:"gidmapping"`
 	GroupAdd           []string          `json:"group_add"`
+	Init               bool              `json:"init"`
 	Ipc                *Ipc              `json:"ipc"`
 	OomScoreAdj        int               `json:"oom_score_adj"`
 	Pid                *Pid              `json:"pid"`
@@ -184,6 +189,10 @@ func getEnv(key string, env []string) string {
 	return ""
 }
 
+// defaultCPUPeriod is the CFS period (in usecs) used to enforce a quota
+// given in CPUs.
+const defaultCPUPeriod = 100000
+
 // SetupCgroups setups cgroup resources for a container.
 func SetupCgroups(container *configs.Config, c *Command) error {
 	if c.Resources != nil {
@@ -196,6 +205,14 @@ func SetupCgroups(container *configs.Config, c *Command) error {
 		container.Cgroups.Resources.CpusetMems = c.Resources.CpusetMems
 		container.Cgroups.Resources.CpuPeriod = c.Resources.CPUPeriod
 		container.Cgroups.Resources.CpuQuota = c.Resources.CPUQuota
+		if c.Resources.NanoCPUs > 0 {
+			// The quota is given in CPUs, translate it to a CFS quota
+			// over the default period.
+			container.Cgroups.Resources.CpuPeriod = defaultCPUPeriod
+			container.Cgroups.Resources.CpuQuota = c.Resources.NanoCPUs * defaultCPUPeriod / 1e9
+		}
+		container.Cgroups.Resources.CpuRtPeriod = c.Resources.CPURealtimePeriod
+		container.Cgroups.Resources.CpuRtRuntime = c.Resources.CPURealtimeRuntime
 		container.Cgroups.Resources.BlkioWeight = c.Resources.BlkioWeight
 		container.Cgroups.Resources.BlkioWeightDevice = c.Resources.BlkioWeightDevice
 		container.Cgroups.Resources.BlkioThrottleReadBpsDevice = c.Resources.BlkioThrottleReadBpsDevice
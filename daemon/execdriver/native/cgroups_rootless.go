@@ -0,0 +1,89 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+var errCgroupsNotDelegated = fmt.Errorf("The cgroups are not delegated to the user running the daemon")
+
+// cgroupsDelegated reports whether the user running the daemon can create
+// cgroups under its own cgroup in all the mounted hierarchies.
+func cgroupsDelegated() bool {
+	mounts, err := cgroups.GetCgroupMounts()
+	if err != nil || len(mounts) == 0 {
+		return false
+	}
+	for _, m := range mounts {
+		if len(m.Subsystems) == 0 {
+			continue
+		}
+		dir, err := cgroups.GetThisCgroupDir(m.Subsystems[0])
+		if err != nil {
+			return false
+		}
+		rel, err := filepath.Rel(m.Root, dir)
+		if err != nil {
+			return false
+		}
+		if err := syscall.Access(filepath.Join(m.Mountpoint, rel), 2 /* W_OK */); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// noCgroups makes the containers run in the cgroups of the daemon, without
+// resource limits, when they are not delegated to a rootless daemon.
+func noCgroups(l *libcontainer.LinuxFactory) error {
+	l.NewCgroupsManager = func(config *configs.Cgroup, paths map[string]string) cgroups.Manager {
+		return &noCgroupsManager{}
+	}
+	return nil
+}
+
+// noCgroupsManager is the cgroups manager of noCgroups. The operations that
+// can't be done without cgroups, like pausing, fail.
+type noCgroupsManager struct{}
+
+func (m *noCgroupsManager) Apply(pid int) error {
+	return nil
+}
+
+func (m *noCgroupsManager) GetPids() ([]int, error) {
+	return nil, errCgroupsNotDelegated
+}
+
+func (m *noCgroupsManager) GetAllPids() ([]int, error) {
+	return nil, errCgroupsNotDelegated
+}
+
+func (m *noCgroupsManager) GetStats() (*cgroups.Stats, error) {
+	return cgroups.NewStats(), nil
+}
+
+func (m *noCgroupsManager) Freeze(state configs.FreezerState) error {
+	if state == configs.Thawed {
+		return nil
+	}
+	return errCgroupsNotDelegated
+}
+
+func (m *noCgroupsManager) Destroy() error {
+	return nil
+}
+
+func (m *noCgroupsManager) GetPaths() map[string]string {
+	return map[string]string{}
+}
+
+func (m *noCgroupsManager) Set(container *configs.Config) error {
+	return nil
+}
@@ -10,6 +10,9 @@ import (
 
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/docker/docker/pkg/rootless"
+	"github.com/docker/docker/pkg/stringutils"
 	"github.com/docker/docker/profiles/seccomp"
 
 	"github.com/docker/docker/volume"
@@ -77,6 +80,9 @@ func (d *Driver) createContainer(c *execdriver.Command, hooks execdriver.Hooks)
 			}
 		}
 	}
+	if err := d.setAmbientCapabilities(container, c); err != nil {
+		return nil, err
+	}
 	// add CAP_ prefix to all caps for new libcontainer update to match
 	// the spec format.
 	for i, s := range container.Capabilities {
@@ -84,6 +90,9 @@ func (d *Driver) createContainer(c *execdriver.Command, hooks execdriver.Hooks)
 			container.Capabilities[i] = fmt.Sprintf("CAP_%s", s)
 		}
 	}
+	for i, s := range container.AmbientCapabilities {
+		container.AmbientCapabilities[i] = fmt.Sprintf("CAP_%s", s)
+	}
 	container.AdditionalGroups = c.GroupAdd
 
 	if c.AppArmorProfile != "" {
@@ -120,6 +129,10 @@ func (d *Driver) createContainer(c *execdriver.Command, hooks execdriver.Hooks)
 		return nil, err
 	}
 
+	if err := d.setupInit(container, c); err != nil {
+		return nil, err
+	}
+
 	d.setupLabels(container, c)
 	d.setupRlimits(container, c)
 
@@ -264,7 +277,7 @@ func (d *Driver) setPrivileged(container *configs.Config) (err error) {
 	}
 	container.Devices = hostDevices
 
-	if apparmor.IsEnabled() {
+	if apparmor.IsEnabled() && !rootless.Running() {
 		container.AppArmorProfile = "unconfined"
 	}
 	return nil
@@ -275,6 +288,19 @@ func (d *Driver) setCapabilities(container *configs.Config, c *execdriver.Comman
 	return err
 }
 
+// setAmbientCapabilities sets the capabilities raised in the ambient set,
+// which must be kept in the container.
+func (d *Driver) setAmbientCapabilities(container *configs.Config, c *execdriver.Command) error {
+	for _, cap := range c.CapAmbient {
+		cap = strings.TrimPrefix(strings.ToUpper(cap), "CAP_")
+		if !stringutils.InSlice(container.Capabilities, cap) {
+			return fmt.Errorf("The ambient capability %s is not one of the capabilities of the container", cap)
+		}
+		container.AmbientCapabilities = append(container.AmbientCapabilities, cap)
+	}
+	return nil
+}
+
 func (d *Driver) setupRlimits(container *configs.Config, c *execdriver.Command) {
 	if c.Resources == nil {
 		return
@@ -399,6 +425,30 @@ func ensureSharedOrSlave(path string) error {
 	return nil
 }
 
+// setupInit bind mounts the daemon binary into the container to run as its
+// init, see InitPath.
+func (d *Driver) setupInit(container *configs.Config, c *execdriver.Command) error {
+	if !c.Init {
+		return nil
+	}
+	for _, m := range container.Mounts {
+		if m.Destination == InitPath {
+			return fmt.Errorf("Cannot run an init, %s is already mounted", InitPath)
+		}
+	}
+	path, err := filepath.EvalSymlinks(reexec.Self())
+	if err != nil {
+		return err
+	}
+	container.Mounts = append(container.Mounts, &configs.Mount{
+		Source:      path,
+		Destination: InitPath,
+		Device:      "bind",
+		Flags:       syscall.MS_BIND | syscall.MS_RDONLY,
+	})
+	return nil
+}
+
 func (d *Driver) setupMounts(container *configs.Config, c *execdriver.Command) error {
 	userMounts := make(map[string]struct{})
 	for _, m := range c.Mounts {
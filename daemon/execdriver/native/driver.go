@@ -19,6 +19,7 @@ import (
 	"github.com/docker/docker/pkg/parsers"
 	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/reexec"
+	"github.com/docker/docker/pkg/rootless"
 	sysinfo "github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/pkg/term"
 	aaprofile "github.com/docker/docker/profiles/apparmor"
@@ -59,7 +60,9 @@ func NewDriver(root string, options []string) (*Driver, error) {
 		return nil, err
 	}
 
-	if apparmor.IsEnabled() {
+	// The AppArmor profiles can't be loaded, nor changed to, by the root of
+	// the user namespace of a rootless daemon.
+	if apparmor.IsEnabled() && !rootless.Running() {
 		if err := aaprofile.InstallDefault(defaultApparmorProfile); err != nil {
 			apparmorProfiles := []string{defaultApparmorProfile}
 
@@ -106,6 +109,13 @@ func NewDriver(root string, options []string) (*Driver, error) {
 		}
 	}
 
+	// The containers of a rootless daemon are only put in cgroups when the
+	// cgroups of the daemon are delegated to its user.
+	if rootless.Running() && !cgroupsDelegated() {
+		logrus.Warn("The cgroups are not delegated to the user running the daemon, the resource limits of the containers are ignored")
+		cgm = noCgroups
+	}
+
 	f, err := libcontainer.New(
 		root,
 		cgm,
@@ -146,6 +156,9 @@ func (d *Driver) Run(c *execdriver.Command, pipes *execdriver.Pipes, hooks execd
 		Cwd:  c.WorkingDir,
 		User: c.ProcessConfig.User,
 	}
+	if c.Init {
+		p.Args = append([]string{InitPath}, p.Args...)
+	}
 
 	wg := sync.WaitGroup{}
 	writers, err := setupPipes(container, &c.ProcessConfig, p, pipes, &wg)
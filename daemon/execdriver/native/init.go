@@ -5,7 +5,10 @@ package native
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
 	"runtime"
+	"syscall"
 
 	"github.com/docker/docker/pkg/reexec"
 	"github.com/opencontainers/runc/libcontainer"
@@ -13,8 +16,15 @@ import (
 
 func init() {
 	reexec.Register(DriverName, initializer)
+	reexec.Register(InitPath, reaper)
 }
 
+// InitPath is the path the daemon binary is bind mounted at in containers
+// that run with an init. The init is the container's PID 1: it runs the
+// container command as its child, forwards signals to it and reaps any
+// process re-parented to it.
+const InitPath = "/dev/init"
+
 func fatal(err error) {
 	if lerr, ok := err.(libcontainer.Error); ok {
 		lerr.Detail(os.Stderr)
@@ -43,3 +53,64 @@ func writeError(err error) {
 	fmt.Fprint(os.Stderr, err)
 	os.Exit(1)
 }
+
+// reaper runs the command given in its arguments and waits for it to exit,
+// forwarding every signal it receives and reaping every child that exits in
+// the meantime. It exits with the exit status of the command.
+func reaper() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "init: no command specified")
+		os.Exit(1)
+	}
+
+	// Subscribe before starting the command so that no SIGCHLD is missed.
+	signals := make(chan os.Signal, 32)
+	signal.Notify(signals)
+
+	cmd := exec.Command(os.Args[1], os.Args[2:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "init: %v\n", err)
+		if os.IsPermission(err) {
+			os.Exit(126)
+		}
+		os.Exit(127)
+	}
+
+	for sig := range signals {
+		switch sig {
+		case syscall.SIGCHLD:
+			if status, exited := reap(cmd.Process.Pid); exited {
+				os.Exit(status)
+			}
+		case syscall.SIGURG:
+			// Used by the Go runtime to preempt goroutines.
+		default:
+			cmd.Process.Signal(sig)
+		}
+	}
+}
+
+// reap waits for all exited children without blocking. It reports the exit
+// status of pid, in the form a shell would, once pid has exited.
+func reap(pid int) (int, bool) {
+	for {
+		var ws syscall.WaitStatus
+		wpid, err := syscall.Wait4(-1, &ws, syscall.WNOHANG, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil || wpid <= 0 {
+			return 0, false
+		}
+		if wpid != pid {
+			continue
+		}
+		if ws.Signaled() {
+			return 128 + int(ws.Signal()), true
+		}
+		return ws.ExitStatus(), true
+	}
+}
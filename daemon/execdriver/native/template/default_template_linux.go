@@ -3,6 +3,7 @@ package template
 import (
 	"syscall"
 
+	"github.com/docker/docker/pkg/rootless"
 	"github.com/opencontainers/runc/libcontainer/apparmor"
 	"github.com/opencontainers/runc/libcontainer/configs"
 )
@@ -98,7 +99,7 @@ func New() *configs.Config {
 		},
 	}
 
-	if apparmor.IsEnabled() {
+	if apparmor.IsEnabled() && !rootless.Running() {
 		container.AppArmorProfile = "docker-default"
 	}
 
@@ -35,11 +35,12 @@ func (daemon *Daemon) containerExport(container *container.Container) (archive.A
 		return nil, err
 	}
 
-	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	uidMaps, gidMaps := daemon.containerIDMaps(container)
 	archive, err := archive.TarWithOptions(container.BaseFS, &archive.TarOptions{
-		Compression: archive.Uncompressed,
-		UIDMaps:     uidMaps,
-		GIDMaps:     gidMaps,
+		Compression:     archive.Uncompressed,
+		ExcludePatterns: secretPaths(container),
+		UIDMaps:         uidMaps,
+		GIDMaps:         gidMaps,
 	})
 	if err != nil {
 		daemon.Unmount(container)
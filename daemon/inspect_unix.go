@@ -6,6 +6,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/versions/v1p19"
 )
@@ -16,10 +17,25 @@ func setPlatformSpecificContainerFields(container *container.Container, contJSON
 	contJSONBase.ResolvConfPath = container.ResolvConfPath
 	contJSONBase.HostnamePath = container.HostnamePath
 	contJSONBase.HostsPath = container.HostsPath
+	if container.UIDMaps != nil {
+		contJSONBase.UsernsMappings = &types.UsernsMappings{
+			UIDMaps: toAPIIDMaps(container.UIDMaps),
+			GIDMaps: toAPIIDMaps(container.GIDMaps),
+		}
+	}
+	contJSONBase.LearnedSeccompProfile = container.LearnedSeccompProfile
 
 	return contJSONBase
 }
 
+func toAPIIDMaps(maps []idtools.IDMap) []types.IDMap {
+	apiMaps := make([]types.IDMap, 0, len(maps))
+	for _, m := range maps {
+		apiMaps = append(apiMaps, types.IDMap{ContainerID: m.ContainerID, HostID: m.HostID, Size: m.Size})
+	}
+	return apiMaps
+}
+
 // containerInspectPre120 gets containers for pre 1.20 APIs.
 func (daemon *Daemon) containerInspectPre120(name string) (*v1p19.ContainerJSON, error) {
 	container, err := daemon.GetContainer(name)
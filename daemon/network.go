@@ -10,6 +10,8 @@ import (
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
+	"github.com/docker/libnetwork/types"
+	"golang.org/x/net/context"
 )
 
 // NetworkControllerEnabled checks if the networking stack is enabled.
@@ -91,7 +93,7 @@ func (daemon *Daemon) GetAllNetworks() []libnetwork.Network {
 }
 
 // CreateNetwork creates a network with the given name, driver and other optional parameters
-func (daemon *Daemon) CreateNetwork(name, driver string, ipam network.IPAM, netOption map[string]string, internal bool, enableIPv6 bool) (libnetwork.Network, error) {
+func (daemon *Daemon) CreateNetwork(ctx context.Context, name, driver string, ipam network.IPAM, netOption map[string]string, labels map[string]string, internal bool, enableIPv6 bool, egressPolicy *network.EgressPolicy) (libnetwork.Network, error) {
 	c := daemon.netController
 	if driver == "" {
 		driver = c.Config().Daemon.DefaultDriver
@@ -107,18 +109,45 @@ func (daemon *Daemon) CreateNetwork(name, driver string, ipam network.IPAM, netO
 	nwOptions = append(nwOptions, libnetwork.NetworkOptionIpam(ipam.Driver, "", v4Conf, v6Conf, ipam.Options))
 	nwOptions = append(nwOptions, libnetwork.NetworkOptionEnableIPv6(enableIPv6))
 	nwOptions = append(nwOptions, libnetwork.NetworkOptionDriverOpts(netOption))
+	nwOptions = append(nwOptions, libnetwork.NetworkOptionLabels(labels))
 	if internal {
 		nwOptions = append(nwOptions, libnetwork.NetworkOptionInternalNetwork())
 	}
+	if egressPolicy != nil {
+		if internal {
+			return nil, errors.NewBadRequestError(fmt.Errorf("an egress policy can not be set on an internal network"))
+		}
+		if enableIPv6 {
+			return nil, errors.NewBadRequestError(fmt.Errorf("an egress policy can not be set on a network with IPv6 enabled"))
+		}
+		policy := getEgressPolicy(egressPolicy)
+		if err := policy.Validate(); err != nil {
+			return nil, errors.NewBadRequestError(err)
+		}
+		nwOptions = append(nwOptions, libnetwork.NetworkOptionEgressPolicy(policy))
+	}
 	n, err := c.NewNetwork(driver, name, nwOptions...)
 	if err != nil {
 		return nil, err
 	}
 
-	daemon.LogNetworkEvent(n, "create")
+	daemon.LogNetworkEventWithAttributes(n, "create", requestAttributes(ctx, nil))
 	return n, nil
 }
 
+func getEgressPolicy(p *network.EgressPolicy) *types.EgressPolicy {
+	policy := &types.EgressPolicy{Default: p.Default}
+	for _, r := range p.Rules {
+		policy.Rules = append(policy.Rules, types.EgressRule{
+			Action:   r.Action,
+			CIDR:     r.CIDR,
+			Protocol: r.Protocol,
+			Port:     r.Port,
+		})
+	}
+	return policy
+}
+
 func getIpamConfig(data []network.IPAMConfig) ([]*libnetwork.IpamConf, []*libnetwork.IpamConf, error) {
 	ipamV4Cfg := []*libnetwork.IpamConf{}
 	ipamV6Cfg := []*libnetwork.IpamConf{}
@@ -144,17 +173,17 @@ func getIpamConfig(data []network.IPAMConfig) ([]*libnetwork.IpamConf, []*libnet
 // ConnectContainerToNetwork connects the given container to the given
 // network. If either cannot be found, an err is returned. If the
 // network cannot be set up, an err is returned.
-func (daemon *Daemon) ConnectContainerToNetwork(containerName, networkName string, endpointConfig *network.EndpointSettings) error {
+func (daemon *Daemon) ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, endpointConfig *network.EndpointSettings) error {
 	container, err := daemon.GetContainer(containerName)
 	if err != nil {
 		return err
 	}
-	return daemon.ConnectToNetwork(container, networkName, endpointConfig)
+	return daemon.ConnectToNetwork(ctx, container, networkName, endpointConfig)
 }
 
 // DisconnectContainerFromNetwork disconnects the given container from
 // the given network. If either cannot be found, an err is returned.
-func (daemon *Daemon) DisconnectContainerFromNetwork(containerName string, network libnetwork.Network, force bool) error {
+func (daemon *Daemon) DisconnectContainerFromNetwork(ctx context.Context, containerName string, network libnetwork.Network, force bool) error {
 	container, err := daemon.GetContainer(containerName)
 	if err != nil {
 		if force {
@@ -162,7 +191,7 @@ func (daemon *Daemon) DisconnectContainerFromNetwork(containerName string, netwo
 		}
 		return err
 	}
-	return daemon.DisconnectFromNetwork(container, network, force)
+	return daemon.DisconnectFromNetwork(ctx, container, network, force)
 }
 
 // GetNetworkDriverList returns the list of plugins drivers
@@ -185,7 +214,7 @@ func (daemon *Daemon) GetNetworkDriverList() map[string]bool {
 }
 
 // DeleteNetwork destroys a network unless it's one of docker's predefined networks.
-func (daemon *Daemon) DeleteNetwork(networkID string) error {
+func (daemon *Daemon) DeleteNetwork(ctx context.Context, networkID string) error {
 	nw, err := daemon.FindNetwork(networkID)
 	if err != nil {
 		return err
@@ -199,6 +228,6 @@ func (daemon *Daemon) DeleteNetwork(networkID string) error {
 	if err := nw.Delete(); err != nil {
 		return err
 	}
-	daemon.LogNetworkEvent(nw, "destroy")
+	daemon.LogNetworkEventWithAttributes(nw, "destroy", requestAttributes(ctx, nil))
 	return nil
 }
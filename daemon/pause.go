@@ -4,16 +4,17 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/container"
+	"golang.org/x/net/context"
 )
 
 // ContainerPause pauses a container
-func (daemon *Daemon) ContainerPause(name string) error {
+func (daemon *Daemon) ContainerPause(ctx context.Context, name string) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
 	}
 
-	if err := daemon.containerPause(container); err != nil {
+	if err := daemon.containerPause(ctx, container); err != nil {
 		return err
 	}
 
@@ -22,7 +23,7 @@ func (daemon *Daemon) ContainerPause(name string) error {
 
 // containerPause pauses the container execution without stopping the process.
 // The execution can be resumed by calling containerUnpause.
-func (daemon *Daemon) containerPause(container *container.Container) error {
+func (daemon *Daemon) containerPause(ctx context.Context, container *container.Container) error {
 	container.Lock()
 	defer container.Unlock()
 
@@ -45,6 +46,6 @@ func (daemon *Daemon) containerPause(container *container.Container) error {
 		return fmt.Errorf("Cannot pause container %s: %s", container.ID, err)
 	}
 	container.Paused = true
-	daemon.LogContainerEvent(container, "pause")
+	daemon.LogContainerEventWithAttributes(container, "pause", requestAttributes(ctx, nil))
 	return nil
 }
@@ -0,0 +1,173 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/runconfig"
+	"github.com/docker/go-connections/nat"
+)
+
+// ContainerPublish publishes the given port bindings on the container.
+// Bindings of a running container are programmed right away, otherwise
+// they take effect on the next start.
+func (daemon *Daemon) ContainerPublish(name string, portBindings nat.PortMap) error {
+	return daemon.updatePortBindings(name, portBindings, true)
+}
+
+// ContainerUnpublish removes the given port bindings from the container.
+// A port given without any binding has all of its bindings removed.
+func (daemon *Daemon) ContainerUnpublish(name string, portBindings nat.PortMap) error {
+	return daemon.updatePortBindings(name, portBindings, false)
+}
+
+func (daemon *Daemon) updatePortBindings(name string, portBindings nat.PortMap, publish bool) error {
+	if len(portBindings) == 0 {
+		return fmt.Errorf("No port bindings specified")
+	}
+
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+
+	container.Lock()
+	defer container.Unlock()
+
+	if container.RemovalInProgress || container.Dead {
+		return fmt.Errorf("Container %s is marked for removal and cannot be updated", container.ID)
+	}
+
+	networkMode := container.HostConfig.NetworkMode
+	if networkMode.IsContainer() || networkMode.IsHost() || networkMode.IsNone() {
+		return runconfig.ErrConflictNetworkPublishPorts
+	}
+
+	backupBindings := container.HostConfig.PortBindings
+	backupExposedPorts := container.Config.ExposedPorts
+
+	bindings, exposedPorts := copyPortMap(backupBindings), copyPortSet(backupExposedPorts)
+	if publish {
+		if err := addPortBindings(bindings, exposedPorts, portBindings); err != nil {
+			return err
+		}
+	} else {
+		if err := removePortBindings(bindings, portBindings); err != nil {
+			return err
+		}
+	}
+
+	container.HostConfig.PortBindings = bindings
+	container.Config.ExposedPorts = exposedPorts
+
+	if container.Running {
+		if err := daemon.updateSandboxPortMapping(container); err != nil {
+			container.HostConfig.PortBindings = backupBindings
+			container.Config.ExposedPorts = backupExposedPorts
+			return fmt.Errorf("Cannot update ports of container %s: %v", container.ID, err)
+		}
+	}
+
+	return container.ToDisk()
+}
+
+// updateSandboxPortMapping reprograms the port mappings of the sandbox of a
+// running container and refreshes the published ports in its network settings.
+func (daemon *Daemon) updateSandboxPortMapping(container *container.Container) error {
+	// TODO Windows: Remove this once TP4 builds are not supported
+	// Windows TP4 build don't support libnetwork and in that case
+	// daemon.netController will be nil
+	if daemon.netController == nil {
+		return nil
+	}
+
+	sb, err := daemon.netController.SandboxByID(container.NetworkSettings.SandboxID)
+	if err != nil {
+		return err
+	}
+
+	exposeList, pbList, err := buildPortMapping(container)
+	if err != nil {
+		return err
+	}
+
+	if err := sb.UpdatePortMapping(exposeList, pbList); err != nil {
+		return err
+	}
+
+	container.UpdatePortMapInfo(sb)
+	return nil
+}
+
+func addPortBindings(bindings nat.PortMap, exposedPorts nat.PortSet, add nat.PortMap) error {
+	for port, portBindings := range add {
+		if _, _, err := port.Range(); err != nil {
+			return fmt.Errorf("Invalid port %s: %v", port, err)
+		}
+		exposedPorts[port] = struct{}{}
+		if len(portBindings) == 0 {
+			// Publish on an ephemeral host port
+			portBindings = []nat.PortBinding{{}}
+		}
+		for _, pb := range portBindings {
+			if hasPortBinding(bindings[port], pb) {
+				return fmt.Errorf("Port %s is already published on %s:%s", port, pb.HostIP, pb.HostPort)
+			}
+			bindings[port] = append(bindings[port], pb)
+		}
+	}
+	return nil
+}
+
+func removePortBindings(bindings nat.PortMap, remove nat.PortMap) error {
+	for port, portBindings := range remove {
+		if len(bindings[port]) == 0 {
+			return fmt.Errorf("No public port '%s' published", port)
+		}
+		if len(portBindings) == 0 {
+			delete(bindings, port)
+			continue
+		}
+		for _, pb := range portBindings {
+			if !hasPortBinding(bindings[port], pb) {
+				return fmt.Errorf("Port %s is not published on %s:%s", port, pb.HostIP, pb.HostPort)
+			}
+			var kept []nat.PortBinding
+			for _, b := range bindings[port] {
+				if b != pb {
+					kept = append(kept, b)
+				}
+			}
+			bindings[port] = kept
+		}
+		if len(bindings[port]) == 0 {
+			delete(bindings, port)
+		}
+	}
+	return nil
+}
+
+func hasPortBinding(bindings []nat.PortBinding, pb nat.PortBinding) bool {
+	for _, b := range bindings {
+		if b == pb {
+			return true
+		}
+	}
+	return false
+}
+
+func copyPortMap(pm nat.PortMap) nat.PortMap {
+	c := make(nat.PortMap, len(pm))
+	for port, bindings := range pm {
+		c[port] = append([]nat.PortBinding(nil), bindings...)
+	}
+	return c
+}
+
+func copyPortSet(ps nat.PortSet) nat.PortSet {
+	c := make(nat.PortSet, len(ps))
+	for port := range ps {
+		c[port] = struct{}{}
+	}
+	return c
+}
@@ -6,12 +6,13 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 // ContainerRename changes the name of a container, using the oldName
 // to find the container. An error is returned if newName is already
 // reserved.
-func (daemon *Daemon) ContainerRename(oldName, newName string) error {
+func (daemon *Daemon) ContainerRename(ctx context.Context, oldName, newName string) error {
 	var (
 		sid string
 		sb  libnetwork.Sandbox
@@ -49,9 +50,9 @@ func (daemon *Daemon) ContainerRename(oldName, newName string) error {
 		return err
 	}
 
-	attributes := map[string]string{
+	attributes := requestAttributes(ctx, map[string]string{
 		"oldName": oldName,
-	}
+	})
 
 	if !container.Running {
 		daemon.LogContainerEventWithAttributes(container, "rename", attributes)
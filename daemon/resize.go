@@ -1,10 +1,14 @@
 package daemon
 
-import "fmt"
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+)
 
 // ContainerResize changes the size of the TTY of the process running
 // in the container with the given name to the given height and width.
-func (daemon *Daemon) ContainerResize(name string, height, width int) error {
+func (daemon *Daemon) ContainerResize(ctx context.Context, name string, height, width int) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -19,7 +23,7 @@ func (daemon *Daemon) ContainerResize(name string, height, width int) error {
 			"height": fmt.Sprintf("%d", height),
 			"width":  fmt.Sprintf("%d", width),
 		}
-		daemon.LogContainerEventWithAttributes(container, "resize", attributes)
+		daemon.LogContainerEventWithAttributes(container, "resize", requestAttributes(ctx, attributes))
 	}
 	return err
 }
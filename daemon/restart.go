@@ -4,20 +4,26 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/container"
+	"golang.org/x/net/context"
 )
 
 // ContainerRestart stops and starts a container. It attempts to
 // gracefully stop the container within the given timeout, forcefully
 // stopping it if the timeout is exceeded. If given a negative
 // timeout, ContainerRestart will wait forever until a graceful
-// stop. Returns an error if the container cannot be found, or if
-// there is an underlying error at any stage of the restart.
-func (daemon *Daemon) ContainerRestart(name string, seconds int) error {
+// stop. If seconds is nil, the stop timeout of the container is used.
+// Returns an error if the container cannot be found, or if there is an
+// underlying error at any stage of the restart.
+func (daemon *Daemon) ContainerRestart(ctx context.Context, name string, seconds *int) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
 	}
-	if err := daemon.containerRestart(container, seconds); err != nil {
+	if seconds == nil {
+		stopTimeout := container.StopTimeout()
+		seconds = &stopTimeout
+	}
+	if err := daemon.containerRestart(ctx, container, *seconds); err != nil {
 		return fmt.Errorf("Cannot restart container %s: %v", name, err)
 	}
 	return nil
@@ -27,7 +33,7 @@ func (daemon *Daemon) ContainerRestart(name string, seconds int) error {
 // container. When stopping, wait for the given duration in seconds to
 // gracefully stop, before forcefully terminating the container. If
 // given a negative duration, wait forever for a graceful stop.
-func (daemon *Daemon) containerRestart(container *container.Container, seconds int) error {
+func (daemon *Daemon) containerRestart(ctx context.Context, container *container.Container, seconds int) error {
 	// Avoid unnecessarily unmounting and then directly mounting
 	// the container when the container stops and then starts
 	// again
@@ -35,14 +41,14 @@ func (daemon *Daemon) containerRestart(container *container.Container, seconds i
 		defer daemon.Unmount(container)
 	}
 
-	if err := daemon.containerStop(container, seconds); err != nil {
+	if err := daemon.containerStop(ctx, container, seconds); err != nil {
 		return err
 	}
 
-	if err := daemon.containerStart(container); err != nil {
+	if err := daemon.containerStart(ctx, container); err != nil {
 		return err
 	}
 
-	daemon.LogContainerEvent(container, "restart")
+	daemon.LogContainerEventWithAttributes(container, "restart", requestAttributes(ctx, nil))
 	return nil
 }
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// rootlessGraphDrivers are the graph drivers a rootless daemon can use.
+var rootlessGraphDrivers = map[string]bool{"overlay": true, "vfs": true}
+
+// rootlessGraphDriver returns the graph driver of a rootless daemon: overlay
+// when the kernel lets the root of a user namespace mount it, vfs otherwise.
+func rootlessGraphDriver(config *Config, name string) (string, error) {
+	if !config.Rootless {
+		return name, nil
+	}
+	if name != "" {
+		if !rootlessGraphDrivers[name] {
+			return "", fmt.Errorf("The %s storage driver is not supported by a rootless daemon, use overlay or vfs", name)
+		}
+		return name, nil
+	}
+	if err := overlayMountable(config.Root); err != nil {
+		logrus.Infof("Rootless mode: overlay can't be mounted in the user namespace, using vfs: %v", err)
+		return "vfs", nil
+	}
+	return "overlay", nil
+}
+
+// overlayMountable checks that an overlay filesystem, with a whiteout in its
+// upper directory, can be mounted in a temporary directory under root.
+func overlayMountable(root string) error {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir(root, "overlay-check")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	lower, upper, work, merged := filepath.Join(dir, "lower"), filepath.Join(dir, "upper"), filepath.Join(dir, "work"), filepath.Join(dir, "merged")
+	for _, d := range []string{lower, upper, work, merged} {
+		if err := os.Mkdir(d, 0700); err != nil {
+			return err
+		}
+	}
+	if err := syscall.Mknod(filepath.Join(upper, "whiteout"), syscall.S_IFCHR, 0); err != nil {
+		return err
+	}
+	opts := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", lower, upper, work)
+	if err := syscall.Mount("overlay", merged, "overlay", 0, opts); err != nil {
+		return err
+	}
+	return syscall.Unmount(merged, 0)
+}
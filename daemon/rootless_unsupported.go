@@ -0,0 +1,7 @@
+// +build !linux
+
+package daemon
+
+func rootlessGraphDriver(config *Config, name string) (string, error) {
+	return name, nil
+}
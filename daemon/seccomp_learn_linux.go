@@ -0,0 +1,399 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/docker/docker/profiles/seccomp"
+	"github.com/docker/engine-api/types"
+	"github.com/vishvananda/netlink/nl"
+)
+
+const (
+	// seccompLearn is the value of the seccomp security option running a
+	// container in learning mode, optionally followed by ':' and the name of
+	// the file the learned profile is written to.
+	seccompLearn = "learn"
+	// seccompLearnDir is the directory of the learned profiles written to a
+	// file, in the daemon root.
+	seccompLearnDir = "seccomp"
+	// seccompLearnSyncCmd is the command logging a syscall to make sure
+	// that all the syscalls of a container were read.
+	seccompLearnSyncCmd = "docker-seccomp-learn-sync"
+	// seccompLearnTimeout is how long the syscalls still queued by the
+	// kernel for an exited container are waited for.
+	seccompLearnTimeout = 5 * time.Second
+
+	// auditGet is the netlink message type querying the audit status.
+	auditGet = 1000
+	// auditNlgrpReadlog is the netlink multicast group receiving a copy of
+	// all the audit records, whether auditd is running or not.
+	auditNlgrpReadlog = 1
+	// auditRecordMax is the maximum size of an audit record.
+	auditRecordMax = 8970
+	// auditRcvbuf is the size of the receive buffer of the socket reading
+	// the audit records.
+	auditRcvbuf = 4 << 20
+)
+
+func init() {
+	reexec.Register(seccompLearnSyncCmd, seccompLearnSync)
+}
+
+// seccompLearner collects the syscalls the kernel logs for the containers
+// running with the seccomp profile logging every syscall. The audit records
+// are read from their netlink multicast group, which isn't subject to the
+// rate limiting of the kernel log and receives them even when auditd runs.
+// When a record may have been lost, the learned profile is incomplete.
+type seccompLearner struct {
+	mu       sync.Mutex
+	sock     *nl.NetlinkSocket
+	sessions map[string]*seccompLearnSession
+}
+
+type seccompLearnSession struct {
+	pids     map[int]bool
+	syscalls map[string]bool
+	// lost is the number of audit records the kernel lost when the
+	// session started.
+	lost uint32
+	// incomplete is set when a syscall of the container may be missing.
+	incomplete bool
+	// syncPID is the process logging the syscall closing the session.
+	syncPID int
+	synced  chan struct{}
+}
+
+// sync marks that all the syscalls of the session were read. It must be
+// called with the learner locked.
+func (s *seccompLearnSession) sync() {
+	select {
+	case <-s.synced:
+	default:
+		close(s.synced)
+	}
+}
+
+func newSeccompLearner() *seccompLearner {
+	return &seccompLearner{sessions: make(map[string]*seccompLearnSession)}
+}
+
+// seccompLearnOutput returns whether the seccomp profile runs the container
+// in learning mode, and the name of the file the learned profile is written
+// to, if any.
+func seccompLearnOutput(profile string) (string, bool) {
+	if profile == seccompLearn {
+		return "", true
+	}
+	if !strings.HasPrefix(profile, seccompLearn+":") {
+		return "", false
+	}
+	return strings.TrimPrefix(profile, seccompLearn+":"), true
+}
+
+// validateSeccompLearn checks the file the profile learned by a container is
+// written to. It is a file name in the daemon root, not a path chosen by the
+// client.
+func validateSeccompLearn(profile string) error {
+	output, ok := seccompLearnOutput(profile)
+	if !ok || profile == seccompLearn {
+		return nil
+	}
+	if output == "" || output == "." || output == ".." || strings.ContainsRune(output, filepath.Separator) {
+		return fmt.Errorf("Invalid seccomp learning output %q: it must be a file name", output)
+	}
+	return nil
+}
+
+// seccompProfile returns the seccomp profile of the container passed to the
+// exec driver, the daemon default one if the container doesn't set any.
+func (daemon *Daemon) seccompProfile(c *container.Container) (string, error) {
+	if _, ok := seccompLearnOutput(c.SeccompProfile); !ok {
+		if c.SeccompProfile == "" {
+			return daemon.defaultSeccompProfile(c), nil
+		}
+		return c.SeccompProfile, nil
+	}
+	b, err := json.Marshal(seccomp.LearnProfile())
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// startSeccompLearning starts recording the syscalls of the container if it
+// runs in learning mode.
+func (daemon *Daemon) startSeccompLearning(c *container.Container) error {
+	if _, ok := seccompLearnOutput(c.SeccompProfile); !ok {
+		return nil
+	}
+	c.LearnedSeccompProfile = nil
+	return daemon.seccompLearner.start(c.ID)
+}
+
+// stopSeccompLearning stores the profile allowing the syscalls recorded for
+// the container, if it runs in learning mode, and writes it to its output
+// file in the daemon root, if any.
+func (daemon *Daemon) stopSeccompLearning(c *container.Container) {
+	output, ok := seccompLearnOutput(c.SeccompProfile)
+	if !ok {
+		return
+	}
+	syscalls, complete := daemon.seccompLearner.stop(c.ID)
+	if len(syscalls) == 0 {
+		logrus.Warnf("No syscall was recorded for container %s", c.ID)
+		complete = false
+	} else if !complete {
+		logrus.Warnf("Some syscalls of container %s may be missing from its learned seccomp profile", c.ID)
+	}
+	c.LearnedSeccompProfile = &types.LearnedSeccompProfile{
+		Complete: complete,
+		Profile:  seccomp.GenerateProfile(syscalls),
+	}
+	if output == "" {
+		return
+	}
+	if err := daemon.writeSeccompProfile(output, c.LearnedSeccompProfile.Profile); err != nil {
+		logrus.Errorf("Error writing the seccomp profile learned by container %s: %v", c.ID, err)
+		return
+	}
+	logrus.Infof("Wrote the seccomp profile learned by container %s to %s", c.ID, output)
+}
+
+// writeSeccompProfile writes a learned profile to the file of the given name
+// in the directory of the learned profiles.
+func (daemon *Daemon) writeSeccompProfile(output string, profile *types.Seccomp) error {
+	b, err := json.MarshalIndent(profile, "", "\t")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(daemon.root, seccompLearnDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, output), b, 0644)
+}
+
+func (l *seccompLearner) start(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lost, err := auditLost()
+	if err != nil {
+		return fmt.Errorf("Error getting the audit status for seccomp learning: %v", err)
+	}
+	if l.sock == nil {
+		s, err := nl.Subscribe(syscall.NETLINK_AUDIT, auditNlgrpReadlog)
+		if err != nil {
+			return fmt.Errorf("Error subscribing to the audit records for seccomp learning: %v", err)
+		}
+		if err := syscall.SetsockoptInt(s.GetFd(), syscall.SOL_SOCKET, syscall.SO_RCVBUFFORCE, auditRcvbuf); err != nil {
+			logrus.Warnf("Error setting the receive buffer of the audit socket: %v", err)
+		}
+		l.sock = s
+		go l.read(s)
+	}
+	l.sessions[id] = &seccompLearnSession{
+		pids:     make(map[int]bool),
+		syscalls: make(map[string]bool),
+		lost:     lost,
+		synced:   make(chan struct{}),
+	}
+	return nil
+}
+
+// stop returns the syscalls recorded for a container once every record
+// queued before the call was read, and whether none may be missing.
+func (l *seccompLearner) stop(id string) ([]string, bool) {
+	l.mu.Lock()
+	s, ok := l.sessions[id]
+	if !ok {
+		l.mu.Unlock()
+		return nil, false
+	}
+	// the records are delivered in order: once the syscall of the sync
+	// process is read, the ones of the container were too
+	cmd := reexec.Command(seccompLearnSyncCmd)
+	err := cmd.Start()
+	if err == nil {
+		s.syncPID = cmd.Process.Pid
+	}
+	l.mu.Unlock()
+
+	if err == nil {
+		err = cmd.Wait()
+	}
+	if err != nil {
+		logrus.Warnf("Error logging the end of the seccomp learning of container %s: %v", id, err)
+	} else {
+		select {
+		case <-s.synced:
+		case <-time.After(seccompLearnTimeout):
+			logrus.Warnf("Timeout reading the syscalls of container %s", id)
+		}
+	}
+	lost, lostErr := auditLost()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.sessions, id)
+	complete := err == nil && !s.incomplete && lostErr == nil && lost == s.lost
+	select {
+	case <-s.synced:
+	default:
+		complete = false
+	}
+	var syscalls []string
+	for name := range s.syscalls {
+		syscalls = append(syscalls, name)
+	}
+	return syscalls, complete
+}
+
+// fail marks that some syscalls may be missing from all the sessions. It
+// must be called with the learner locked.
+func (l *seccompLearner) fail() {
+	for _, s := range l.sessions {
+		s.incomplete = true
+	}
+}
+
+func (l *seccompLearner) read(s *nl.NetlinkSocket) {
+	buf := make([]byte, auditRecordMax+syscall.NLMSG_HDRLEN)
+	for {
+		n, _, err := syscall.Recvfrom(s.GetFd(), buf, 0)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err == syscall.ENOBUFS {
+			// records were dropped before being read
+			logrus.Warn("Some audit records were lost while learning seccomp profiles")
+			l.mu.Lock()
+			l.fail()
+			l.mu.Unlock()
+			continue
+		}
+		var msgs []syscall.NetlinkMessage
+		if err == nil {
+			msgs, err = syscall.ParseNetlinkMessage(buf[:n])
+		}
+		if err != nil {
+			logrus.Errorf("Error reading the audit records for seccomp learning: %v", err)
+			l.mu.Lock()
+			l.sock = nil
+			l.fail()
+			for _, session := range l.sessions {
+				session.sync()
+			}
+			l.mu.Unlock()
+			s.Close()
+			return
+		}
+		for _, m := range msgs {
+			if m.Header.Type == seccomp.AuditSeccomp {
+				l.handle(strings.TrimRight(string(m.Data), "\x00\n"))
+			}
+		}
+	}
+}
+
+func (l *seccompLearner) handle(msg string) {
+	r, ok := seccomp.ParseAuditRecord(msg)
+	if !ok {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.sessions) == 0 {
+		return
+	}
+	for _, s := range l.sessions {
+		if s.syncPID != 0 && s.syncPID == r.PID {
+			s.sync()
+			return
+		}
+	}
+	var cgroups string
+	for id, s := range l.sessions {
+		if !s.pids[r.PID] {
+			// the process is looked up the first time it is seen
+			if cgroups == "" {
+				b, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cgroup", r.PID))
+				if err != nil {
+					// it exited, its syscall can't be attributed
+					l.fail()
+					return
+				}
+				cgroups = string(b)
+			}
+			if !strings.Contains(cgroups, id) {
+				continue
+			}
+			s.pids[r.PID] = true
+		}
+		name, err := seccomp.SyscallName(r)
+		if err != nil {
+			logrus.Debugf("Ignoring a syscall of container %s: %v", id, err)
+			s.incomplete = true
+			return
+		}
+		s.syscalls[name] = true
+		return
+	}
+}
+
+// auditLost returns the number of audit records lost by the kernel.
+func auditLost() (uint32, error) {
+	req := nl.NewNetlinkRequest(auditGet, 0)
+	msgs, err := req.Execute(syscall.NETLINK_AUDIT, auditGet)
+	if err != nil {
+		return 0, err
+	}
+	// struct audit_status starts with the mask, enabled, failure, pid,
+	// rate_limit, backlog_limit and lost fields
+	if len(msgs) == 0 || len(msgs[0]) < 28 {
+		return 0, fmt.Errorf("short audit status")
+	}
+	return nl.NativeEndian().Uint32(msgs[0][24:28]), nil
+}
+
+// seccompLearnSync logs a syscall with a seccomp filter, which is read
+// after all the syscalls logged before it.
+func seccompLearnSync() {
+	runtime.LockOSThread()
+	filter := []syscall.SockFilter{
+		// load the syscall number
+		{Code: syscall.BPF_LD | syscall.BPF_W | syscall.BPF_ABS, K: 0},
+		{Code: syscall.BPF_JMP | syscall.BPF_JEQ | syscall.BPF_K, Jt: 0, Jf: 1, K: syscall.SYS_GETPPID},
+		// SECCOMP_RET_LOG
+		{Code: syscall.BPF_RET | syscall.BPF_K, K: 0x7ffc0000},
+		// SECCOMP_RET_ALLOW
+		{Code: syscall.BPF_RET | syscall.BPF_K, K: 0x7fff0000},
+	}
+	prog := syscall.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	// PR_SET_NO_NEW_PRIVS
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, 38, 1, 0); errno != 0 {
+		fmt.Fprintln(os.Stderr, errno)
+		os.Exit(1)
+	}
+	// PR_SET_SECCOMP, SECCOMP_MODE_FILTER
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, syscall.PR_SET_SECCOMP, 2, uintptr(unsafe.Pointer(&prog))); errno != 0 {
+		fmt.Fprintln(os.Stderr, errno)
+		os.Exit(1)
+	}
+	syscall.RawSyscall(syscall.SYS_GETPPID, 0, 0, 0)
+	os.Exit(0)
+}
@@ -0,0 +1,22 @@
+// +build !linux
+
+package daemon
+
+import "github.com/docker/docker/container"
+
+type seccompLearner struct{}
+
+func newSeccompLearner() *seccompLearner {
+	return &seccompLearner{}
+}
+
+func validateSeccompLearn(profile string) error {
+	return nil
+}
+
+func (daemon *Daemon) startSeccompLearning(c *container.Container) error {
+	return nil
+}
+
+func (daemon *Daemon) stopSeccompLearning(c *container.Container) {
+}
@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+const (
+	// defaultSecretsDir is the directory the secrets are mounted in when
+	// their reference doesn't set a target.
+	defaultSecretsDir = "/run/secrets"
+	// defaultSecretMode is the mode of the secret files when their
+	// reference doesn't set one.
+	defaultSecretMode = 0444
+)
+
+// SecretCreate stores a new secret.
+func (daemon *Daemon) SecretCreate(req types.SecretCreateRequest) (*types.Secret, error) {
+	return daemon.secrets.Create(req.Name, req.Data, req.Labels)
+}
+
+// Secrets lists the stored secrets, without their data.
+func (daemon *Daemon) Secrets() ([]*types.Secret, error) {
+	return daemon.secrets.List()
+}
+
+// SecretRm removes a secret which isn't used by any container.
+func (daemon *Daemon) SecretRm(name string) error {
+	if _, err := daemon.secrets.Get(name); err != nil {
+		return err
+	}
+	for _, c := range daemon.List() {
+		for _, s := range c.HostConfig.Secrets {
+			if s.Name == name {
+				err := fmt.Errorf("secret %s is in use by container %s", name, c.ID)
+				return errors.NewRequestConflictError(err)
+			}
+		}
+	}
+	return daemon.secrets.Remove(name)
+}
+
+// verifySecrets checks that the secrets referenced by a container exist and
+// sets the default target and mode of the references.
+func (daemon *Daemon) verifySecrets(hostConfig *containertypes.HostConfig) error {
+	if len(hostConfig.Secrets) == 0 {
+		return nil
+	}
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("secrets are not supported on Windows")
+	}
+
+	targets := make(map[string]bool)
+	for i := range hostConfig.Secrets {
+		s := &hostConfig.Secrets[i]
+		if _, err := daemon.secrets.Get(s.Name); err != nil {
+			return err
+		}
+		if s.Target == "" {
+			s.Target = path.Join(defaultSecretsDir, s.Name)
+		}
+		if !path.IsAbs(s.Target) || path.Clean(s.Target) == "/" {
+			return fmt.Errorf("invalid target %q for secret %s: it must be an absolute file path", s.Target, s.Name)
+		}
+		s.Target = path.Clean(s.Target)
+		if targets[s.Target] {
+			return fmt.Errorf("duplicate secret target %s", s.Target)
+		}
+		targets[s.Target] = true
+		if s.Mode == 0 {
+			s.Mode = defaultSecretMode
+		}
+		if s.Mode&^0777 != 0 {
+			return fmt.Errorf("invalid mode %o for secret %s", s.Mode, s.Name)
+		}
+		if s.UID < 0 {
+			return fmt.Errorf("invalid uid %d for secret %s", s.UID, s.Name)
+		}
+	}
+	return nil
+}
+
+// secretPaths returns the paths of the secret files of the container, relative
+// to its root filesystem.
+func secretPaths(c *container.Container) []string {
+	var paths []string
+	for _, s := range c.HostConfig.Secrets {
+		paths = append(paths, strings.TrimPrefix(s.Target, "/"))
+	}
+	return paths
+}
+
+// excludeSecrets filters the mount points of the container secrets out of a
+// tar stream of its filesystem, so that commit never records them.
+func excludeSecrets(c *container.Container, in archive.Archive) archive.Archive {
+	paths := secretPaths(c)
+	if len(paths) == 0 {
+		return in
+	}
+	excluded := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		excluded[p] = true
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		tr := tar.NewReader(in)
+		tw := tar.NewWriter(pw)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			// The whiteouts of the secret files are dropped as well.
+			name := path.Clean("/" + hdr.Name)
+			dir, base := path.Split(name)
+			name = path.Join(dir, strings.TrimPrefix(base, archive.WhiteoutPrefix))
+			if excluded[strings.TrimPrefix(name, "/")] {
+				continue
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+	return ioutils.NewReadCloserWrapper(pr, func() error {
+		pr.Close()
+		return in.Close()
+	})
+}
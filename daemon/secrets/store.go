@@ -0,0 +1,256 @@
+// Package secrets implements the daemon store of the secrets mounted into
+// containers. The secrets are encrypted at rest with a key read from a key
+// file.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/errors"
+	"github.com/docker/engine-api/types"
+)
+
+const (
+	// keySize is the size of the AES-256 key used to encrypt the secrets.
+	keySize = 32
+	// MaxSize is the maximum size of the data of a secret.
+	MaxSize = 500 * 1024
+)
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// secret is the on-disk representation of a secret, its data is encrypted.
+type secret struct {
+	Name      string
+	CreatedAt time.Time
+	Size      int
+	Labels    map[string]string `json:",omitempty"`
+	Data      []byte
+}
+
+// Store holds the secrets in a directory, one file per secret.
+type Store struct {
+	mu   sync.Mutex
+	root string
+	aead cipher.AEAD
+}
+
+// New returns a store keeping the secrets in root, encrypted with the key in
+// keyFile. The key file is generated if it doesn't exist.
+func New(root, keyFile string) (*Store, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	key, err := loadKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{root: root, aead: aead}, nil
+}
+
+func loadKey(keyFile string) ([]byte, error) {
+	key, err := ioutil.ReadFile(keyFile)
+	if os.IsNotExist(err) {
+		key = make([]byte, keySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+			return nil, err
+		}
+		if err := atomicWriteFile(keyFile, key, 0600); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("invalid secrets key file %s: expected %d bytes, got %d", keyFile, keySize, len(key))
+	}
+	return key, nil
+}
+
+// atomicWriteFile writes data to a temporary file renamed to filename, so
+// that a partially written file is never read.
+func atomicWriteFile(filename string, data []byte, perm os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(filename), ".tmp-"+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.root, name+".json")
+}
+
+// Create encrypts and stores a new secret.
+func (s *Store) Create(name string, data []byte, labels map[string]string) (*types.Secret, error) {
+	if !validName.MatchString(name) {
+		return nil, errors.NewBadRequestError(fmt.Errorf("invalid secret name %q: only [a-zA-Z0-9][a-zA-Z0-9_.-] are allowed", name))
+	}
+	if len(data) == 0 {
+		return nil, errors.NewBadRequestError(fmt.Errorf("secret %s is empty", name))
+	}
+	if len(data) > MaxSize {
+		return nil, errors.NewBadRequestError(fmt.Errorf("secret %s is larger than %d bytes", name, MaxSize))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.path(name)); err == nil {
+		return nil, errors.NewRequestConflictError(fmt.Errorf("secret %s already exists", name))
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sec := &secret{
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		Size:      len(data),
+		Labels:    labels,
+		Data:      s.aead.Seal(nonce, nonce, data, []byte(name)),
+	}
+	b, err := json.Marshal(sec)
+	if err != nil {
+		return nil, err
+	}
+	if err := atomicWriteFile(s.path(name), b, 0600); err != nil {
+		return nil, err
+	}
+	return sec.toAPI(), nil
+}
+
+func (s *Store) load(name string) (*secret, error) {
+	if !validName.MatchString(name) {
+		return nil, errors.NewRequestNotFoundError(fmt.Errorf("no such secret: %s", name))
+	}
+	b, err := ioutil.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, errors.NewRequestNotFoundError(fmt.Errorf("no such secret: %s", name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	sec := &secret{}
+	if err := json.Unmarshal(b, sec); err != nil {
+		return nil, fmt.Errorf("invalid secret %s: %v", name, err)
+	}
+	return sec, nil
+}
+
+// Get returns the metadata of a secret.
+func (s *Store) Get(name string) (*types.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, err := s.load(name)
+	if err != nil {
+		return nil, err
+	}
+	return sec.toAPI(), nil
+}
+
+// Data returns the decrypted data of a secret.
+func (s *Store) Data(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, err := s.load(name)
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(sec.Data) < nonceSize {
+		return nil, fmt.Errorf("invalid secret %s: data too short", name)
+	}
+	data, err := s.aead.Open(nil, sec.Data[:nonceSize], sec.Data[nonceSize:], []byte(sec.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting secret %s: %v", name, err)
+	}
+	return data, nil
+}
+
+// List returns the metadata of the secrets, sorted by name.
+func (s *Store) List() ([]*types.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(s.root, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	secrets := []*types.Secret{}
+	for _, f := range files {
+		name := filepath.Base(f)
+		sec, err := s.load(name[:len(name)-len(".json")])
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, sec.toAPI())
+	}
+	sort.Sort(byName(secrets))
+	return secrets, nil
+}
+
+// Remove deletes a secret.
+func (s *Store) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.load(name); err != nil {
+		return err
+	}
+	return os.Remove(s.path(name))
+}
+
+func (sec *secret) toAPI() *types.Secret {
+	return &types.Secret{
+		Name:      sec.Name,
+		CreatedAt: sec.CreatedAt.Format(time.RFC3339Nano),
+		Size:      sec.Size,
+		Labels:    sec.Labels,
+	}
+}
+
+type byName []*types.Secret
+
+func (r byName) Len() int           { return len(r) }
+func (r byName) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byName) Less(i, j int) bool { return r[i].Name < r[j].Name }
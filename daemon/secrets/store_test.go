@@ -0,0 +1,127 @@
+package secrets
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestStore(t *testing.T) (*Store, string) {
+	dir, err := ioutil.TempDir("", "secrets-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(filepath.Join(dir, "secrets"), filepath.Join(dir, "secrets.key"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return s, dir
+}
+
+func TestStore(t *testing.T) {
+	s, dir := newTestStore(t)
+	defer os.RemoveAll(dir)
+
+	data := []byte("hunter2")
+	sec, err := s.Create("db-password", data, map[string]string{"team": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sec.Name != "db-password" || sec.Size != len(data) || sec.Labels["team"] != "a" || sec.CreatedAt == "" {
+		t.Fatalf("unexpected secret %+v", sec)
+	}
+	if _, err := s.Create("db-password", data, nil); err == nil {
+		t.Fatal("expected an error creating a secret twice")
+	}
+
+	// The data is encrypted at rest
+	b, err := ioutil.ReadFile(filepath.Join(dir, "secrets", "db-password.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, data) {
+		t.Fatal("expected the secret data to be encrypted")
+	}
+
+	got, err := s.Data("db-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expected %q, got %q", data, got)
+	}
+
+	if _, err := s.Create("api-key", []byte("abc"), nil); err != nil {
+		t.Fatal(err)
+	}
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "api-key" || list[1].Name != "db-password" {
+		t.Fatalf("unexpected secrets %v", list)
+	}
+
+	if err := s.Remove("db-password"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Data("db-password"); err == nil {
+		t.Fatal("expected an error reading a removed secret")
+	}
+	if err := s.Remove("db-password"); err == nil {
+		t.Fatal("expected an error removing a removed secret")
+	}
+}
+
+func TestStoreKeyFile(t *testing.T) {
+	s, dir := newTestStore(t)
+	defer os.RemoveAll(dir)
+	if _, err := s.Create("token", []byte("abc"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The generated key is reused
+	s, err := New(filepath.Join(dir, "secrets"), filepath.Join(dir, "secrets.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := s.Data("token"); err != nil || string(data) != "abc" {
+		t.Fatalf("expected to decrypt the secret with the same key, got %q, %v", data, err)
+	}
+
+	// Another key can't decrypt the secrets
+	s, err = New(filepath.Join(dir, "secrets"), filepath.Join(dir, "other.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Data("token"); err == nil {
+		t.Fatal("expected an error decrypting the secret with another key")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "short.key"), []byte("short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(filepath.Join(dir, "secrets"), filepath.Join(dir, "short.key")); err == nil {
+		t.Fatal("expected an error loading an invalid key")
+	}
+}
+
+func TestStoreInvalid(t *testing.T) {
+	s, dir := newTestStore(t)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"", "../escape", "-dash", "a/b"} {
+		if _, err := s.Create(name, []byte("data"), nil); err == nil {
+			t.Fatalf("expected an error creating a secret named %q", name)
+		}
+	}
+	if _, err := s.Create("empty", nil, nil); err == nil {
+		t.Fatal("expected an error creating an empty secret")
+	}
+	if _, err := s.Create("large", make([]byte, MaxSize+1), nil); err == nil {
+		t.Fatal("expected an error creating a secret larger than the maximum size")
+	}
+}
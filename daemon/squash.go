@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stringid"
+)
+
+// SquashImage creates a new image from imageID with all layers on top of
+// parentID merged into a single layer. The history of imageID is kept, with
+// an additional entry for the merged layer. An empty parentID squashes all
+// layers of the image.
+func (daemon *Daemon) SquashImage(imageID, parentID string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("squashing images is not supported on Windows")
+	}
+
+	img, err := daemon.imageStore.Get(image.ID(imageID))
+	if err != nil {
+		return "", err
+	}
+
+	rootFS := image.NewRootFS()
+	var history []image.History
+	if parentID != "" {
+		parentImg, err := daemon.imageStore.Get(image.ID(parentID))
+		if err != nil {
+			return "", err
+		}
+		rootFS.DiffIDs = append(rootFS.DiffIDs, parentImg.RootFS.DiffIDs...)
+		history = append(history, parentImg.History...)
+	}
+
+	if len(img.RootFS.DiffIDs) < len(rootFS.DiffIDs) || len(img.History) < len(history) {
+		return "", fmt.Errorf("image %s is not a child of %s", imageID, parentID)
+	}
+	for i, diffID := range rootFS.DiffIDs {
+		if img.RootFS.DiffIDs[i] != diffID {
+			return "", fmt.Errorf("image %s is not a child of %s", imageID, parentID)
+		}
+	}
+	if len(img.RootFS.DiffIDs) == len(rootFS.DiffIDs) {
+		// no layers to merge
+		return imageID, nil
+	}
+
+	l, err := daemon.squashLayers(img.RootFS.ChainID(), rootFS.ChainID())
+	if err != nil {
+		return "", err
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+
+	for _, h := range img.History[len(history):] {
+		h.EmptyLayer = true
+		history = append(history, h)
+	}
+
+	now := time.Now().UTC()
+	if diffID := l.DiffID(); diffID != layer.DigestSHA256EmptyTar {
+		rootFS.Append(diffID)
+		history = append(history, image.History{
+			Created: now,
+			Comment: fmt.Sprintf("merge %s to %s", imageID, parentID),
+		})
+	}
+
+	newImage := &image.Image{
+		V1Image: img.V1Image,
+		RootFS:  rootFS,
+		History: history,
+	}
+	newImage.Created = now
+
+	config, err := json.Marshal(newImage)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := daemon.imageStore.Create(config)
+	if err != nil {
+		return "", err
+	}
+
+	if parentID != "" {
+		if err := daemon.imageStore.SetParent(id, image.ID(parentID)); err != nil {
+			return "", err
+		}
+	}
+
+	return id.String(), nil
+}
+
+// squashLayers registers a new layer on top of parent holding the changes
+// between the filesystems of parent and top.
+func (daemon *Daemon) squashLayers(top, parent layer.ChainID) (layer.Layer, error) {
+	topDir, releaseTop, err := daemon.mountLayer(top)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTop()
+
+	parentDir, releaseParent, err := daemon.mountLayer(parent)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseParent()
+
+	changes, err := archive.ChangesDirs(topDir, parentDir)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := archive.ExportChanges(topDir, changes, daemon.uidMaps, daemon.gidMaps)
+	if err != nil {
+		return nil, err
+	}
+	defer diff.Close()
+
+	return daemon.layerStore.Register(diff, parent)
+}
+
+// mountLayer mounts the filesystem of the read-only layer chainID through a
+// temporary read-write layer. The returned function unmounts and releases
+// the temporary layer.
+func (daemon *Daemon) mountLayer(chainID layer.ChainID) (string, func(), error) {
+	rwLayer, err := daemon.layerStore.CreateRWLayer(stringid.GenerateRandomID(), chainID, "", nil, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	release := func() {
+		metadata, err := daemon.layerStore.ReleaseRWLayer(rwLayer)
+		layer.LogReleaseMetadata(metadata)
+		if err != nil {
+			logrus.Errorf("Error releasing temporary layer: %v", err)
+		}
+	}
+
+	dir, err := rwLayer.Mount("")
+	if err != nil {
+		release()
+		return "", nil, err
+	}
+	return dir, func() {
+		if err := rwLayer.Unmount(); err != nil {
+			logrus.Errorf("Error unmounting temporary layer: %v", err)
+		}
+		release()
+	}, nil
+}
@@ -10,10 +10,11 @@ import (
 	"github.com/docker/docker/errors"
 	"github.com/docker/docker/runconfig"
 	containertypes "github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 // ContainerStart starts a container.
-func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.HostConfig) error {
+func (daemon *Daemon) ContainerStart(ctx context.Context, name string, hostConfig *containertypes.HostConfig) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -69,19 +70,19 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 		return err
 	}
 
-	return daemon.containerStart(container)
+	return daemon.containerStart(ctx, container)
 }
 
 // Start starts a container
 func (daemon *Daemon) Start(container *container.Container) error {
-	return daemon.containerStart(container)
+	return daemon.containerStart(context.Background(), container)
 }
 
 // containerStart prepares the container to run by setting up everything the
 // container needs, such as storage and networking, as well as links
 // between containers. The container is left waiting for a signal to
 // begin running.
-func (daemon *Daemon) containerStart(container *container.Container) (err error) {
+func (daemon *Daemon) containerStart(ctx context.Context, container *container.Container) (err error) {
 	container.Lock()
 	defer container.Unlock()
 
@@ -93,6 +94,10 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		return fmt.Errorf("Container is marked for removal and cannot be started.")
 	}
 
+	if err := daemon.checkUserns(container); err != nil {
+		return err
+	}
+
 	// if we encounter an error during start we need to ensure that any other
 	// setup has been cleaned up properly
 	defer func() {
@@ -119,14 +124,14 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 	// backwards API compatibility.
 	container.HostConfig = runconfig.SetDefaultNetModeIfBlank(container.HostConfig)
 
-	if err := daemon.initializeNetworking(container); err != nil {
+	if err := daemon.initializeNetworking(ctx, container); err != nil {
 		return err
 	}
 	linkedEnv, err := daemon.setupLinkedContainers(container)
 	if err != nil {
 		return err
 	}
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	rootUID, rootGID := daemon.containerRootUIDGID(container)
 	if err := container.SetupWorkingDirectory(rootUID, rootGID); err != nil {
 		return err
 	}
@@ -134,6 +139,9 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 	if err := daemon.populateCommand(container, env); err != nil {
 		return err
 	}
+	if err := daemon.setupRootfsView(container); err != nil {
+		return err
+	}
 
 	if !container.HostConfig.IpcMode.IsContainer() && !container.HostConfig.IpcMode.IsHost() {
 		if err := daemon.setupIpcDirs(container); err != nil {
@@ -141,23 +149,31 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		}
 	}
 
+	if err := daemon.setupSecretMounts(container); err != nil {
+		return err
+	}
+
 	mounts, err := daemon.setupMounts(container)
 	if err != nil {
 		return err
 	}
 	mounts = append(mounts, container.IpcMounts()...)
 	mounts = append(mounts, container.TmpfsMounts()...)
+	mounts = append(mounts, container.SecretMounts()...)
 
 	container.Command.Mounts = mounts
-	if err := daemon.waitForStart(container); err != nil {
+	if err := daemon.startSeccompLearning(container); err != nil {
+		return err
+	}
+	if err := daemon.waitForStart(ctx, container); err != nil {
 		return err
 	}
 	container.HasBeenStartedBefore = true
 	return nil
 }
 
-func (daemon *Daemon) waitForStart(container *container.Container) error {
-	return container.StartMonitor(daemon)
+func (daemon *Daemon) waitForStart(ctx context.Context, container *container.Container) error {
+	return container.StartMonitor(daemon, requestAttributes(ctx, nil))
 }
 
 // Cleanup releases any network resources allocated to the container along with any rules
@@ -166,6 +182,10 @@ func (daemon *Daemon) Cleanup(container *container.Container) {
 	daemon.releaseNetwork(container)
 
 	container.UnmountIpcMounts(detachMounted)
+	container.UnmountSecrets(detachMounted)
+	container.UnmountRootfsView(detachMounted)
+
+	daemon.stopSeccompLearning(container)
 
 	daemon.conditionalUnmountOnCleanup(container)
 
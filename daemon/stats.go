@@ -49,6 +49,22 @@ func (daemon *Daemon) ContainerStats(prefixOrName string, config *backend.Contai
 		ss.Read = update.Read
 		ss.CPUStats.SystemUsage = update.SystemUsage
 		preCPUStats = ss.CPUStats
+		if !apiVersion.LessThan("1.23") && update.EndpointStats != nil {
+			// Break the network stats down per network endpoint
+			ss.Networks = make(map[string]types.NetworkStats)
+			for name, ns := range update.EndpointStats {
+				ss.Networks[name] = types.NetworkStats{
+					RxBytes:   ns.RxBytes,
+					RxPackets: ns.RxPackets,
+					RxErrors:  ns.RxErrors,
+					RxDropped: ns.RxDropped,
+					TxBytes:   ns.TxBytes,
+					TxPackets: ns.TxPackets,
+					TxErrors:  ns.TxErrors,
+					TxDropped: ns.TxDropped,
+				}
+			}
+		}
 		return ss
 	}
 
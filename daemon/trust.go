@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+)
+
+// verifyImageTrust checks the image referred to by refOrID against the
+// daemon's trust policy. Images referred to by ID are accepted if any of
+// their references covered by the policy is signed.
+func (daemon *Daemon) verifyImageTrust(refOrID string, imgID image.ID) error {
+	if daemon.trustVerifier == nil {
+		return nil
+	}
+
+	if ref, err := reference.ParseNamed(refOrID); err == nil {
+		if id, err := daemon.referenceStore.Get(ref); err == nil && id == imgID {
+			if !daemon.trustVerifier.Required(ref) {
+				return nil
+			}
+			return daemon.verifyReferenceTrust(ref, imgID)
+		}
+	}
+
+	var lastErr error
+	for _, ref := range daemon.referenceStore.References(imgID) {
+		if !daemon.trustVerifier.Required(ref) {
+			continue
+		}
+		if lastErr = daemon.verifyReferenceTrust(ref, imgID); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+// verifyReferenceTrust returns an error unless ref is signed and the signed
+// digest resolves to imgID.
+func (daemon *Daemon) verifyReferenceTrust(ref reference.Named, imgID image.ID) error {
+	if canonical, ok := ref.(reference.Canonical); ok {
+		return daemon.trustVerifier.VerifyDigest(ref, canonical.Digest(), nil)
+	}
+
+	tagged, ok := reference.WithDefaultTag(ref).(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("trust policy violation: %s has no tag or digest", ref.String())
+	}
+	trustedRef, err := daemon.trustVerifier.TrustedReference(tagged, nil)
+	if err != nil {
+		return err
+	}
+	if id, err := daemon.referenceStore.Get(trustedRef); err != nil || id != imgID {
+		return fmt.Errorf("trust policy violation: %s does not match its signed digest %s, pull it again", tagged.String(), trustedRef.Digest())
+	}
+	return nil
+}
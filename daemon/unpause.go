@@ -4,16 +4,17 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/container"
+	"golang.org/x/net/context"
 )
 
 // ContainerUnpause unpauses a container
-func (daemon *Daemon) ContainerUnpause(name string) error {
+func (daemon *Daemon) ContainerUnpause(ctx context.Context, name string) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
 	}
 
-	if err := daemon.containerUnpause(container); err != nil {
+	if err := daemon.containerUnpause(ctx, container); err != nil {
 		return err
 	}
 
@@ -21,7 +22,7 @@ func (daemon *Daemon) ContainerUnpause(name string) error {
 }
 
 // containerUnpause resumes the container execution after the container is paused.
-func (daemon *Daemon) containerUnpause(container *container.Container) error {
+func (daemon *Daemon) containerUnpause(ctx context.Context, container *container.Container) error {
 	container.Lock()
 	defer container.Unlock()
 
@@ -40,6 +41,6 @@ func (daemon *Daemon) containerUnpause(container *container.Container) error {
 	}
 
 	container.Paused = false
-	daemon.LogContainerEvent(container, "unpause")
+	daemon.LogContainerEventWithAttributes(container, "unpause", requestAttributes(ctx, nil))
 	return nil
 }
@@ -5,10 +5,11 @@ import (
 	"time"
 
 	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 // ContainerUpdate updates configuration of the container
-func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostConfig) ([]string, error) {
+func (daemon *Daemon) ContainerUpdate(ctx context.Context, name string, hostConfig *container.HostConfig) ([]string, error) {
 	var warnings []string
 
 	warnings, err := daemon.verifyContainerSettings(hostConfig, nil, true)
@@ -16,7 +17,7 @@ func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostCon
 		return warnings, err
 	}
 
-	if err := daemon.update(name, hostConfig); err != nil {
+	if err := daemon.update(ctx, name, hostConfig); err != nil {
 		return warnings, err
 	}
 
@@ -34,7 +35,7 @@ func (daemon *Daemon) ContainerUpdateCmdOnBuild(cID string, cmd []string) error
 	return nil
 }
 
-func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) error {
+func (daemon *Daemon) update(ctx context.Context, name string, hostConfig *container.HostConfig) error {
 	if hostConfig == nil {
 		return nil
 	}
@@ -87,7 +88,7 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 		}
 	}
 
-	daemon.LogContainerEvent(container, "update")
+	daemon.LogContainerEventWithAttributes(container, "update", requestAttributes(ctx, nil))
 
 	return nil
 }
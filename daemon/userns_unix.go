@@ -0,0 +1,196 @@
+// +build linux freebsd
+
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/volume"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// usernsRangeSize is the number of IDs of the ranges allocated to the
+// containers with a private user namespace.
+const usernsRangeSize = 65536
+
+// setupUsernsPool returns the pool the ID ranges of the containers with a
+// private user namespace are allocated from, or nil if --userns-pool is not
+// set.
+func setupUsernsPool(config *Config) (*idtools.Pool, error) {
+	if config.UsernsPool == "" {
+		return nil, nil
+	}
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("User namespaces are only supported on Linux")
+	}
+	if config.RemappedRoot != "" {
+		return nil, fmt.Errorf("--userns-pool and --userns-remap cannot be used together")
+	}
+	username, groupname, err := parseRemappedRoot(config.UsernsPool)
+	if err != nil {
+		return nil, err
+	}
+	if username == "root" {
+		return nil, fmt.Errorf("The subordinate IDs of root cannot be used by --userns-pool")
+	}
+	config.UsernsPool = fmt.Sprintf("%s:%s", username, groupname)
+
+	pool, err := idtools.NewPool(username, groupname, usernsRangeSize)
+	if err != nil {
+		return nil, fmt.Errorf("Can't create the user namespace pool: %v", err)
+	}
+	logrus.Infof("User namespaces: private ID ranges will be allocated from the subuid/subgid ranges of: %s:%s", username, groupname)
+	return pool, nil
+}
+
+// checkUsernsPoolDriver returns an error if --userns-pool is set with a
+// storage driver other than vfs. The files of the image are shifted to the
+// IDs of each container with a private user namespace, which only vfs does
+// without copying them up to the read-write layer.
+func checkUsernsPoolDriver(config *Config, graphDriver string) error {
+	if config.UsernsPool != "" && graphDriver != "vfs" {
+		return fmt.Errorf("--userns-pool is only supported with the vfs storage driver, not %s", graphDriver)
+	}
+	return nil
+}
+
+// setupUsernsPoolDirs makes the directories holding the containers and the
+// volumes traversable by the roots of the containers with a private user
+// namespace, which are not the owners of the directories.
+func setupUsernsPoolDirs(config *Config, daemonRepo string) error {
+	if config.UsernsPool == "" {
+		return nil
+	}
+	if err := os.Chmod(daemonRepo, 0701); err != nil {
+		return err
+	}
+	return os.Chmod(filepath.Join(config.Root, "volumes"), 0701)
+}
+
+// containerIDMaps returns the user and group ID mappings of the user
+// namespace of the container: either its private ones or the ones of the
+// daemon.
+func (daemon *Daemon) containerIDMaps(c *container.Container) ([]idtools.IDMap, []idtools.IDMap) {
+	if c.UIDMaps != nil {
+		return c.UIDMaps, c.GIDMaps
+	}
+	return daemon.GetUIDGIDMaps()
+}
+
+// containerRootUIDGID returns the host uid and gid the root of the
+// container is mapped to.
+func (daemon *Daemon) containerRootUIDGID(c *container.Container) (int, int) {
+	uidMaps, gidMaps := daemon.containerIDMaps(c)
+	uid, gid, _ := idtools.GetRootUIDGID(uidMaps, gidMaps)
+	return uid, gid
+}
+
+// allocateUserns allocates an ID range of the pool to the container if it
+// has a private user namespace.
+func (daemon *Daemon) allocateUserns(c *container.Container, hostConfig *containertypes.HostConfig) error {
+	if !hostConfig.UsernsMode.IsPerContainer() {
+		return nil
+	}
+	if daemon.usernsPool == nil {
+		return fmt.Errorf("A private user namespace requires the daemon to be started with --userns-pool")
+	}
+	uidMaps, gidMaps, err := daemon.usernsPool.Allocate()
+	if err != nil {
+		return err
+	}
+	c.UIDMaps, c.GIDMaps = uidMaps, gidMaps
+	return nil
+}
+
+// reserveUserns marks the ID range of a restored container with a private
+// user namespace as used. When it can't be, the container is still restored
+// but can't be started.
+func (daemon *Daemon) reserveUserns(c *container.Container) error {
+	if c.UIDMaps == nil {
+		return nil
+	}
+	var err error
+	if daemon.usernsPool == nil {
+		err = fmt.Errorf("the daemon is not started with --userns-pool")
+	} else {
+		err = daemon.usernsPool.Reserve(c.UIDMaps, c.GIDMaps)
+	}
+	c.UsernsError = err
+	return err
+}
+
+// checkUserns returns an error if the container has a private user
+// namespace whose ID range isn't reserved.
+func (daemon *Daemon) checkUserns(c *container.Container) error {
+	if c.UsernsError != nil {
+		return fmt.Errorf("Cannot start container %s: the ID range of its private user namespace could not be reserved: %v", c.ID, c.UsernsError)
+	}
+	return nil
+}
+
+// releaseUserns makes the ID range of the container available again.
+func (daemon *Daemon) releaseUserns(c *container.Container) {
+	if c.UIDMaps != nil && c.UsernsError == nil && daemon.usernsPool != nil {
+		daemon.usernsPool.Release(c.UIDMaps, c.GIDMaps)
+	}
+}
+
+// setupCreatedVolume makes a local volume the daemon just created for a
+// container with a private user namespace owned by its root. Volumes that
+// aren't empty are left alone.
+func (daemon *Daemon) setupCreatedVolume(c *container.Container, v volume.Volume) error {
+	if c.UIDMaps == nil || v.DriverName() != volume.DefaultDriverName {
+		return nil
+	}
+	f, err := os.Open(v.Path())
+	if err != nil {
+		return err
+	}
+	_, err = f.Readdirnames(1)
+	f.Close()
+	if err != io.EOF {
+		return err
+	}
+	rootUID, rootGID := daemon.containerRootUIDGID(c)
+	return os.Chown(v.Path(), rootUID, rootGID)
+}
+
+// layerIDMappings returns the ID mappings the read-write layer of the
+// container is shifted to, if it has a private user namespace.
+func layerIDMappings(c *container.Container) *layer.IDMappings {
+	if c.UIDMaps == nil {
+		return nil
+	}
+	return &layer.IDMappings{UIDMaps: c.UIDMaps, GIDMaps: c.GIDMaps}
+}
+
+// setupRootfsView bind mounts the root filesystem of a container with a
+// private user namespace in the directory of the container, as the root of
+// the container can't traverse the directories of the graph driver.
+func (daemon *Daemon) setupRootfsView(c *container.Container) error {
+	if c.UIDMaps == nil {
+		return nil
+	}
+	rootUID, rootGID := daemon.containerRootUIDGID(c)
+	viewPath, err := c.RootfsViewPath()
+	if err != nil {
+		return err
+	}
+	if err := idtools.MkdirAllAs(viewPath, 0700, rootUID, rootGID); err != nil {
+		return err
+	}
+	if err := mount.Mount(c.BaseFS, viewPath, "bind", "rbind,rprivate"); err != nil {
+		return fmt.Errorf("mounting the root filesystem view: %v", err)
+	}
+	c.Command.Rootfs = viewPath
+	return nil
+}
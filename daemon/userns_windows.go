@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/volume"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+func setupUsernsPool(config *Config) (*idtools.Pool, error) {
+	return nil, nil
+}
+
+func checkUsernsPoolDriver(config *Config, graphDriver string) error {
+	return nil
+}
+
+func setupUsernsPoolDirs(config *Config, daemonRepo string) error {
+	return nil
+}
+
+func (daemon *Daemon) containerIDMaps(c *container.Container) ([]idtools.IDMap, []idtools.IDMap) {
+	return daemon.GetUIDGIDMaps()
+}
+
+func (daemon *Daemon) containerRootUIDGID(c *container.Container) (int, int) {
+	return daemon.GetRemappedUIDGID()
+}
+
+func (daemon *Daemon) allocateUserns(c *container.Container, hostConfig *containertypes.HostConfig) error {
+	return nil
+}
+
+func (daemon *Daemon) reserveUserns(c *container.Container) error {
+	return nil
+}
+
+func (daemon *Daemon) checkUserns(c *container.Container) error {
+	return nil
+}
+
+func (daemon *Daemon) releaseUserns(c *container.Container) {
+}
+
+func (daemon *Daemon) setupCreatedVolume(c *container.Container, v volume.Volume) error {
+	return nil
+}
+
+func layerIDMappings(c *container.Container) *layer.IDMappings {
+	return nil
+}
+
+func (daemon *Daemon) setupRootfsView(c *container.Container) error {
+	return nil
+}
@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/volume"
+	volumestore "github.com/docker/docker/volume/store"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 	"github.com/opencontainers/runc/libcontainer/label"
 )
 
@@ -23,6 +27,24 @@ var (
 
 type mounts []execdriver.Mount
 
+// createContainerVolume creates, or gets if it already exists, the volume
+// name referenced by the container. A volume the daemon creates for the
+// container is set up for its user namespace.
+func (daemon *Daemon) createContainerVolume(c *container.Container, name, driverName string, opts map[string]string) (volume.Volume, error) {
+	_, err := daemon.volumes.Get(name)
+	created := volumestore.IsNotExist(err)
+	v, err := daemon.volumes.CreateWithRef(name, driverName, c.ID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		if err := daemon.setupCreatedVolume(c, v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
 // volumeToAPIType converts a volume.Volume to the type used by the remote API
 func volumeToAPIType(v volume.Volume) *types.Volume {
 	return &types.Volume{
@@ -60,7 +82,9 @@ func (m mounts) parts(i int) int {
 // 1. Select the previously configured mount points for the containers, if any.
 // 2. Select the volumes mounted from another containers. Overrides previously configured mount point destination.
 // 3. Select the bind mounts set by the client. Overrides previously configured mount point destinations.
-// 4. Cleanup old volumes that are about to be reassigned.
+// 4. Select the bind and volume mounts of the structured mounts set by the client. tmpfs mounts have no
+//    mount point; they are mounted from the host config when the container starts.
+// 5. Cleanup old volumes that are about to be reassigned.
 func (daemon *Daemon) registerMountPoints(container *container.Container, hostConfig *containertypes.HostConfig) error {
 	binds := map[string]bool{}
 	mountPoints := map[string]*volume.MountPoint{}
@@ -119,7 +143,7 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 
 		if len(bind.Name) > 0 {
 			// create the volume
-			v, err := daemon.volumes.CreateWithRef(bind.Name, bind.Driver, container.ID, nil)
+			v, err := daemon.createContainerVolume(container, bind.Name, bind.Driver, nil)
 			if err != nil {
 				return err
 			}
@@ -141,9 +165,70 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 		mountPoints[bind.Destination] = bind
 	}
 
+	// 4. Read structured mounts
+	for _, cfg := range hostConfig.Mounts {
+		if cfg.Type == mounttypes.TypeTmpfs {
+			if binds[filepath.Clean(cfg.Target)] {
+				return fmt.Errorf("Duplicate mount point '%s'", filepath.Clean(cfg.Target))
+			}
+			continue
+		}
+
+		mp := &volume.MountPoint{
+			Destination: filepath.Clean(cfg.Target),
+			RW:          !cfg.ReadOnly,
+			Propagation: volume.DefaultPropagationMode,
+		}
+		if binds[mp.Destination] {
+			return fmt.Errorf("Duplicate mount point '%s'", mp.Destination)
+		}
+
+		switch cfg.Type {
+		case mounttypes.TypeBind:
+			mp.Source = filepath.Clean(cfg.Source)
+			if cfg.BindOptions != nil && cfg.BindOptions.Propagation != "" {
+				mp.Propagation = string(cfg.BindOptions.Propagation)
+			}
+		case mounttypes.TypeVolume:
+			driver := hostConfig.VolumeDriver
+			var driverOpts map[string]string
+			if cfg.VolumeOptions != nil && cfg.VolumeOptions.DriverConfig != nil {
+				if cfg.VolumeOptions.DriverConfig.Name != "" {
+					driver = cfg.VolumeOptions.DriverConfig.Name
+				}
+				driverOpts = cfg.VolumeOptions.DriverConfig.Options
+			}
+
+			name := cfg.Source
+			if name == "" {
+				name = stringid.GenerateNonCryptoID()
+			} else {
+				mp.Named = true
+			}
+			v, err := daemon.createContainerVolume(container, name, driver, driverOpts)
+			if err != nil {
+				return err
+			}
+			mp.Volume = v
+			mp.Name = v.Name()
+			mp.Source = v.Path()
+			mp.Driver = v.DriverName()
+			if mp.Driver == volume.DefaultDriverName {
+				mp = setBindModeIfNull(mp)
+			}
+		}
+		if label.RelabelNeeded(mp.Mode) {
+			if err := label.Relabel(mp.Source, container.MountLabel, label.IsShared(mp.Mode)); err != nil {
+				return err
+			}
+		}
+		binds[mp.Destination] = true
+		mountPoints[mp.Destination] = mp
+	}
+
 	container.Lock()
 
-	// 4. Cleanup old volumes that are about to be reassigned.
+	// 5. Cleanup old volumes that are about to be reassigned.
 	for _, m := range mountPoints {
 		if m.BackwardsCompatible() {
 			if mp, exists := container.MountPoints[m.Destination]; exists && mp.Volume != nil {
@@ -158,6 +243,95 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 	return nil
 }
 
+// validateMounts checks that the structured mounts of a container are valid
+// and do not share a target.
+func validateMounts(mnts []mounttypes.Mount) error {
+	targets := map[string]bool{}
+	for _, m := range mnts {
+		if err := validateMount(m); err != nil {
+			return err
+		}
+		target := filepath.Clean(m.Target)
+		if targets[target] {
+			return fmt.Errorf("Duplicate mount point '%s'", target)
+		}
+		targets[target] = true
+	}
+	return nil
+}
+
+func validateMount(m mounttypes.Mount) error {
+	if len(m.Target) == 0 {
+		return errMountConfig(m, "target is required")
+	}
+	if !filepath.IsAbs(m.Target) {
+		return errMountConfig(m, fmt.Sprintf("target path '%s' must be absolute", m.Target))
+	}
+	if target := filepath.Clean(m.Target); filepath.Dir(target) == target {
+		return errMountConfig(m, "target can't be the root directory")
+	}
+
+	if m.BindOptions != nil && m.Type != mounttypes.TypeBind {
+		return errMountConfig(m, "BindOptions can only be set for bind mounts")
+	}
+	if m.VolumeOptions != nil && m.Type != mounttypes.TypeVolume {
+		return errMountConfig(m, "VolumeOptions can only be set for volume mounts")
+	}
+	if m.TmpfsOptions != nil && m.Type != mounttypes.TypeTmpfs {
+		return errMountConfig(m, "TmpfsOptions can only be set for tmpfs mounts")
+	}
+
+	switch m.Type {
+	case mounttypes.TypeBind:
+		if len(m.Source) == 0 {
+			return errMountConfig(m, "source is required")
+		}
+		if !filepath.IsAbs(m.Source) {
+			return errMountConfig(m, fmt.Sprintf("source path '%s' must be absolute", m.Source))
+		}
+		if _, err := os.Stat(m.Source); err != nil {
+			if os.IsNotExist(err) {
+				return errMountConfig(m, fmt.Sprintf("bind source path does not exist: %s", m.Source))
+			}
+			return errMountConfig(m, err.Error())
+		}
+		if m.BindOptions != nil && m.BindOptions.Propagation != "" {
+			if p := string(m.BindOptions.Propagation); volume.GetPropagation(p) != p {
+				return errMountConfig(m, fmt.Sprintf("invalid propagation mode: %s", p))
+			}
+		}
+	case mounttypes.TypeVolume:
+		if filepath.IsAbs(m.Source) {
+			return errMountConfig(m, fmt.Sprintf("source '%s' must be a volume name, not a path", m.Source))
+		}
+		if len(m.Source) > 0 {
+			if valid, err := volume.IsVolumeNameValid(m.Source); !valid {
+				if err == nil {
+					err = fmt.Errorf("invalid volume name: %s", m.Source)
+				}
+				return errMountConfig(m, err.Error())
+			}
+		}
+	case mounttypes.TypeTmpfs:
+		if runtime.GOOS == "windows" {
+			return errMountConfig(m, "tmpfs mounts are not supported on Windows")
+		}
+		if len(m.Source) > 0 {
+			return errMountConfig(m, "source must not be set for tmpfs mounts")
+		}
+		if _, err := volume.ConvertTmpfsOptions(m.TmpfsOptions, m.ReadOnly); err != nil {
+			return errMountConfig(m, err.Error())
+		}
+	default:
+		return fmt.Errorf("invalid mount config: mount type unknown: %q", m.Type)
+	}
+	return nil
+}
+
+func errMountConfig(m mounttypes.Mount, msg string) error {
+	return fmt.Errorf("invalid mount config for type %q: %s", m.Type, msg)
+}
+
 // lazyInitializeVolume initializes a mountpoint's volume if needed.
 // This happens after a daemon restart.
 func (daemon *Daemon) lazyInitializeVolume(containerID string, m *volume.MountPoint) error {
@@ -1,9 +1,13 @@
 package daemon
 
 import (
+	"io/ioutil"
+	"os"
+	"runtime"
 	"testing"
 
 	"github.com/docker/docker/volume"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 func TestParseVolumesFrom(t *testing.T) {
@@ -37,3 +41,56 @@ func TestParseVolumesFrom(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateMounts(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("mount paths and propagation modes are Linux specific")
+	}
+	tmpdir, err := ioutil.TempDir("", "validate-mounts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	valid := [][]mounttypes.Mount{
+		{{Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo"}},
+		{{Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo", ReadOnly: true, BindOptions: &mounttypes.BindOptions{Propagation: mounttypes.PropagationRShared}}},
+		{{Type: mounttypes.TypeVolume, Target: "/foo"}},
+		{{Type: mounttypes.TypeVolume, Source: "data", Target: "/foo", VolumeOptions: &mounttypes.VolumeOptions{DriverConfig: &mounttypes.Driver{Name: "local"}}}},
+		{{Type: mounttypes.TypeTmpfs, Target: "/foo", TmpfsOptions: &mounttypes.TmpfsOptions{SizeBytes: 1 << 20, Mode: 0700}}},
+		{
+			{Type: mounttypes.TypeVolume, Target: "/foo"},
+			{Type: mounttypes.TypeTmpfs, Target: "/bar"},
+		},
+	}
+	for _, mounts := range valid {
+		if err := validateMounts(mounts); err != nil {
+			t.Fatalf("expected %+v to be valid, got %v", mounts, err)
+		}
+	}
+
+	invalid := [][]mounttypes.Mount{
+		{{Type: mounttypes.TypeVolume}},
+		{{Type: mounttypes.TypeVolume, Target: "foo"}},
+		{{Type: mounttypes.TypeVolume, Target: "/"}},
+		{{Type: "invalid", Target: "/foo"}},
+		{{Type: mounttypes.TypeBind, Target: "/foo"}},
+		{{Type: mounttypes.TypeBind, Source: "relative", Target: "/foo"}},
+		{{Type: mounttypes.TypeBind, Source: tmpdir + "/missing", Target: "/foo"}},
+		{{Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo", BindOptions: &mounttypes.BindOptions{Propagation: "invalid"}}},
+		{{Type: mounttypes.TypeVolume, Source: tmpdir, Target: "/foo"}},
+		{{Type: mounttypes.TypeVolume, Target: "/foo", BindOptions: &mounttypes.BindOptions{}}},
+		{{Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo", TmpfsOptions: &mounttypes.TmpfsOptions{}}},
+		{{Type: mounttypes.TypeTmpfs, Source: "data", Target: "/foo"}},
+		{{Type: mounttypes.TypeTmpfs, Target: "/foo", TmpfsOptions: &mounttypes.TmpfsOptions{SizeBytes: -1}}},
+		{
+			{Type: mounttypes.TypeVolume, Target: "/foo"},
+			{Type: mounttypes.TypeTmpfs, Target: "/foo/"},
+		},
+	}
+	for _, mounts := range invalid {
+		if err := validateMounts(mounts); err == nil {
+			t.Fatalf("expected %+v to be invalid", mounts)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package distribution
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// ImageDeleteConfig stores configuration for deleting an image from a
+// registry.
+type ImageDeleteConfig struct {
+	// MetaHeaders stores HTTP headers with metadata about the image
+	MetaHeaders map[string][]string
+	// AuthConfig holds authentication credentials for authenticating with
+	// the registry.
+	AuthConfig *types.AuthConfig
+	// RegistryService is the registry service to use for TLS configuration
+	// and endpoint lookup.
+	RegistryService *registry.Service
+	// DryRun lists what would be deleted without deleting anything.
+	DryRun bool
+}
+
+// Delete deletes the manifest that ref points to from its registry. ref may
+// be a tag or a digest. The registry removes every tag that points to a
+// deleted manifest, so the result lists all of them as untagged, followed by
+// the digest of the manifest. Only v2 registries support deletion.
+func Delete(ctx context.Context, ref reference.Named, imageDeleteConfig *ImageDeleteConfig) ([]types.ImageDelete, error) {
+	repoInfo, err := imageDeleteConfig.RegistryService.ResolveRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// makes sure name is not empty or `scratch`
+	if err := validateRepoName(repoInfo.Name()); err != nil {
+		return nil, err
+	}
+
+	endpoints, err := imageDeleteConfig.RegistryService.LookupPushEndpoints(repoInfo.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, endpoint := range endpoints {
+		if endpoint.Version != registry.APIVersion2 {
+			continue
+		}
+
+		logrus.Debugf("Trying to delete %s from %s %s", ref.String(), endpoint.URL, endpoint.Version)
+
+		repo, _, err := NewV2Repository(ctx, repoInfo, endpoint, imageDeleteConfig.MetaHeaders, imageDeleteConfig.AuthConfig, "*")
+		if err != nil {
+			if fallbackErr, ok := err.(fallbackError); ok {
+				lastErr = fallbackErr.err
+				logrus.Debugf("Attempting next endpoint for delete after error: %v", lastErr)
+				continue
+			}
+			return nil, err
+		}
+		return deleteManifest(ctx, repo, ref, imageDeleteConfig.DryRun)
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no v2 endpoint found to delete %s from", ref.String())
+	}
+	return nil, lastErr
+}
+
+func deleteManifest(ctx context.Context, repo distribution.Repository, ref reference.Named, dryRun bool) ([]types.ImageDelete, error) {
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dgst digest.Digest
+	switch r := ref.(type) {
+	case reference.Canonical:
+		dgst = r.Digest()
+	case reference.NamedTagged:
+		if dgst, err = tagDigest(ctx, manSvc, r.Tag()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("cannot delete %s from the registry: a tag or digest is required", ref.String())
+	}
+
+	tags, err := taggedWith(ctx, repo, manSvc, dgst)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []types.ImageDelete
+	for _, tag := range tags {
+		tagged, err := reference.WithTag(ref, tag)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, types.ImageDelete{Untagged: tagged.String()})
+	}
+	list = append(list, types.ImageDelete{Deleted: dgst.String()})
+
+	if dryRun {
+		return list, nil
+	}
+	if err := manSvc.Delete(ctx, dgst); err != nil {
+		return nil, fmt.Errorf("failed to delete %s from the registry: %v", dgst, err)
+	}
+	return list, nil
+}
+
+// tagDigest returns the digest of the manifest a tag points to in the
+// registry.
+func tagDigest(ctx context.Context, manSvc distribution.ManifestService, tag string) (digest.Digest, error) {
+	m, err := manSvc.Get(ctx, "", client.WithTag(tag))
+	if err != nil {
+		return "", err
+	}
+	return manifestDigest(m)
+}
+
+// taggedWith returns the tags of the repository that point to the manifest
+// dgst.
+func taggedWith(ctx context.Context, repo distribution.Repository, manSvc distribution.ManifestService, dgst digest.Digest) ([]string, error) {
+	all, err := repo.Tags(ctx).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tags of %s: %v", repo.Named().Name(), err)
+	}
+
+	var tags []string
+	for _, tag := range all {
+		tagDgst, err := tagDigest(ctx, manSvc, tag)
+		if err != nil {
+			return nil, err
+		}
+		if tagDgst == dgst {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// manifestDigest returns the digest a registry stores a manifest under.
+// Schema1 manifests are addressed by their payload without signatures.
+func manifestDigest(m distribution.Manifest) (digest.Digest, error) {
+	if signed, ok := m.(*schema1.SignedManifest); ok {
+		return digest.FromBytes(signed.Canonical), nil
+	}
+	_, payload, err := m.Payload()
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(payload), nil
+}
@@ -0,0 +1,42 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/pkg/platform"
+)
+
+// checkImagePlatform returns an error if an image config declares an
+// operating system, architecture or variant other than the platform's.
+func checkImagePlatform(p platform.Spec, img *image.Image) error {
+	if (img.OS != "" && img.OS != p.OS) || (img.Architecture != "" && img.Architecture != p.Architecture) ||
+		(img.Variant != "" && p.Variant != "" && img.Variant != p.Variant) {
+		declared := platform.Spec{OS: img.OS, Architecture: img.Architecture, Variant: img.Variant}
+		return fmt.Errorf("image is for %s, not %s", declared, p)
+	}
+	return nil
+}
+
+// addConfigVariant returns the image config with the variant of the
+// architecture set, unless it is empty or the config declares one.
+func addConfigVariant(config []byte, variant string) ([]byte, error) {
+	if variant == "" {
+		return config, nil
+	}
+	var c map[string]*json.RawMessage
+	if err := json.Unmarshal(config, &c); err != nil {
+		return nil, err
+	}
+	if _, ok := c["variant"]; ok {
+		return config, nil
+	}
+	v, err := json.Marshal(variant)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage(v)
+	c["variant"] = &raw
+	return json.Marshal(c)
+}
@@ -0,0 +1,63 @@
+package distribution
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/pkg/platform"
+)
+
+func TestCheckImagePlatform(t *testing.T) {
+	p := platform.Spec{OS: "linux", Architecture: "arm", Variant: "v7"}
+	img := func(os, arch, variant string) *image.Image {
+		return &image.Image{V1Image: image.V1Image{OS: os, Architecture: arch, Variant: variant}}
+	}
+
+	if err := checkImagePlatform(p, img("linux", "arm", "v7")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Images that do not declare a platform are accepted.
+	if err := checkImagePlatform(p, img("", "", "")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkImagePlatform(p, img("linux", "arm", "")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkImagePlatform(p, img("linux", "arm", "v6")); err == nil {
+		t.Fatal("expected an error for a different variant")
+	}
+	if err := checkImagePlatform(p, img("linux", "amd64", "")); err == nil {
+		t.Fatal("expected an error for a different architecture")
+	}
+	if err := checkImagePlatform(p, img("windows", "arm", "")); err == nil {
+		t.Fatal("expected an error for a different operating system")
+	}
+}
+
+func TestAddConfigVariant(t *testing.T) {
+	config := []byte(`{"architecture":"arm","os":"linux"}`)
+
+	out, err := addConfigVariant(config, "")
+	if err != nil || string(out) != string(config) {
+		t.Fatalf("expected the config to be unchanged without a variant, got %s, %v", out, err)
+	}
+
+	out, err = addConfigVariant(config, "v7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var img image.Image
+	if err := json.Unmarshal(out, &img); err != nil {
+		t.Fatal(err)
+	}
+	if img.Variant != "v7" || img.Architecture != "arm" || img.OS != "linux" {
+		t.Fatalf("expected the variant v7 to be added, got %s", out)
+	}
+
+	declared := []byte(`{"architecture":"arm","os":"linux","variant":"v6"}`)
+	out, err = addConfigVariant(declared, "v7")
+	if err != nil || string(out) != string(declared) {
+		t.Fatalf("expected the declared variant to be kept, got %s, %v", out, err)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/trust"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/pkg/platform"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -39,6 +41,12 @@ type ImagePullConfig struct {
 	ReferenceStore reference.Store
 	// DownloadManager manages concurrent pulls.
 	DownloadManager *xfer.LayerDownloadManager
+	// Platform selects the manifest list entry to pull. If nil, the
+	// daemon's own platform is used.
+	Platform *platform.Spec
+	// TrustVerifier enforces the daemon's trust policy. Repositories
+	// covered by the policy are pulled by signed digest only.
+	TrustVerifier *trust.Verifier
 }
 
 // Puller is an interface that abstracts pulling for different API versions.
@@ -88,6 +96,65 @@ func Pull(ctx context.Context, ref reference.Named, imagePullConfig *ImagePullCo
 		return err
 	}
 
+	if imagePullConfig.TrustVerifier.Required(ref) {
+		return trustedPull(ctx, ref, repoInfo, imagePullConfig)
+	}
+	return pull(ctx, ref, repoInfo, imagePullConfig)
+}
+
+// trustedPull pulls the signed digests that the tags of ref resolve to, and
+// points the tags at the pulled images.
+func trustedPull(ctx context.Context, ref reference.Named, repoInfo *registry.RepositoryInfo, imagePullConfig *ImagePullConfig) error {
+	verifier := imagePullConfig.TrustVerifier
+
+	if canonical, ok := ref.(reference.Canonical); ok {
+		if err := verifier.VerifyDigest(ref, canonical.Digest(), imagePullConfig.AuthConfig); err != nil {
+			return err
+		}
+		return pull(ctx, ref, repoInfo, imagePullConfig)
+	}
+
+	var tags []reference.NamedTagged
+	if tagged, ok := ref.(reference.NamedTagged); ok {
+		tags = append(tags, tagged)
+	} else {
+		targets, err := verifier.Targets(ref, imagePullConfig.AuthConfig)
+		if err != nil {
+			return err
+		}
+		for _, t := range targets {
+			tagged, err := reference.WithTag(ref, t.Tag)
+			if err != nil {
+				return err
+			}
+			tags = append(tags, tagged)
+		}
+	}
+
+	for i, tagged := range tags {
+		trustedRef, err := verifier.TrustedReference(tagged, imagePullConfig.AuthConfig)
+		if err != nil {
+			return err
+		}
+		progress.Messagef(imagePullConfig.ProgressOutput, "", "Pull (%d of %d): %s", i+1, len(tags), trustedRef.String())
+
+		if err := pull(ctx, trustedRef, repoInfo, imagePullConfig); err != nil {
+			return err
+		}
+		imgID, err := imagePullConfig.ReferenceStore.Get(trustedRef)
+		if err != nil {
+			return err
+		}
+		if err := imagePullConfig.ReferenceStore.AddTag(tagged, imgID, true); err != nil {
+			return err
+		}
+		progress.Messagef(imagePullConfig.ProgressOutput, "", "Tagging %s as %s", trustedRef.String(), tagged.String())
+	}
+	return nil
+}
+
+// pull tries each of the configured endpoints in turn until ref is pulled.
+func pull(ctx context.Context, ref reference.Named, repoInfo *registry.RepositoryInfo, imagePullConfig *ImagePullConfig) error {
 	endpoints, err := imagePullConfig.RegistryService.LookupPullEndpoints(repoInfo.Hostname())
 	if err != nil {
 		return err
@@ -26,6 +26,7 @@ import (
 	"github.com/docker/docker/image/v1"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/platform"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/reference"
@@ -55,6 +56,9 @@ type v2Puller struct {
 	// confirmedV2 is set to true if we confirm we're talking to a v2
 	// registry. This is used to limit fallbacks to the v1 protocol.
 	confirmedV2 bool
+	// variant is the variant of the architecture of the manifest list entry
+	// being pulled. It is added to the image config if it declares none.
+	variant string
 }
 
 func (p *v2Puller) Pull(ctx context.Context, ref reference.Named) (err error) {
@@ -418,6 +422,16 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 		return "", "", err
 	}
 
+	if p.config.Platform != nil {
+		var img image.Image
+		if err := json.Unmarshal([]byte(verifiedManifest.History[0].V1Compatibility), &img); err != nil {
+			return "", "", err
+		}
+		if err := checkImagePlatform(*p.config.Platform, &img); err != nil {
+			return "", "", err
+		}
+	}
+
 	var descriptors []xfer.DownloadDescriptor
 
 	// Image history converted to the new format
@@ -466,6 +480,11 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 		return "", "", err
 	}
 
+	config, err = addConfigVariant(config, p.variant)
+	if err != nil {
+		return "", "", err
+	}
+
 	imageID, err = p.config.ImageStore.Create(config)
 	if err != nil {
 		return "", "", err
@@ -567,6 +586,12 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 		}
 	}
 
+	if p.config.Platform != nil {
+		if err := checkImagePlatform(*p.config.Platform, &unmarshalledConfig); err != nil {
+			return "", "", err
+		}
+	}
+
 	// The DiffIDs returned in rootFS MUST match those in the config.
 	// Otherwise the image config could be referencing layers that aren't
 	// included in the manifest.
@@ -580,6 +605,11 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 		}
 	}
 
+	configJSON, err = addConfigVariant(configJSON, p.variant)
+	if err != nil {
+		return "", "", err
+	}
+
 	imageID, err = p.config.ImageStore.Create(configJSON)
 	if err != nil {
 		return "", "", err
@@ -611,19 +641,28 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 		return "", "", err
 	}
 
-	var manifestDigest digest.Digest
+	spec := platform.DefaultSpec()
+	if p.config.Platform != nil {
+		spec = *p.config.Platform
+	}
+
+	var (
+		manifestDigest digest.Digest
+		variant        string
+	)
 	for _, manifestDescriptor := range mfstList.Manifests {
-		// TODO(aaronl): The manifest list spec supports optional
-		// "features" and "variant" fields. These are not yet used.
-		// Once they are, their values should be interpreted here.
-		if manifestDescriptor.Platform.Architecture == runtime.GOARCH && manifestDescriptor.Platform.OS == runtime.GOOS {
+		// TODO(aaronl): The manifest list spec supports an optional
+		// "features" field. It is not yet used. Once it is, its
+		// value should be interpreted here.
+		if spec.Matches(manifestDescriptor.Platform.OS, manifestDescriptor.Platform.Architecture, manifestDescriptor.Platform.Variant) {
 			manifestDigest = manifestDescriptor.Digest
+			variant = manifestDescriptor.Platform.Variant
 			break
 		}
 	}
 
 	if manifestDigest == "" {
-		return "", "", errors.New("no supported platform found in manifest list")
+		return "", "", fmt.Errorf("no matching manifest for %s found in manifest list", spec)
 	}
 
 	manSvc, err := p.repo.Manifests(ctx)
@@ -641,6 +680,11 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 		return "", "", err
 	}
 
+	// Image configs rarely declare the variant of the architecture, the
+	// one of the manifest list entry is added to the config.
+	p.variant = variant
+	defer func() { p.variant = "" }()
+
 	switch v := manifest.(type) {
 	case *schema1.SignedManifest:
 		imageID, _, err = p.pullSchema1(ctx, manifestRef, v)
@@ -0,0 +1,83 @@
+package trust
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/reference"
+)
+
+// Policy lists the registries and namespaces for which the daemon requires
+// images to be signed.
+type Policy struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// Scope is a registry hostname (e.g. "registry.example.com") or a
+// repository name prefix (e.g. "docker.io/myorg") which requires signed
+// images.
+type Scope struct {
+	// Name is the registry hostname or repository prefix the scope covers.
+	Name string `json:"name"`
+	// Server is the URL of the notary server holding the trust data for
+	// the scope. If empty, the server is derived from the registry.
+	Server string `json:"server,omitempty"`
+}
+
+// LoadPolicy reads a trust policy from a JSON file.
+func LoadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var p Policy
+	if err := json.NewDecoder(f).Decode(&p); err != nil {
+		return nil, fmt.Errorf("error parsing trust policy %s: %v", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks that every scope of the policy has a name and that the
+// notary server, if specified, is an https URL.
+func (p *Policy) Validate() error {
+	for _, s := range p.Scopes {
+		if s.Name == "" {
+			return fmt.Errorf("invalid trust policy: scope name can't be empty")
+		}
+		if s.Server != "" {
+			u, err := url.Parse(s.Server)
+			if err != nil || u.Scheme != "https" {
+				return fmt.Errorf("invalid trust policy: valid https URL required for trust server of %s, got %s", s.Name, s.Server)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the most specific scope covering the repository ref, or
+// false if no scope requires signatures for it.
+func (p *Policy) Match(ref reference.Named) (Scope, bool) {
+	var (
+		match Scope
+		found bool
+	)
+	name := ref.FullName()
+	for _, s := range p.Scopes {
+		scope := strings.TrimSuffix(s.Name, "/")
+		if name != scope && !strings.HasPrefix(name, scope+"/") {
+			continue
+		}
+		if !found || len(scope) > len(strings.TrimSuffix(match.Name, "/")) {
+			match, found = s, true
+		}
+	}
+	return match, found
+}
@@ -0,0 +1,83 @@
+package trust
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/reference"
+)
+
+func TestPolicyMatch(t *testing.T) {
+	p := &Policy{
+		Scopes: []Scope{
+			{Name: "registry.example.com"},
+			{Name: "registry.example.com/team", Server: "https://notary.example.com"},
+			{Name: "docker.io/myorg/"},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		match  bool
+		server string
+	}{
+		{"registry.example.com/app", true, ""},
+		{"registry.example.com/team/app:1.0", true, "https://notary.example.com"},
+		{"registry.example.com/teamapp", true, ""},
+		{"myorg/app", true, ""},
+		{"docker.io/myorg/app@sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4", true, ""},
+		{"myorganization/app", false, ""},
+		{"ubuntu", false, ""},
+		{"registry.example.org/app", false, ""},
+	}
+
+	for _, c := range cases {
+		ref, err := reference.ParseNamed(c.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scope, ok := p.Match(ref)
+		if ok != c.match {
+			t.Fatalf("expected match %v for %s, got %v", c.match, c.name, ok)
+		}
+		if scope.Server != c.server {
+			t.Fatalf("expected server %q for %s, got %q", c.server, c.name, scope.Server)
+		}
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "trust-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	valid := filepath.Join(tmp, "valid.json")
+	if err := ioutil.WriteFile(valid, []byte(`{"scopes": [{"name": "registry.example.com", "server": "https://notary.example.com"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Scopes) != 1 || p.Scopes[0].Name != "registry.example.com" {
+		t.Fatalf("unexpected policy %+v", p)
+	}
+
+	for _, invalid := range []string{
+		`{"scopes": [{"name": ""}]}`,
+		`{"scopes": [{"name": "registry.example.com", "server": "http://notary.example.com"}]}`,
+		`{"scopes": `,
+	} {
+		f := filepath.Join(tmp, "invalid.json")
+		if err := ioutil.WriteFile(f, []byte(invalid), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(f); err == nil {
+			t.Fatalf("expected error loading policy %s", invalid)
+		}
+	}
+}
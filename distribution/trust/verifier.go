@@ -0,0 +1,220 @@
+package trust
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/notary/client"
+	"github.com/docker/notary/passphrase"
+	"github.com/docker/notary/tuf/data"
+)
+
+var releasesRole = path.Join(data.CanonicalTargetsRole, "releases")
+
+// Target is a signed tag of a repository.
+type Target struct {
+	Tag    string
+	Digest digest.Digest
+}
+
+// Verifier resolves and checks image signatures for the repositories covered
+// by a trust policy.
+type Verifier struct {
+	policy          *Policy
+	trustDir        string
+	registryService *registry.Service
+}
+
+// NewVerifier returns a Verifier enforcing policy. Trust data and pinned
+// keys are kept in trustDir.
+func NewVerifier(policy *Policy, trustDir string, registryService *registry.Service) *Verifier {
+	return &Verifier{
+		policy:          policy,
+		trustDir:        trustDir,
+		registryService: registryService,
+	}
+}
+
+// Required reports whether the policy requires signed images for the
+// repository ref.
+func (v *Verifier) Required(ref reference.Named) bool {
+	if v == nil || v.policy == nil {
+		return false
+	}
+	_, ok := v.policy.Match(ref)
+	return ok
+}
+
+// TrustedReference resolves the tag of ref to the digest it is signed with.
+func (v *Verifier) TrustedReference(ref reference.NamedTagged, authConfig *types.AuthConfig) (reference.Canonical, error) {
+	repo, err := v.repository(ref, authConfig)
+	if err != nil {
+		return nil, err
+	}
+	t, err := repo.GetTargetByName(ref.Tag(), releasesRole, data.CanonicalTargetsRole)
+	if err != nil {
+		return nil, notaryError(ref.Name(), err)
+	}
+	target, err := convertTarget(t.Target)
+	if err != nil {
+		return nil, err
+	}
+	return reference.WithDigest(ref, target.Digest)
+}
+
+// Targets returns all signed tags of the repository ref.
+func (v *Verifier) Targets(ref reference.Named, authConfig *types.AuthConfig) ([]Target, error) {
+	repo, err := v.repository(ref, authConfig)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := repo.ListTargets(releasesRole, data.CanonicalTargetsRole)
+	if err != nil {
+		return nil, notaryError(ref.Name(), err)
+	}
+	var result []Target
+	for _, tgt := range targets {
+		t, err := convertTarget(tgt.Target)
+		if err != nil {
+			logrus.Debugf("Skipping target %q for %s: %v", tgt.Name, ref.Name(), err)
+			continue
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// VerifyDigest returns an error unless dgst is the digest of a signed tag
+// of the repository ref.
+func (v *Verifier) VerifyDigest(ref reference.Named, dgst digest.Digest, authConfig *types.AuthConfig) error {
+	targets, err := v.Targets(ref, authConfig)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if t.Digest == dgst {
+			return nil
+		}
+	}
+	return fmt.Errorf("trust policy violation: %s@%s is not signed", ref.Name(), dgst)
+}
+
+// repository returns the notary repository holding the trust data for ref.
+func (v *Verifier) repository(ref reference.Named, authConfig *types.AuthConfig) (*client.NotaryRepository, error) {
+	scope, ok := v.policy.Match(ref)
+	if !ok {
+		return nil, fmt.Errorf("no trust policy covers %s", ref.Name())
+	}
+	repoInfo, err := v.registryService.ResolveRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	server := scope.Server
+	if server == "" {
+		if repoInfo.Index.Official {
+			server = registry.NotaryServer
+		} else {
+			server = "https://" + repoInfo.Index.Name
+		}
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := v.registryService.TLSConfig(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.InsecureSkipVerify = !repoInfo.Index.Secure
+
+	base := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     tlsConfig,
+		DisableKeepAlives:   true,
+	}
+
+	modifiers := registry.DockerHeaders(dockerversion.DockerUserAgent(), http.Header{})
+	authTransport := transport.NewTransport(base, modifiers...)
+	pingClient := &http.Client{
+		Transport: authTransport,
+		Timeout:   5 * time.Second,
+	}
+	endpointStr := server + "/v2/"
+	req, err := http.NewRequest("GET", endpointStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	challengeManager := auth.NewSimpleChallengeManager()
+
+	resp, err := pingClient.Do(req)
+	if err != nil {
+		// Ignore error on ping to operate from the cached trust data
+		logrus.Debugf("Error pinging notary server %q: %s", endpointStr, err)
+	} else {
+		defer resp.Body.Close()
+		if err := challengeManager.AddResponse(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	if authConfig == nil {
+		authConfig = &types.AuthConfig{}
+	}
+	creds := credentialStore{auth: *authConfig}
+	tokenHandler := auth.NewTokenHandler(authTransport, creds, repoInfo.FullName(), "pull")
+	basicHandler := auth.NewBasicHandler(creds)
+	modifiers = append(modifiers, transport.RequestModifier(auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler)))
+	tr := transport.NewTransport(base, modifiers...)
+
+	// The daemon only reads trust data, so it never needs a passphrase.
+	return client.NewNotaryRepository(v.trustDir, repoInfo.FullName(), server, tr, passphrase.ConstantRetriever(""))
+}
+
+type credentialStore struct {
+	auth types.AuthConfig
+}
+
+func (cs credentialStore) Basic(u *url.URL) (string, string) {
+	return cs.auth.Username, cs.auth.Password
+}
+
+func convertTarget(t client.Target) (Target, error) {
+	h, ok := t.Hashes["sha256"]
+	if !ok {
+		return Target{}, errors.New("no valid hash, expecting sha256")
+	}
+	return Target{
+		Tag:    t.Name,
+		Digest: digest.NewDigestFromHex("sha256", hex.EncodeToString(h)),
+	}, nil
+}
+
+func notaryError(repoName string, err error) error {
+	switch err.(type) {
+	case client.ErrRepositoryNotExist:
+		return fmt.Errorf("trust policy violation: no trust data for %s: %v", repoName, err)
+	}
+	return fmt.Errorf("trust policy violation: could not verify signature for %s: %v", repoName, err)
+}
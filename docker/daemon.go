@@ -20,6 +20,7 @@ import (
 	"github.com/docker/docker/api/server/router/container"
 	"github.com/docker/docker/api/server/router/image"
 	"github.com/docker/docker/api/server/router/network"
+	"github.com/docker/docker/api/server/router/secret"
 	systemrouter "github.com/docker/docker/api/server/router/system"
 	"github.com/docker/docker/api/server/router/volume"
 	"github.com/docker/docker/builder/dockerfile"
@@ -30,6 +31,8 @@ import (
 	"github.com/docker/docker/docker/listeners"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/opts"
+	"github.com/docker/docker/pkg/authentication"
+	"github.com/docker/docker/pkg/authorization/rbac"
 	"github.com/docker/docker/pkg/jsonlog"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/pidfile"
@@ -65,6 +68,7 @@ func NewDaemonCli() *DaemonCli {
 	// TODO(tiborvass): remove InstallFlags?
 	daemonConfig := new(daemon.Config)
 	daemonConfig.LogConfig.Config = make(map[string]string)
+	daemonConfig.AuditLogOpts = make(map[string]string)
 	daemonConfig.ClusterOpts = make(map[string]string)
 
 	if runtime.GOOS != "linux" {
@@ -159,6 +163,11 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	cli.flags.ParseFlags(args, true)
 	commonFlags.PostParse()
 
+	rootlessMode, err := setRootlessDefaults(cli.Config, cli.flags, commonFlags, configFile)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
 	if commonFlags.TrustKey == "" {
 		commonFlags.TrustKey = filepath.Join(getDaemonConfDir(), defaultTrustKeyFile)
 	}
@@ -182,6 +191,12 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 		DisableColors:   cli.Config.RawLogs,
 	})
 
+	if rootlessMode {
+		if exit, code := startRootless(cli.Config); exit {
+			os.Exit(code)
+		}
+	}
+
 	if err := setDefaultUmask(); err != nil {
 		logrus.Fatalf("Failed to set umask: %v", err)
 	}
@@ -214,6 +229,24 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	}
 	serverConfig = setPlatformServerConfig(serverConfig, cli.Config)
 
+	authenticators, err := newAuthenticators(cli.Config)
+	if err != nil {
+		logrus.Fatalf("Error setting up API authentication: %v", err)
+	}
+	serverConfig.Authenticators = authenticators
+
+	var authorizer *rbac.Authorizer
+	if cli.Config.AuthorizationPolicy != "" {
+		if len(authenticators) == 0 {
+			logrus.Fatal("--authorization-policy requires at least one --authentication-method")
+		}
+		authorizer, err = rbac.NewAuthorizer(cli.Config.AuthorizationPolicy)
+		if err != nil {
+			logrus.Fatalf("Error loading authorization policy: %v", err)
+		}
+		serverConfig.Authorizers = append(serverConfig.Authorizers, authorizer)
+	}
+
 	if cli.Config.TLS {
 		tlsOptions := tlsconfig.Options{
 			CAFile:   cli.Config.CommonTLSOptions.CAFile,
@@ -253,13 +286,49 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 		if err != nil {
 			logrus.Fatal(err)
 		}
+		if protoAddrParts[0] == "unix" && hasAuthenticationMethod(cli.Config, authentication.PeerCredMethod) {
+			for i := range l {
+				l[i] = authentication.NewPeerCredListener(l[i])
+			}
+		}
 
 		logrus.Debugf("Listener created for HTTP on %s (%s)", protoAddrParts[0], protoAddrParts[1])
 		api.Accept(protoAddrParts[1], l...)
 	}
 
-	if err := migrateKey(); err != nil {
-		logrus.Fatal(err)
+	for _, host := range cli.Config.RestrictedHosts {
+		if strings.TrimSpace(host) == "" {
+			logrus.Fatal("--restricted-host requires an address")
+		}
+		protoAddr, err := opts.ParseHost(cli.Config.TLS, host)
+		if err != nil {
+			logrus.Fatalf("error parsing --restricted-host %s : %v", host, err)
+		}
+		protoAddrParts := strings.SplitN(protoAddr, "://", 2)
+		if len(protoAddrParts) != 2 {
+			logrus.Fatalf("bad format %s, expected PROTO://ADDR", protoAddr)
+		}
+		l, err := listeners.Init(protoAddrParts[0], protoAddrParts[1], cli.Config.RestrictedGroup, serverConfig.TLSConfig)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		for i := range l {
+			if protoAddrParts[0] == "unix" && hasAuthenticationMethod(cli.Config, authentication.PeerCredMethod) {
+				l[i] = authentication.NewPeerCredListener(l[i])
+			}
+			if l[i], err = listeners.NewRestrictedListener(l[i], cli.Config.RestrictedRoutes); err != nil {
+				logrus.Fatalf("Error setting up the restricted listener %s: %v", protoAddr, err)
+			}
+		}
+
+		logrus.Debugf("Restricted listener created for HTTP on %s (%s)", protoAddrParts[0], protoAddrParts[1])
+		api.Accept(protoAddrParts[1], l...)
+	}
+
+	if !rootlessMode {
+		if err := migrateKey(); err != nil {
+			logrus.Fatal(err)
+		}
 	}
 	cli.TrustKeyPath = commonFlags.TrustKey
 
@@ -283,9 +352,23 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 		"graphdriver": d.GraphDriverName(),
 	}).Info("Docker daemon")
 
+	if d.AuditLogger != nil {
+		serverConfig.AuditLogger = d.AuditLogger
+		serverConfig.AuditResolver = d
+	}
+	if authorizer != nil {
+		authorizer.SetResolver(d)
+	}
 	initRouter(api, d)
 
 	reload := func(config *daemon.Config) {
+		if authorizer != nil {
+			if err := authorizer.Reload(config.AuthorizationPolicy); err != nil {
+				logrus.Errorf("Error reloading the authorization policy: %v", err)
+			}
+		} else if config.AuthorizationPolicy != "" {
+			logrus.Warn("The authorization policy can't be enabled on reload, the daemon must be restarted")
+		}
 		if err := d.Reload(config); err != nil {
 			logrus.Errorf("Error reconfiguring the daemon: %v", err)
 			return
@@ -315,7 +398,7 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	signal.Trap(func() {
 		api.Close()
 		<-serveAPIWait
-		shutdownDaemon(d, 15)
+		shutdownDaemon(d, d.ShutdownTimeout())
 		if pfile != nil {
 			if err := pfile.Remove(); err != nil {
 				logrus.Error(err)
@@ -329,7 +412,7 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	// Daemon is fully initialized and handling API traffic
 	// Wait for serve API to complete
 	errAPI := <-serveAPIWait
-	shutdownDaemon(d, 15)
+	shutdownDaemon(d, d.ShutdownTimeout())
 	if errAPI != nil {
 		if pfile != nil {
 			if err := pfile.Remove(); err != nil {
@@ -343,17 +426,22 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 
 // shutdownDaemon just wraps daemon.Shutdown() to handle a timeout in case
 // d.Shutdown() is waiting too long to kill container or worst it's
-// blocked there
-func shutdownDaemon(d *daemon.Daemon, timeout time.Duration) {
+// blocked there. A negative timeout waits for d.Shutdown() to return.
+func shutdownDaemon(d *daemon.Daemon, timeout int) {
 	ch := make(chan struct{})
 	go func() {
 		d.Shutdown()
 		close(ch)
 	}()
+	if timeout < 0 {
+		<-ch
+		logrus.Debug("Clean shutdown succeeded")
+		return
+	}
 	select {
 	case <-ch:
 		logrus.Debug("Clean shutdown succeeded")
-	case <-time.After(timeout * time.Second):
+	case <-time.After(time.Duration(timeout) * time.Second):
 		logrus.Error("Force shutdown daemon")
 	}
 }
@@ -398,12 +486,54 @@ func loadDaemonCliConfig(config *daemon.Config, daemonFlags *flag.FlagSet, commo
 	return config, nil
 }
 
+// newAuthenticators returns the API authenticators of the methods enabled
+// in the daemon configuration, in order of precedence.
+func newAuthenticators(config *daemon.Config) ([]authentication.Authenticator, error) {
+	var authenticators []authentication.Authenticator
+	for _, method := range config.AuthenticationMethods {
+		switch method {
+		case authentication.TLSMethod:
+			if !config.TLSVerify {
+				return nil, fmt.Errorf("the %s authentication method requires --tlsverify", method)
+			}
+			authenticators = append(authenticators, authentication.NewTLSAuthenticator())
+		case authentication.PeerCredMethod:
+			if runtime.GOOS != "linux" {
+				return nil, fmt.Errorf("the %s authentication method is only supported on Linux", method)
+			}
+			authenticators = append(authenticators, authentication.NewPeerCredAuthenticator())
+		case authentication.TokenMethod:
+			if config.AuthenticationTokenFile == "" {
+				return nil, fmt.Errorf("the %s authentication method requires --authentication-token-file", method)
+			}
+			a, err := authentication.NewTokenAuthenticator(config.AuthenticationTokenFile)
+			if err != nil {
+				return nil, err
+			}
+			authenticators = append(authenticators, a)
+		default:
+			return nil, fmt.Errorf("unknown authentication method %q", method)
+		}
+	}
+	return authenticators, nil
+}
+
+func hasAuthenticationMethod(config *daemon.Config, method string) bool {
+	for _, m := range config.AuthenticationMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
 func initRouter(s *apiserver.Server, d *daemon.Daemon) {
 	routers := []router.Router{
 		container.NewRouter(d),
 		image.NewRouter(d),
 		systemrouter.NewRouter(d),
 		volume.NewRouter(d),
+		secret.NewRouter(d),
 		build.NewRouter(dockerfile.NewBuildManager(d)),
 	}
 	if d.NetworkControllerEnabled() {
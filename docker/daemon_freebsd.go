@@ -2,6 +2,25 @@
 
 package main
 
+import (
+	"fmt"
+
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/pkg/mflag"
+)
+
 // notifySystem sends a message to the host when the server is ready to be used
 func notifySystem() {
 }
+
+func setRootlessDefaults(config *daemon.Config, flags *mflag.FlagSet, commonFlags *cli.CommonFlags, configFile *string) (bool, error) {
+	if config.Rootless {
+		return true, fmt.Errorf("--rootless is only supported on Linux")
+	}
+	return false, nil
+}
+
+func startRootless(config *daemon.Config) (bool, int) {
+	return false, 0
+}
@@ -3,7 +3,18 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/Sirupsen/logrus"
 	systemdDaemon "github.com/coreos/go-systemd/daemon"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/pkg/homedir"
+	"github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/pkg/rootless"
 )
 
 // notifySystem sends a message to the host when the server is ready to be used
@@ -11,3 +22,83 @@ func notifySystem() {
 	// Tell the init daemon we are accepting requests
 	go systemdDaemon.SdNotify("READY=1")
 }
+
+// setRootlessDefaults replaces the defaults of the paths only root can write
+// to with paths in the directories of the user when --rootless is set, and
+// reports whether it is.
+func setRootlessDefaults(config *daemon.Config, flags *mflag.FlagSet, commonFlags *cli.CommonFlags, configFile *string) (bool, error) {
+	if !config.Rootless {
+		return false, nil
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return true, fmt.Errorf("--rootless requires XDG_RUNTIME_DIR to be set")
+	}
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		dataDir = filepath.Join(homedir.Get(), ".local", "share")
+	}
+	confDir := os.Getenv("XDG_CONFIG_HOME")
+	if confDir == "" {
+		confDir = filepath.Join(homedir.Get(), ".config")
+	}
+
+	if !isFlagSet(flags, "g", "-graph") {
+		config.Root = filepath.Join(dataDir, "docker")
+	}
+	if !isFlagSet(flags, "p", "-pidfile") {
+		config.Pidfile = filepath.Join(runtimeDir, "docker.pid")
+	}
+	if !isFlagSet(flags, "G", "-group") {
+		config.SocketGroup = ""
+	}
+	if len(commonFlags.Hosts) == 0 {
+		commonFlags.Hosts = []string{"unix://" + filepath.Join(runtimeDir, "docker.sock")}
+	}
+	if commonFlags.TrustKey == "" {
+		commonFlags.TrustKey = filepath.Join(confDir, "docker", defaultTrustKeyFile)
+	}
+	if !flags.IsSet(daemonConfigFileFlag) {
+		*configFile = filepath.Join(confDir, "docker", "daemon.json")
+	}
+	return true, nil
+}
+
+func isFlagSet(flags *mflag.FlagSet, names ...string) bool {
+	for _, name := range names {
+		if flags.IsSet(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// startRootless starts the daemon again in the namespaces of a rootless
+// daemon, unless it already runs in them, and reports whether the current
+// process must exit with the returned code.
+func startRootless(config *daemon.Config) (bool, int) {
+	if rootless.InNamespaces() {
+		if err := rootless.Init(); err != nil {
+			logrus.Fatalf("Error starting the rootless daemon: %v", err)
+		}
+		return false, 0
+	}
+	if rootless.Running() {
+		return false, 0
+	}
+	if os.Geteuid() == 0 {
+		logrus.Fatal("--rootless requires the daemon to be started as an unprivileged user")
+	}
+
+	opts := rootless.Options{MTU: config.Mtu}
+	if path, err := exec.LookPath("slirp4netns"); err == nil {
+		opts.Slirp4netns = path
+	} else {
+		logrus.Warn("slirp4netns not found, the daemon and its containers won't have access to the network of the host")
+	}
+	code, err := rootless.Run(opts)
+	if err != nil {
+		logrus.Fatalf("Error starting the rootless daemon: %v", err)
+	}
+	return true, code
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	apiserver "github.com/docker/docker/api/server"
+	"github.com/docker/docker/cli"
 	"github.com/docker/docker/daemon"
 	"github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/system"
@@ -35,6 +36,14 @@ func getDaemonConfDir() string {
 	return os.Getenv("PROGRAMDATA") + `\docker\config`
 }
 
+func setRootlessDefaults(config *daemon.Config, flags *mflag.FlagSet, commonFlags *cli.CommonFlags, configFile *string) (bool, error) {
+	return false, nil
+}
+
+func startRootless(config *daemon.Config) (bool, int) {
+	return false, 0
+}
+
 // notifySystem sends a message to the host when the server is ready to be used
 func notifySystem() {
 }
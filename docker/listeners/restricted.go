@@ -0,0 +1,35 @@
+package listeners
+
+import (
+	"net"
+
+	apiserver "github.com/docker/docker/api/server"
+)
+
+// restrictedListener is a listener whose API requests are limited to an
+// allowlist of routes.
+type restrictedListener struct {
+	net.Listener
+	routes []string
+}
+
+// NewRestrictedListener returns a listener limiting the API requests of the
+// connections of l to routes, methods and paths where `*` stands for a path
+// parameter, like `GET /containers/*/json`. The read-only routes of
+// apiserver.DefaultRestrictedRoutes are allowed if routes is empty.
+func NewRestrictedListener(l net.Listener, routes []string) (net.Listener, error) {
+	if len(routes) == 0 {
+		routes = apiserver.DefaultRestrictedRoutes
+	}
+	for _, route := range routes {
+		if _, err := apiserver.ParseRoute(route); err != nil {
+			return nil, err
+		}
+	}
+	return &restrictedListener{Listener: l, routes: routes}, nil
+}
+
+// AllowedRoutes returns the routes allowed on the listener.
+func (l *restrictedListener) AllowedRoutes() []string {
+	return l.routes
+}
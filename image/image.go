@@ -41,6 +41,8 @@ type V1Image struct {
 	Architecture string `json:"architecture,omitempty"`
 	// OS is the operating system used to build and run the image
 	OS string `json:"os,omitempty"`
+	// Variant is the variant of the architecture, for example v7 for arm
+	Variant string `json:"variant,omitempty"`
 	// Size is the total size of the image including all layers it is composed of
 	Size int64 `json:",omitempty"`
 }
@@ -118,7 +120,7 @@ type History struct {
 type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
-	Save([]string, io.Writer) error
+	Save([]string, []string, io.Writer) error
 }
 
 // NewFromJSON creates an Image configuration from json.
@@ -88,6 +88,11 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 			r.Append(diffID)
 			newLayer, err := l.ls.Get(r.ChainID())
 			if err != nil {
+				if _, err := os.Stat(layerPath); os.IsNotExist(err) {
+					// the layer was left out of the archive with
+					// --exclude-base, so it must already exist locally
+					return fmt.Errorf("layer %s was excluded from the archive and does not exist locally, load its base image first", diffID)
+				}
 				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), progressOutput)
 				if err != nil {
 					return err
@@ -25,18 +25,54 @@ type imageDescriptor struct {
 
 type saveSession struct {
 	*tarexporter
-	outDir      string
-	images      map[image.ID]*imageDescriptor
-	savedLayers map[string]struct{}
+	outDir         string
+	images         map[image.ID]*imageDescriptor
+	savedLayers    map[string]struct{}
+	excludedLayers map[layer.ChainID]struct{}
 }
 
-func (l *tarexporter) Save(names []string, outStream io.Writer) error {
+// Save writes the images referenced by names to outStream. Layers that are
+// part of any image referenced by excludeBase are left out of the archive;
+// the receiver is expected to already have them in its layer store.
+func (l *tarexporter) Save(names []string, excludeBase []string, outStream io.Writer) error {
 	images, err := l.parseNames(names)
 	if err != nil {
 		return err
 	}
 
-	return (&saveSession{tarexporter: l, images: images}).save(outStream)
+	excluded, err := l.baseLayers(excludeBase)
+	if err != nil {
+		return err
+	}
+
+	return (&saveSession{tarexporter: l, images: images, excludedLayers: excluded}).save(outStream)
+}
+
+// baseLayers returns the chain IDs of all layers of the images referenced
+// by names.
+func (l *tarexporter) baseLayers(names []string) (map[layer.ChainID]struct{}, error) {
+	chainIDs := make(map[layer.ChainID]struct{})
+	if len(names) == 0 {
+		return chainIDs, nil
+	}
+
+	bases, err := l.parseNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for id := range bases {
+		img, err := l.is.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		rootFS := *img.RootFS
+		for i := range img.RootFS.DiffIDs {
+			rootFS.DiffIDs = img.RootFS.DiffIDs[:i+1]
+			chainIDs[rootFS.ChainID()] = struct{}{}
+		}
+	}
+	return chainIDs, nil
 }
 
 func (l *tarexporter) parseNames(names []string) (map[image.ID]*imageDescriptor, error) {
@@ -223,8 +259,10 @@ func (s *saveSession) saveImage(id image.ID) error {
 			v1Img.Parent = parent.Hex()
 		}
 
-		if err := s.saveLayer(rootFS.ChainID(), v1Img, img.Created); err != nil {
-			return err
+		if _, excluded := s.excludedLayers[rootFS.ChainID()]; !excluded {
+			if err := s.saveLayer(rootFS.ChainID(), v1Img, img.Created); err != nil {
+				return err
+			}
 		}
 		layers = append(layers, v1Img.ID)
 		parent = v1ID
@@ -33,6 +33,7 @@ func (s *DockerSuite) TearDownTest(c *check.C) {
 	deleteAllImages()
 	deleteAllVolumes()
 	deleteAllNetworks()
+	deleteAllSecrets()
 }
 
 func init() {
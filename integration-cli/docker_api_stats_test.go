@@ -146,6 +146,33 @@ func (s *DockerSuite) TestApiStatsNetworkStatsVersioning(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestApiStatsNetworkStatsPerEndpoint(c *check.C) {
+	testRequires(c, SameHostDaemon)
+	testRequires(c, DaemonIsLinux)
+
+	dockerCmd(c, "network", "create", "statsnet")
+	out, _ := runSleepingContainer(c)
+	id := strings.TrimSpace(out)
+	c.Assert(waitRun(id), checker.IsNil)
+	dockerCmd(c, "network", "connect", "statsnet", id)
+
+	nwStats := getNetworkStats(c, id)
+	c.Assert(nwStats, checker.HasLen, 2)
+	for _, name := range []string{"bridge", "statsnet"} {
+		_, ok := nwStats[name]
+		c.Assert(ok, checker.True, check.Commentf("no stats for network %s: %v", name, nwStats))
+	}
+
+	// Older API versions are keyed by interface name
+	statsJSONBlob := getVersionedStats(c, id, "v1.22")
+	networks, ok := statsJSONBlob["networks"].(map[string]interface{})
+	c.Assert(ok, checker.True)
+	for _, name := range []string{"eth0", "eth1"} {
+		_, ok := networks[name]
+		c.Assert(ok, checker.True, check.Commentf("no stats for interface %s: %v", name, networks))
+	}
+}
+
 func getNetworkStats(c *check.C, id string) map[string]types.NetworkStats {
 	var st *types.StatsJSON
 
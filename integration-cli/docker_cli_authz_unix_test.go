@@ -15,6 +15,7 @@ import (
 	"bytes"
 	"os/exec"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/docker/docker/pkg/authorization"
@@ -53,6 +54,7 @@ type authorizationController struct {
 	psRequestCnt  int                    // psRequestCnt counts the number of calls to list container request api
 	psResponseCnt int                    // psResponseCnt counts the number of calls to list containers response API
 	requestsURIs  []string               // requestsURIs stores all request URIs that are sent to the authorization controller
+	users         []string               // users stores the user and authentication method of the requests sent to the authorization controller
 }
 
 func (s *DockerAuthzSuite) SetUpTest(c *check.C) {
@@ -94,6 +96,7 @@ func (s *DockerAuthzSuite) SetUpSuite(c *check.C) {
 		}
 
 		s.ctrl.requestsURIs = append(s.ctrl.requestsURIs, authReq.RequestURI)
+		s.ctrl.users = append(s.ctrl.users, authReq.User+"/"+strings.Join(authReq.UserGroups, ",")+"@"+authReq.UserAuthNMethod)
 
 		reqRes := s.ctrl.reqRes
 		if isAllowed(authReq.RequestURI) {
@@ -348,6 +351,124 @@ func (s *DockerAuthzSuite) TestAuthZPluginEnsureNoDuplicatePluginRegistration(c
 	c.Assert(s.ctrl.psResponseCnt, check.Equals, 1)
 }
 
+func (s *DockerAuthzSuite) TestAuthZPluginAuthenticatedUser(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	tokenFile, err := ioutil.TempFile("", "authn-tokens")
+	c.Assert(err, checker.IsNil)
+	defer os.Remove(tokenFile.Name())
+	_, err = tokenFile.WriteString("secret-token alice team-a,ops\n")
+	c.Assert(err, checker.IsNil)
+	tokenFile.Close()
+
+	c.Assert(s.d.Start("--authorization-plugin="+testAuthZPlugin,
+		"--authentication-method=token", "--authentication-method=peercred",
+		"--authentication-token-file="+tokenFile.Name()), check.IsNil)
+	s.ctrl.reqRes.Allow = true
+	s.ctrl.resRes.Allow = true
+
+	// Unix socket callers are identified by their process credentials
+	out, err := s.d.Cmd("ps")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(s.ctrl.users[len(s.ctrl.users)-1], checker.HasPrefix, "root/root")
+	c.Assert(s.ctrl.users[len(s.ctrl.users)-1], checker.HasSuffix, "@peercred")
+
+	// Bearer tokens take precedence
+	configDir, err := ioutil.TempDir("", "authn-config")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(configDir)
+	err = ioutil.WriteFile(configDir+"/config.json", []byte(`{"HttpHeaders": {"Authorization": "Bearer secret-token"}}`), 0600)
+	c.Assert(err, checker.IsNil)
+
+	out, err = s.d.CmdWithArgs([]string{"--config", configDir}, "ps")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(s.ctrl.users[len(s.ctrl.users)-1], checker.Equals, "alice/team-a,ops@token")
+
+	// Invalid tokens are rejected
+	err = ioutil.WriteFile(configDir+"/config.json", []byte(`{"HttpHeaders": {"Authorization": "Bearer wrong-token"}}`), 0600)
+	c.Assert(err, checker.IsNil)
+	out, err = s.d.CmdWithArgs([]string{"--config", configDir}, "ps")
+	c.Assert(err, check.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "invalid bearer token")
+}
+
+func (s *DockerAuthzSuite) TestAuthZBuiltinPolicy(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	dir, err := ioutil.TempDir("", "authz-policy")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(dir)
+
+	tokenFile := dir + "/tokens"
+	err = ioutil.WriteFile(tokenFile, []byte("alice-token alice team-a\nbob-token bob\n"), 0600)
+	c.Assert(err, checker.IsNil)
+	configFile := dir + "/daemon.json"
+	err = ioutil.WriteFile(configFile, []byte("{}"), 0600)
+	c.Assert(err, checker.IsNil)
+
+	policy := `{"rules": [
+		{"users": ["root"], "routes": ["*"]},
+		{"users": ["*"], "methods": ["GET"], "routes": ["*"]},
+		{"name": "team-a-exec", "groups": ["team-a"], "methods": ["POST"],
+		 "routes": ["/containers/{name}/exec", "/exec/{name}/start", "/exec/{name}/resize"],
+		 "labels": {"team": "a"}}
+	]}`
+	policyFile := dir + "/policy.json"
+	err = ioutil.WriteFile(policyFile, []byte(policy), 0600)
+	c.Assert(err, checker.IsNil)
+
+	// root is identified by its process credentials on the unix socket
+	c.Assert(s.d.StartWithBusybox("--config-file="+configFile,
+		"--authentication-method=token", "--authentication-method=peercred",
+		"--authentication-token-file="+tokenFile, "--authorization-policy="+policyFile), check.IsNil)
+
+	out, err := s.d.Cmd("run", "-d", "--name", "web-a", "--label", "team=a", "busybox", "top")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	out, err = s.d.Cmd("run", "-d", "--name", "web-b", "--label", "team=b", "busybox", "top")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+
+	alice := tokenClientConfig(c, dir+"/alice", "alice-token")
+	bob := tokenClientConfig(c, dir+"/bob", "bob-token")
+
+	out, err = s.d.CmdWithArgs(alice, "ps")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	out, err = s.d.CmdWithArgs(alice, "exec", "web-a", "true")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	out, err = s.d.CmdWithArgs(alice, "exec", "web-b", "true")
+	c.Assert(err, check.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "is not allowed for user alice")
+	out, err = s.d.CmdWithArgs(alice, "kill", "web-a")
+	c.Assert(err, check.NotNil, check.Commentf(out))
+
+	out, err = s.d.CmdWithArgs(bob, "exec", "web-a", "true")
+	c.Assert(err, check.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "is not allowed for user bob")
+
+	// The policy is reloaded on SIGHUP
+	policy = strings.Replace(policy, `"groups": ["team-a"]`, `"users": ["bob"], "groups": ["team-a"]`, 1)
+	err = ioutil.WriteFile(policyFile, []byte(policy), 0600)
+	c.Assert(err, checker.IsNil)
+	syscall.Kill(s.d.cmd.Process.Pid, syscall.SIGHUP)
+
+	for i := 0; ; i++ {
+		out, err = s.d.CmdWithArgs(bob, "exec", "web-a", "true")
+		if err == nil {
+			break
+		}
+		c.Assert(i < 20, checker.True, check.Commentf("bob is still denied after reloading the policy: %s", out))
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// tokenClientConfig writes a client configuration sending the bearer token
+// in dir and returns the arguments to use it.
+func tokenClientConfig(c *check.C, dir, token string) []string {
+	c.Assert(os.MkdirAll(dir, 0700), checker.IsNil)
+	config := fmt.Sprintf(`{"HttpHeaders": {"Authorization": "Bearer %s"}}`, token)
+	c.Assert(ioutil.WriteFile(dir+"/config.json", []byte(config), 0600), checker.IsNil)
+	return []string{"--config", dir}
+}
+
 // assertURIRecorded verifies that the given URI was sent and recorded in the authz plugin
 func assertURIRecorded(c *check.C, uris []string, uri string) {
 	var found bool
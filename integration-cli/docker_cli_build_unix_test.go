@@ -204,3 +204,35 @@ func (s *DockerSuite) TestBuildCancellationKillsSleep(c *check.C) {
 		// ignore, done
 	}
 }
+
+func (s *DockerSuite) TestBuildSquash(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildsquash"
+
+	layerCount := func(image string) int {
+		out, _, err := runCommandPipelineWithOutput(
+			exec.Command(dockerBinary, "save", image),
+			exec.Command("tar", "t"),
+			exec.Command("grep", "layer.tar"))
+		c.Assert(err, checker.IsNil, check.Commentf("failed to save image: %s", out))
+		return len(strings.Split(strings.TrimSpace(out), "\n"))
+	}
+	historyCount := func(image string) int {
+		out, _ := dockerCmd(c, "history", "-q", image)
+		return len(strings.Split(strings.TrimSpace(out), "\n"))
+	}
+
+	_, err := buildImage(name, `FROM busybox
+		RUN echo removed > /removed
+		RUN rm /removed
+		RUN echo kept > /kept`, true, "--squash")
+	c.Assert(err, checker.IsNil)
+
+	c.Assert(layerCount(name), checker.Equals, layerCount("busybox")+1)
+	// the history of each instruction is kept, plus an entry for the merged layer
+	c.Assert(historyCount(name), checker.Equals, historyCount("busybox")+4)
+
+	out, _ := dockerCmd(c, "run", "--rm", name, "sh", "-c", "cat /kept; test -e /removed || echo missing")
+	c.Assert(out, checker.Contains, "kept")
+	c.Assert(out, checker.Contains, "missing")
+}
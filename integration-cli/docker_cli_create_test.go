@@ -426,6 +426,20 @@ func (s *DockerSuite) TestCreateStopSignal(c *check.C) {
 
 }
 
+func (s *DockerSuite) TestCreateStopTimeout(c *check.C) {
+	name := "test_create_stop_timeout"
+	dockerCmd(c, "create", "--name", name, "--stop-timeout", "120", "busybox")
+
+	res := inspectFieldJSON(c, name, "Config.StopTimeout")
+	c.Assert(res, checker.Equals, "120")
+
+	name = "test_create_no_stop_timeout"
+	dockerCmd(c, "create", "--name", name, "busybox")
+
+	res = inspectFieldJSON(c, name, "Config.StopTimeout")
+	c.Assert(res, checker.Equals, "null")
+}
+
 func (s *DockerSuite) TestCreateWithWorkdir(c *check.C) {
 	// TODO Windows. This requires further investigation for porting to
 	// Windows CI. Currently fails.
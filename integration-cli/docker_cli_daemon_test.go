@@ -67,6 +67,51 @@ func (s *DockerDaemonSuite) TestDaemonRestartWithRunningContainersPorts(c *check
 	testRun(map[string]bool{"top1": true, "top2": false}, "After daemon restart: ")
 }
 
+func (s *DockerDaemonSuite) TestDaemonRestartKeepsNetworkLabels(c *check.C) {
+	c.Assert(s.d.Start(), check.IsNil)
+
+	out, err := s.d.Cmd("network", "create", "--label", "team=web", "labelnet")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+
+	c.Assert(s.d.Restart(), check.IsNil)
+
+	out, err = s.d.Cmd("network", "inspect", "--format={{.Labels.team}}", "labelnet")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), check.Equals, "web")
+
+	out, err = s.d.Cmd("network", "ls", "-f", "label=team=web")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "labelnet")
+}
+
+func (s *DockerDaemonSuite) TestDaemonCPURealtime(c *check.C) {
+	testRequires(c, SameHostDaemon, cpuRealtime)
+	c.Assert(s.d.StartWithBusybox("--cpu-rt-runtime=950000", "--cpu-rt-period=1000000"), check.IsNil)
+
+	file := "/sys/fs/cgroup/cpu/cpu.rt_runtime_us"
+	out, err := s.d.Cmd("run", "--rm", "--cpu-rt-runtime=10000", "busybox", "cat", file)
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), check.Equals, "10000")
+
+	out, err = s.d.Cmd("run", "--rm", "--cpu-rt-period=1000", "--cpu-rt-runtime=10000", "busybox", "true")
+	c.Assert(err, check.NotNil)
+	c.Assert(out, checker.Contains, "CPU real-time runtime cannot be higher than CPU real-time period")
+}
+
+func (s *DockerDaemonSuite) TestDaemonDefaultInit(c *check.C) {
+	testRequires(c, SameHostDaemon)
+	c.Assert(s.d.StartWithBusybox("--init"), check.IsNil)
+
+	out, err := s.d.Cmd("run", "--rm", "busybox", "cat", "/proc/1/comm")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), check.Equals, "init")
+
+	// An explicit setting on the container overrides the daemon default.
+	out, err = s.d.Cmd("run", "--rm", "--init=false", "busybox", "cat", "/proc/1/comm")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), check.Equals, "cat")
+}
+
 func (s *DockerDaemonSuite) TestDaemonRestartWithVolumesRefs(c *check.C) {
 	if err := s.d.StartWithBusybox(); err != nil {
 		c.Fatal(err)
@@ -428,6 +473,139 @@ func (s *DockerDaemonSuite) TestDaemonIPv6FixedCIDRAndMac(c *check.C) {
 	c.Assert(err, checker.IsNil)
 }
 
+// TestDaemonIPv6PublishNAT checks that with --ipv6-publish=nat the published
+// ports are mapped on the IPv6 host addresses as well
+func (s *DockerDaemonSuite) TestDaemonIPv6PublishNAT(c *check.C) {
+	// IPv6 setup is messing with local bridge address.
+	testRequires(c, SameHostDaemon, IPv6, NotUserNamespace)
+	err := setupV6()
+	c.Assert(err, checker.IsNil, check.Commentf("Could not set up host for IPv6 tests"))
+	defer teardownV6()
+
+	err = s.d.StartWithBusybox("--ipv6", "--fixed-cidr-v6=2001:db8:3::/64", "--ipv6-publish=nat")
+	c.Assert(err, checker.IsNil, check.Commentf("Could not start daemon with busybox: %v", err))
+
+	out, err := s.d.Cmd("run", "-d", "--name=ipv6nat", "-p", "80", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, err = s.d.Cmd("port", "ipv6nat", "80")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	frontends := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(frontends, checker.HasLen, 2, check.Commentf(out))
+
+	_, port, err := net.SplitHostPort(frontends[0])
+	c.Assert(err, checker.IsNil)
+	c.Assert(out, checker.Contains, "0.0.0.0:"+port)
+	c.Assert(out, checker.Contains, "[::]:"+port)
+
+	// The userland proxy accepts the connections on the IPv6 loopback
+	conn, err := net.Dial("tcp", net.JoinHostPort("::1", port))
+	c.Assert(err, checker.IsNil)
+	conn.Close()
+
+	out, err = s.d.Cmd("rm", "-f", "ipv6nat")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	_, err = net.Dial("tcp", net.JoinHostPort("::1", port))
+	c.Assert(err, checker.NotNil, check.Commentf("IPv6 port should have been released"))
+}
+
+// TestDaemonIPv6PublishRouted checks that with --ipv6-publish=routed the
+// published ports are opened on the container global IPv6 address
+func (s *DockerDaemonSuite) TestDaemonIPv6PublishRouted(c *check.C) {
+	// IPv6 setup is messing with local bridge address.
+	testRequires(c, SameHostDaemon, IPv6, NotUserNamespace)
+	err := setupV6()
+	c.Assert(err, checker.IsNil, check.Commentf("Could not set up host for IPv6 tests"))
+	defer teardownV6()
+
+	err = s.d.StartWithBusybox("--ipv6", "--fixed-cidr-v6=2001:db8:4::/64", "--ipv6-publish=routed")
+	c.Assert(err, checker.IsNil, check.Commentf("Could not start daemon with busybox: %v", err))
+
+	out, err := s.d.Cmd("run", "-d", "--name=ipv6routed", "-p", "80", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, err = s.d.Cmd("inspect", "--format", "{{.NetworkSettings.Networks.bridge.GlobalIPv6Address}}", "ipv6routed")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	ip := strings.TrimSpace(out)
+	c.Assert(net.ParseIP(ip), checker.NotNil, check.Commentf("Container should have a global IPv6 address"))
+
+	out, err = s.d.Cmd("port", "ipv6routed", "80")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "0.0.0.0:")
+	c.Assert(out, checker.Contains, net.JoinHostPort(ip, "80"))
+
+	rules, err := exec.Command("ip6tables", "-S", "DOCKER").CombinedOutput()
+	c.Assert(err, checker.IsNil, check.Commentf(string(rules)))
+	c.Assert(string(rules), checker.Contains, ip+"/128")
+
+	out, err = s.d.Cmd("rm", "-f", "ipv6routed")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	rules, err = exec.Command("ip6tables", "-S", "DOCKER").CombinedOutput()
+	c.Assert(err, checker.IsNil, check.Commentf(string(rules)))
+	c.Assert(string(rules), checker.Not(checker.Contains), ip+"/128")
+}
+
+func (s *DockerDaemonSuite) TestDaemonIPv6PublishInvalid(c *check.C) {
+	c.Assert(s.d.Start("--ipv6-publish=bogus"), check.NotNil, check.Commentf("Daemon shouldn't start with an invalid IPv6 publishing mode"))
+	c.Assert(s.d.Start("--ipv6-publish=nat", "--iptables=false"), check.NotNil, check.Commentf("Daemon shouldn't publish on IPv6 without iptables"))
+}
+
+func (s *DockerDaemonSuite) TestDaemonAuditLog(c *check.C) {
+	dir, err := ioutil.TempDir("", "audit-log")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(dir)
+	auditLog := filepath.Join(dir, "audit.log")
+
+	c.Assert(s.d.StartWithBusybox("--audit-log="+auditLog, "--audit-log-opt=max-size=1m"), check.IsNil)
+
+	out, err := s.d.Cmd("run", "-d", "--name", "audited", "-e", "SECRET=hunter2", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	id := strings.TrimSpace(out)
+	out, err = s.d.Cmd("exec", "audited", "true")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	out, err = s.d.Cmd("ps")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	content, err := ioutil.ReadFile(auditLog)
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(content), checker.Not(checker.Contains), "hunter2")
+
+	var create, start, execStart, execEnd bool
+	dec := json.NewDecoder(bytes.NewReader(content))
+	for dec.More() {
+		var r struct {
+			Method      string
+			Route       string
+			ResourceIDs []string
+			Session     string
+			Stage       string
+			Status      int
+		}
+		c.Assert(dec.Decode(&r), checker.IsNil)
+		c.Assert(r.Method, checker.Not(checker.Equals), "GET", check.Commentf("%+v", r))
+		switch {
+		case r.Route == "/containers/create":
+			create = r.Status == 201 && len(r.ResourceIDs) == 1 && r.ResourceIDs[0] == id
+		case r.Route == "/containers/"+id+"/start":
+			start = r.Status == 204
+		case r.Session == "exec" && r.Stage == "start":
+			execStart = len(r.ResourceIDs) == 2 && r.ResourceIDs[1] == id
+		case r.Session == "exec" && r.Stage == "end":
+			execEnd = r.Status == 200
+		}
+	}
+	c.Assert(create, checker.True, check.Commentf("%s", content))
+	c.Assert(start, checker.True, check.Commentf("%s", content))
+	c.Assert(execStart, checker.True, check.Commentf("%s", content))
+	c.Assert(execEnd, checker.True, check.Commentf("%s", content))
+}
+
+func (s *DockerDaemonSuite) TestDaemonAuditLogInvalidOption(c *check.C) {
+	c.Assert(s.d.Start("--audit-log=/tmp/audit.log", "--audit-log-opt=bogus=1"), check.NotNil, check.Commentf("Daemon shouldn't start with an invalid audit log option"))
+}
+
 func (s *DockerDaemonSuite) TestDaemonLogLevelWrong(c *check.C) {
 	c.Assert(s.d.Start("--log-level=bogus"), check.NotNil, check.Commentf("Daemon shouldn't start with wrong log level"))
 }
@@ -2120,3 +2298,132 @@ func (s *DockerSuite) TestDaemonDiscoveryBackendConfigReload(c *check.C) {
 	c.Assert(out, checker.Contains, fmt.Sprintf("Cluster store: consul://consuladdr:consulport/some/path"))
 	c.Assert(out, checker.Contains, fmt.Sprintf("Cluster advertise: 192.168.56.100:0"))
 }
+
+func (s *DockerDaemonSuite) TestDaemonDefaultSeccompProfile(c *check.C) {
+	testRequires(c, SameHostDaemon, seccompEnabled)
+
+	profile := `{
+	"defaultAction": "SCMP_ACT_ALLOW",
+	"syscalls": [
+		{
+			"name": "chmod",
+			"action": "SCMP_ACT_ERRNO"
+		}
+	]
+}`
+	profilePath := filepath.Join(s.d.folder, "seccomp.json")
+	c.Assert(ioutil.WriteFile(profilePath, []byte(profile), 0644), checker.IsNil)
+
+	c.Assert(s.d.StartWithBusybox("--seccomp-profile="+profilePath), checker.IsNil)
+
+	out, err := s.d.Cmd("info")
+	c.Assert(err, checker.IsNil)
+	c.Assert(out, checker.Contains, "Seccomp Profile: "+profilePath)
+
+	out, err = s.d.Cmd("run", "--rm", "busybox", "chmod", "400", "/etc/hostname")
+	c.Assert(err, checker.NotNil, check.Commentf("expected chmod to be denied by the default seccomp profile: %s", out))
+	c.Assert(out, checker.Contains, "Operation not permitted")
+
+	// The profile of the container replaces the default one.
+	out, err = s.d.Cmd("run", "--rm", "--security-opt", "seccomp=unconfined", "busybox", "chmod", "400", "/etc/hostname")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+}
+
+func (s *DockerDaemonSuite) TestDaemonNoNewPrivileges(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	c.Assert(s.d.StartWithBusybox("--no-new-privileges"), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "--rm", "busybox", "grep", "NoNewPrivs", "/proc/self/status")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Matches, "NoNewPrivs:\\s+1\\s*")
+
+	out, err = s.d.Cmd("run", "--rm", "--security-opt", "no-new-privileges=false", "busybox", "grep", "NoNewPrivs", "/proc/self/status")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Matches, "NoNewPrivs:\\s+0\\s*")
+}
+
+func (s *DockerDaemonSuite) TestDaemonDefaultSeccompProfileInvalid(c *check.C) {
+	testRequires(c, SameHostDaemon, DaemonIsLinux)
+
+	profilePath := filepath.Join(s.d.folder, "seccomp.json")
+	c.Assert(ioutil.WriteFile(profilePath, []byte(`{"defaultAction": "SCMP_ACT_NONE"}`), 0644), checker.IsNil)
+
+	c.Assert(s.d.Start("--seccomp-profile="+profilePath), checker.NotNil)
+	content, _ := ioutil.ReadFile(s.d.logFile.Name())
+	c.Assert(string(content), checker.Contains, "Invalid default seccomp profile")
+}
+
+func (s *DockerDaemonSuite) TestDaemonDefaultSeccompProfileReload(c *check.C) {
+	testRequires(c, SameHostDaemon, seccompEnabled)
+
+	profilePath := filepath.Join(s.d.folder, "seccomp.json")
+	c.Assert(ioutil.WriteFile(profilePath, []byte(`{"defaultAction": "SCMP_ACT_ALLOW"}`), 0644), checker.IsNil)
+	configPath := filepath.Join(s.d.folder, "daemon.json")
+	c.Assert(ioutil.WriteFile(configPath, []byte(`{}`), 0644), checker.IsNil)
+
+	c.Assert(s.d.Start("--config-file="+configPath), checker.IsNil)
+
+	out, err := s.d.Cmd("info")
+	c.Assert(err, checker.IsNil)
+	c.Assert(out, checker.Contains, "Seccomp Profile: builtin")
+
+	reload := func(config string) {
+		c.Assert(ioutil.WriteFile(configPath, []byte(config), 0644), checker.IsNil)
+		c.Assert(syscall.Kill(s.d.cmd.Process.Pid, syscall.SIGHUP), checker.IsNil)
+		time.Sleep(3 * time.Second)
+	}
+
+	reload(fmt.Sprintf(`{"seccomp-profile": %q}`, profilePath))
+	out, err = s.d.Cmd("info")
+	c.Assert(err, checker.IsNil)
+	c.Assert(out, checker.Contains, "Seccomp Profile: "+profilePath)
+
+	// An invalid profile is rejected and the previous one is kept.
+	reload(fmt.Sprintf(`{"seccomp-profile": %q}`, filepath.Join(s.d.folder, "missing.json")))
+	out, err = s.d.Cmd("info")
+	c.Assert(err, checker.IsNil)
+	c.Assert(out, checker.Contains, "Seccomp Profile: "+profilePath)
+	content, _ := ioutil.ReadFile(s.d.logFile.Name())
+	c.Assert(string(content), checker.Contains, "Error reading the default seccomp profile")
+}
+
+func (s *DockerDaemonSuite) TestDaemonRestrictedHost(c *check.C) {
+	testRequires(c, SameHostDaemon, DaemonIsLinux)
+
+	sock := "unix://" + filepath.Join(s.d.folder, "restricted.sock")
+	c.Assert(s.d.StartWithBusybox("--restricted-host", sock), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "-d", "--name", "top", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+
+	out, _, err = runCommandWithOutput(exec.Command(dockerBinary, "--host", sock, "ps"))
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "top")
+
+	out, _, err = runCommandWithOutput(exec.Command(dockerBinary, "--host", sock, "inspect", "--format", "{{.State.Running}}", "top"))
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "true")
+
+	out, _, err = runCommandWithOutput(exec.Command(dockerBinary, "--host", sock, "exec", "top", "true"))
+	c.Assert(err, checker.NotNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "is not allowed on this listener")
+
+	out, _, err = runCommandWithOutput(exec.Command(dockerBinary, "--host", sock, "create", "busybox"))
+	c.Assert(err, checker.NotNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "is not allowed on this listener")
+}
+
+func (s *DockerDaemonSuite) TestDaemonRestrictedHostRoutes(c *check.C) {
+	testRequires(c, SameHostDaemon, DaemonIsLinux)
+
+	sock := "unix://" + filepath.Join(s.d.folder, "restricted.sock")
+	c.Assert(s.d.Start("--restricted-host", sock, "--restricted-route", "GET /_ping", "--restricted-route", "GET /version"), checker.IsNil)
+
+	out, _, err := runCommandWithOutput(exec.Command(dockerBinary, "--host", sock, "version"))
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+
+	out, _, err = runCommandWithOutput(exec.Command(dockerBinary, "--host", sock, "ps"))
+	c.Assert(err, checker.NotNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "is not allowed on this listener")
+}
@@ -10,12 +10,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/pkg/integration/checker"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/network"
 	"github.com/docker/engine-api/types/versions/v1p20"
 	"github.com/docker/libnetwork/driverapi"
 	remoteapi "github.com/docker/libnetwork/drivers/remote/api"
@@ -333,6 +335,46 @@ func (s *DockerNetworkSuite) TestDockerNetworkLsFilter(c *check.C) {
 	assertNwList(c, out, []string{"bridge", "dev", "host", "none"})
 }
 
+func (s *DockerNetworkSuite) TestDockerNetworkLsFilterLabel(c *check.C) {
+	dockerCmd(c, "network", "create", "--label", "team=web", "--label", "env=prod", "webprod")
+	dockerCmd(c, "network", "create", "--label", "team=web", "--label", "env=dev", "webdev")
+	dockerCmd(c, "network", "create", "--label", "team=db", "dbnet")
+	defer func() {
+		dockerCmd(c, "network", "rm", "webprod", "webdev", "dbnet")
+	}()
+
+	out, _ := dockerCmd(c, "network", "ls", "-f", "label=team")
+	assertNwList(c, out, []string{"dbnet", "webdev", "webprod"})
+
+	out, _ = dockerCmd(c, "network", "ls", "-f", "label=team=web")
+	assertNwList(c, out, []string{"webdev", "webprod"})
+
+	// all labels must match
+	out, _ = dockerCmd(c, "network", "ls", "-f", "label=team=web", "-f", "label=env=prod")
+	assertNwList(c, out, []string{"webprod"})
+
+	// labels narrow down the other filters
+	out, _ = dockerCmd(c, "network", "ls", "-f", "label=team=db", "-f", "type=custom")
+	assertNwList(c, out, []string{"dbnet"})
+
+	out, _ = dockerCmd(c, "network", "ls", "-f", "label=nonexistent")
+	c.Assert(strings.Split(strings.TrimSpace(out), "\n"), checker.HasLen, 1, check.Commentf("expected no networks: %s", out))
+}
+
+func (s *DockerNetworkSuite) TestDockerNetworkCreateLabel(c *check.C) {
+	dockerCmd(c, "network", "create", "--label", "team=web", "--label", "internal", "labelnet")
+	assertNwIsAvailable(c, "labelnet")
+
+	nr := getNwResource(c, "labelnet")
+	c.Assert(nr.Labels, checker.DeepEquals, map[string]string{"team": "web", "internal": ""})
+
+	out, _ := dockerCmd(c, "network", "inspect", "--format={{.Labels.team}}", "labelnet")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "web")
+
+	dockerCmd(c, "network", "rm", "labelnet")
+	assertNwNotAvailable(c, "labelnet")
+}
+
 func (s *DockerNetworkSuite) TestDockerNetworkCreateDelete(c *check.C) {
 	dockerCmd(c, "network", "create", "test")
 	assertNwIsAvailable(c, "test")
@@ -1375,6 +1417,49 @@ func (s *DockerSuite) TestUserDefinedNetworkConnectDisconnectAlias(c *check.C) {
 	c.Assert(out, checker.Contains, runconfig.ErrUnsupportedNetworkAndAlias.Error())
 }
 
+func (s *DockerSuite) TestUserDefinedNetworkDNSRRName(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace, NotArm)
+	dockerCmd(c, "network", "create", "-d", "bridge", "rrnet")
+
+	dockerCmd(c, "run", "-d", "--net=rrnet", "--name=web1", "--dns-rr-name=web", "busybox", "top")
+	c.Assert(waitRun("web1"), check.IsNil)
+	dockerCmd(c, "run", "-d", "--name=web2", "busybox", "top")
+	c.Assert(waitRun("web2"), check.IsNil)
+	dockerCmd(c, "network", "connect", "--dns-rr-name=web", "rrnet", "web2")
+	dockerCmd(c, "run", "-d", "--net=rrnet", "--name=client", "busybox", "top")
+	c.Assert(waitRun("client"), check.IsNil)
+
+	c.Assert(inspectField(c, "web1", "NetworkSettings.Networks.rrnet.DNSRRName"), checker.Equals, "web")
+	ip1 := inspectField(c, "web1", "NetworkSettings.Networks.rrnet.IPAddress")
+	ip2 := inspectField(c, "web2", "NetworkSettings.Networks.rrnet.IPAddress")
+
+	// both the round-robin name and its tasks list resolve to every backend
+	for _, name := range []string{"web", "tasks.web", "web.rrnet", "tasks.web.rrnet"} {
+		out, _ := dockerCmd(c, "exec", "client", "nslookup", name)
+		c.Assert(out, checker.Contains, ip1, check.Commentf("resolving %s", name))
+		c.Assert(out, checker.Contains, ip2, check.Commentf("resolving %s", name))
+	}
+
+	// a stopped backend is not returned anymore
+	dockerCmd(c, "stop", "web1")
+	out, _ := dockerCmd(c, "exec", "client", "nslookup", "web")
+	c.Assert(out, checker.Not(checker.Contains), ip1)
+	c.Assert(out, checker.Contains, ip2)
+
+	// and it is again once restarted
+	dockerCmd(c, "start", "web1")
+	c.Assert(waitRun("web1"), check.IsNil)
+	ip1 = inspectField(c, "web1", "NetworkSettings.Networks.rrnet.IPAddress")
+	out, _ = dockerCmd(c, "exec", "client", "nslookup", "tasks.web")
+	c.Assert(out, checker.Contains, ip1)
+	c.Assert(out, checker.Contains, ip2)
+
+	// the round-robin name is rejected on predefined networks
+	out, _, err := dockerCmdWithError("run", "--rm", "--dns-rr-name=any", "busybox", "true")
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+	c.Assert(out, checker.Contains, runconfig.ErrUnsupportedNetworkAndDNSRRName.Error())
+}
+
 func (s *DockerSuite) TestUserDefinedNetworkConnectivity(c *check.C) {
 	testRequires(c, DaemonIsLinux, NotUserNamespace)
 	dockerCmd(c, "network", "create", "-d", "bridge", "br.net1")
@@ -1436,3 +1521,86 @@ func (s *DockerSuite) TestDockerNetworkInternalMode(c *check.C) {
 	_, _, err = dockerCmdWithError("exec", "second", "ping", "-c", "1", "first")
 	c.Assert(err, check.IsNil)
 }
+
+func (s *DockerSuite) TestDockerNetworkEgressPolicy(c *check.C) {
+	testRequires(c, SameHostDaemon, NotUserNamespace)
+	dockerCmd(c, "network", "create", "--egress-default=deny",
+		"--egress-rule=allow:8.8.8.8/32:53/udp", "--egress-rule=deny:10.0.0.0/8:22", "--egress-rule=allow:10.0.0.0/8", "egressnet")
+	assertNwIsAvailable(c, "egressnet")
+
+	nr := getNetworkResource(c, "egressnet")
+	c.Assert(nr.EgressPolicy, checker.NotNil)
+	c.Assert(*nr.EgressPolicy, checker.DeepEquals, network.EgressPolicy{
+		Default: "deny",
+		Rules: []network.EgressRule{
+			{Action: "allow", CIDR: "8.8.8.8/32", Protocol: "udp", Port: 53},
+			{Action: "deny", CIDR: "10.0.0.0/8", Protocol: "tcp", Port: 22},
+			{Action: "allow", CIDR: "10.0.0.0/8"},
+		},
+	})
+
+	chain := "DOCKER-EGRESS-" + nr.ID[:12]
+	out, _, err := runCommandWithOutput(exec.Command("iptables", "-S", chain))
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	rules := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(rules, checker.HasLen, 6, check.Commentf(out))
+	c.Assert(rules[2], checker.Contains, "-d 8.8.8.8/32")
+	c.Assert(rules[2], checker.Contains, "--dport 53 -j RETURN")
+	c.Assert(rules[3], checker.Contains, "--dport 22 -j DROP")
+	c.Assert(rules[4], checker.Equals, "-A "+chain+" -d 10.0.0.0/8 -j RETURN")
+	c.Assert(rules[5], checker.Equals, "-A "+chain+" -j DROP")
+
+	dockerCmd(c, "network", "rm", "egressnet")
+	out, _, err = runCommandWithOutput(exec.Command("iptables", "-S", chain))
+	c.Assert(err, check.NotNil, check.Commentf("egress chain should be removed with the network: %s", out))
+}
+
+func (s *DockerSuite) TestDockerNetworkEgressPolicyInvalid(c *check.C) {
+	out, _, err := dockerCmdWithError("network", "create", "--egress-rule=reject:10.0.0.0/8", "badegress")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "invalid egress action")
+
+	out, _, err = dockerCmdWithError("network", "create", "--egress-rule=allow:10.0.0.0/8:icmp", "--egress-rule=allow:fd00::/64", "badegress")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "only IPv4 destinations are supported")
+
+	out, _, err = dockerCmdWithError("network", "create", "--internal", "--egress-default=deny", "badegress")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "can not be set on an internal network")
+
+	out, _, err = dockerCmdWithError("network", "create", "--ipv6", "--subnet=fd01::/64", "--egress-default=deny", "badegress")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "can not be set on a network with IPv6 enabled")
+
+	assertNwNotAvailable(c, "badegress")
+}
+
+func (s *DockerNetworkSuite) TestDockerNetworkConnectBandwidth(c *check.C) {
+	testRequires(c, SameHostDaemon, NotUserNamespace)
+	dockerCmd(c, "network", "create", "ratenet")
+	dockerCmd(c, "run", "-d", "--name=limited", "busybox", "top")
+	c.Assert(waitRun("limited"), check.IsNil)
+
+	dockerCmd(c, "network", "connect", "--ingress-rate=1m", "--egress-rate=512k", "ratenet", "limited")
+	c.Assert(inspectField(c, "limited", "NetworkSettings.Networks.ratenet.IngressRate"), checker.Equals, "1048576")
+	c.Assert(inspectField(c, "limited", "NetworkSettings.Networks.ratenet.EgressRate"), checker.Equals, "524288")
+
+	// Connecting again updates the limits of the running container
+	dockerCmd(c, "network", "connect", "--egress-rate=2m", "ratenet", "limited")
+	c.Assert(inspectField(c, "limited", "NetworkSettings.Networks.ratenet.IngressRate"), checker.Equals, "0")
+	c.Assert(inspectField(c, "limited", "NetworkSettings.Networks.ratenet.EgressRate"), checker.Equals, "2097152")
+
+	// Other settings cannot be changed while connected
+	out, _, err := dockerCmdWithError("network", "connect", "--alias=foo", "--egress-rate=1m", "ratenet", "limited")
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+	c.Assert(out, checker.Contains, "only its bandwidth limits can be updated")
+
+	// The limits are applied again when the container restarts
+	dockerCmd(c, "restart", "limited")
+	c.Assert(waitRun("limited"), check.IsNil)
+	c.Assert(inspectField(c, "limited", "NetworkSettings.Networks.ratenet.EgressRate"), checker.Equals, "2097152")
+
+	out, _, err = dockerCmdWithError("network", "connect", "--egress-rate=fast", "ratenet", "limited")
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+	c.Assert(out, checker.Contains, "invalid rate")
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/docker/docker/runconfig"
 	"github.com/go-check/check"
 )
 
@@ -317,3 +318,85 @@ func (s *DockerSuite) TestPortBindingOnSandbox(c *check.C) {
 		check.Commentf("Port mapping on the new network is expected to succeed"))
 
 }
+
+func (s *DockerSuite) TestPortAddRm(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace)
+	out, _ := dockerCmd(c, "run", "-d", "--name", "test", "busybox", "nc", "-l", "-p", "80")
+	id := strings.TrimSpace(out)
+
+	dockerCmd(c, "port", "add", "test", "9877:80")
+
+	out, _ = dockerCmd(c, "port", "test", "80")
+	err := assertPortList(c, out, []string{"0.0.0.0:9877"})
+	// Port list is not correct
+	c.Assert(err, checker.IsNil)
+
+	// The new binding is reachable without restarting the container
+	dockerCmd(c, "run", "--net=host", "busybox", "nc", "localhost", "9877")
+
+	bindings := inspectFieldJSON(c, id, "HostConfig.PortBindings")
+	c.Assert(bindings, checker.Contains, "9877")
+
+	out, _, err = dockerCmdWithError("port", "add", "test", "9877:80")
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+	c.Assert(out, checker.Contains, "already published")
+
+	dockerCmd(c, "port", "rm", "test", "9877:80")
+
+	out, _, err = dockerCmdWithError("port", "test", "80")
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+
+	out, _, err = dockerCmdWithError("run", "--net=host", "busybox", "nc", "localhost", "9877")
+	// Port is still bound after it was unpublished
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+
+	out, _, err = dockerCmdWithError("port", "rm", "test", "80")
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+	c.Assert(out, checker.Contains, "No public port")
+
+	// The subcommands report their usage instead of looking up a container
+	for _, sub := range []string{"add", "rm"} {
+		out, _, err = dockerCmdWithError("port", sub, "test")
+		c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+		c.Assert(out, checker.Contains, "requires a minimum of 2 arguments")
+	}
+}
+
+func (s *DockerSuite) TestPortAddRmKeepsOtherBindings(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace)
+	dockerCmd(c, "run", "-d", "--name", "test", "-p", "80", "busybox", "top")
+	ephemeral, _ := dockerCmd(c, "port", "test", "80")
+
+	// Publishing and unpublishing another port doesn't reallocate the
+	// ephemeral host port of the existing binding
+	dockerCmd(c, "port", "add", "test", "9880:81")
+	out, _ := dockerCmd(c, "port", "test", "80")
+	c.Assert(out, checker.Equals, ephemeral)
+
+	dockerCmd(c, "port", "rm", "test", "9880:81")
+	out, _ = dockerCmd(c, "port", "test", "80")
+	c.Assert(out, checker.Equals, ephemeral)
+}
+
+func (s *DockerSuite) TestPortAddPersistsAcrossRestart(c *check.C) {
+	testRequires(c, DaemonIsLinux, NotUserNamespace)
+	dockerCmd(c, "run", "-d", "--name", "test", "busybox", "top")
+
+	dockerCmd(c, "port", "add", "test", "9878:80/udp")
+	dockerCmd(c, "restart", "test")
+
+	out, _ := dockerCmd(c, "port", "test", "80/udp")
+	err := assertPortList(c, out, []string{"0.0.0.0:9878"})
+	// Port list is not correct
+	c.Assert(err, checker.IsNil)
+}
+
+func (s *DockerSuite) TestPortAddContainerNetwork(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "run", "-d", "--name", "parent", "busybox", "top")
+	dockerCmd(c, "run", "-d", "--name", "child", "--net=container:parent", "busybox", "top")
+
+	out, _, err := dockerCmdWithError("port", "add", "child", "9879:80")
+	c.Assert(err, checker.NotNil, check.Commentf("out: %s", out))
+	c.Assert(out, checker.Contains, runconfig.ErrConflictNetworkPublishPorts.Error())
+}
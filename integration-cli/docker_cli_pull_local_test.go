@@ -360,6 +360,35 @@ func (s *DockerRegistrySuite) TestPullManifestList(c *check.C) {
 	dockerCmd(c, "inspect", repoName)
 
 	dockerCmd(c, "rmi", repoName)
+
+	// Selecting a platform that is not in the manifest list fails.
+	out, _, err = dockerCmdWithError("pull", "--platform=other_os/other_arch", repoName)
+	c.Assert(err, checker.NotNil, check.Commentf("pulling a platform missing from the manifest list should fail"))
+	c.Assert(out, checker.Contains, "no matching manifest for other_os/other_arch found in manifest list")
+
+	// Selecting the current platform explicitly pulls the same image.
+	out, _ = dockerCmd(c, "pull", "--platform="+runtime.GOOS+"/"+runtime.GOARCH, repoName)
+	matches = digestRegex.FindStringSubmatch(out)
+	c.Assert(matches, checker.HasLen, 2, check.Commentf("unable to parse digest from pull output: %s", out))
+	c.Assert(matches[1], checker.Equals, manifestListDigest.String())
+
+	dockerCmd(c, "rmi", repoName)
+}
+
+// TestPullPlatformMismatch checks that pulling a single-platform image
+// for a different platform is refused.
+func (s *DockerRegistrySuite) TestPullPlatformMismatch(c *check.C) {
+	testRequires(c, NotArm)
+	_, err := setupImage(c)
+	c.Assert(err, checker.IsNil, check.Commentf("error setting up image"))
+
+	out, _, err := dockerCmdWithError("pull", "--platform=other_os/other_arch", repoName)
+	c.Assert(err, checker.NotNil, check.Commentf("pulling an image for another platform should fail"))
+	c.Assert(out, checker.Contains, "not other_os/other_arch")
+
+	out, _, err = dockerCmdWithError("pull", "--platform=linux", repoName)
+	c.Assert(err, checker.NotNil, check.Commentf("pulling with an invalid platform should fail"))
+	c.Assert(out, checker.Contains, "invalid platform")
 }
 
 func (s *DockerRegistryAuthSuite) TestPullWithExternalAuth(c *check.C) {
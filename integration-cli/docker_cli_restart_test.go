@@ -43,6 +43,34 @@ func (s *DockerSuite) TestRestartRunningContainer(c *check.C) {
 	c.Assert(out, checker.Equals, "foobar\nfoobar\n")
 }
 
+// Test that stop and restart use the stop timeout of the container, unless
+// a timeout is given.
+func (s *DockerSuite) TestRestartStopTimeout(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	// The shell ignores SIGTERM, so the container only stops when it is killed
+	// at the end of the stop timeout.
+	out, _ := dockerCmd(c, "run", "-d", "--stop-timeout=1", "busybox", "sh", "-c", "trap '' TERM; while true; do sleep 1; done")
+	id := strings.TrimSpace(out)
+	c.Assert(waitRun(id), checker.IsNil)
+
+	start := time.Now()
+	dockerCmd(c, "stop", id)
+	c.Assert(time.Since(start).Seconds(), checker.LessThan, 8.0, check.Commentf("docker stop should use the stop timeout of the container"))
+
+	dockerCmd(c, "start", id)
+	c.Assert(waitRun(id), checker.IsNil)
+
+	start = time.Now()
+	dockerCmd(c, "restart", id)
+	c.Assert(time.Since(start).Seconds(), checker.LessThan, 8.0, check.Commentf("docker restart should use the stop timeout of the container"))
+	c.Assert(waitRun(id), checker.IsNil)
+
+	// An explicit timeout overrides the stop timeout of the container.
+	start = time.Now()
+	dockerCmd(c, "stop", "-t", "3", id)
+	c.Assert(time.Since(start).Seconds(), checker.GreaterOrEqualThan, 3.0)
+}
+
 // Test that restarting a container with a volume does not create a new volume on restart. Regression test for #819.
 func (s *DockerSuite) TestRestartWithVolumes(c *check.C) {
 	prefix, slash := getPrefixAndSlashFromDaemonPlatform()
@@ -360,3 +360,40 @@ func (s *DockerSuite) TestRmiByIDHardConflict(c *check.C) {
 	imgID2 := inspectField(c, "busybox:latest", "Id")
 	c.Assert(imgID, checker.Equals, imgID2)
 }
+
+func (s *DockerRegistrySuite) TestRmiRemote(c *check.C) {
+	pushDigest, err := setupImage(c)
+	c.Assert(err, checker.IsNil, check.Commentf("error setting up image"))
+
+	// push a second tag pointing to the same manifest
+	otherTag := repoName + ":other"
+	dockerCmd(c, "pull", repoName)
+	dockerCmd(c, "tag", repoName, otherTag)
+	dockerCmd(c, "push", otherTag)
+	dockerCmd(c, "rmi", repoName, otherTag)
+
+	// a dry run lists every tag of the manifest and deletes nothing
+	out, _ := dockerCmd(c, "rmi", "--remote", "--dry-run", repoName)
+	c.Assert(out, checker.Contains, "Would untag: "+repoName+":latest")
+	c.Assert(out, checker.Contains, "Would untag: "+otherTag)
+	c.Assert(out, checker.Contains, "Would delete: "+pushDigest.String())
+	dockerCmd(c, "pull", repoName)
+	dockerCmd(c, "rmi", repoName)
+
+	out, _ = dockerCmd(c, "rmi", "--remote", otherTag)
+	c.Assert(out, checker.Contains, "Untagged: "+repoName+":latest")
+	c.Assert(out, checker.Contains, "Untagged: "+otherTag)
+	c.Assert(out, checker.Contains, "Deleted: "+pushDigest.String())
+
+	_, _, err = dockerCmdWithError("pull", repoName)
+	c.Assert(err, checker.NotNil, check.Commentf("the deleted manifest should not be pullable by tag"))
+	_, _, err = dockerCmdWithError("pull", repoName+"@"+pushDigest.String())
+	c.Assert(err, checker.NotNil, check.Commentf("the deleted manifest should not be pullable by digest"))
+}
+
+func (s *DockerSuite) TestRmiDryRunRequiresRemote(c *check.C) {
+	out, _, err := dockerCmdWithError("rmi", "--dry-run", "busybox")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "--dry-run can only be used with --remote")
+	dockerCmd(c, "inspect", "busybox")
+}
@@ -374,6 +374,26 @@ func (s *DockerSuite) TestRunWithCPUQuota(c *check.C) {
 	c.Assert(out, checker.Equals, "8000", check.Commentf("setting the CPU CFS quota failed"))
 }
 
+func (s *DockerSuite) TestRunWithCPUs(c *check.C) {
+	testRequires(c, cpuCfsQuota, cpuCfsPeriod)
+
+	file1 := "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	file2 := "/sys/fs/cgroup/cpu/cpu.cfs_period_us"
+	out, _ := dockerCmd(c, "run", "--cpus", "0.5", "--name", "test", "busybox", "cat", file1, file2)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "50000\n100000")
+
+	out = inspectField(c, "test", "HostConfig.NanoCPUs")
+	c.Assert(out, checker.Equals, "500000000", check.Commentf("setting the CPUs failed"))
+
+	out, _, err := dockerCmdWithError("run", "--cpus", "0.5", "--cpu-quota", "50000", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Conflicting options: CPUs and CPU period or quota cannot both be set")
+
+	out, _, err = dockerCmdWithError("run", "--cpus", "100000", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Range of CPUs is from 0.01 to")
+}
+
 func (s *DockerSuite) TestRunWithCpuPeriod(c *check.C) {
 	testRequires(c, cpuCfsPeriod)
 
@@ -709,6 +729,99 @@ func (s *DockerSuite) TestRunTmpfsMounts(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestRunMountBind(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	// a path with a colon can't be expressed with -v
+	tmpDir, err := ioutil.TempDir("", "mount:bind")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+	c.Assert(ioutil.WriteFile(filepath.Join(tmpDir, "file"), []byte("hello"), 0644), checker.IsNil)
+
+	out, _ := dockerCmd(c, "run", "--mount", "type=bind,source="+tmpDir+",target=/foo", "busybox", "cat", "/foo/file")
+	c.Assert(out, checker.Equals, "hello")
+
+	out, _, err = dockerCmdWithError("run", "--mount", "type=bind,source="+tmpDir+",target=/foo,readonly", "busybox", "touch", "/foo/other")
+	c.Assert(err, checker.NotNil, check.Commentf("a readonly bind mount should not be writable"))
+	c.Assert(out, checker.Contains, "Read-only file system")
+
+	out, _, err = dockerCmdWithError("run", "--mount", "type=bind,source="+filepath.Join(tmpDir, "missing")+",target=/foo", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "bind source path does not exist")
+
+	out, _, err = dockerCmdWithError("run", "--mount", "type=bind,source="+tmpDir+",target=/foo", "-v", tmpDir+"/:/foo", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Duplicate mount point")
+}
+
+func (s *DockerSuite) TestRunMountVolume(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	dockerCmd(c, "run", "--mount", "type=volume,source=mountvol,target=/data,volume-driver=local", "busybox", "sh", "-c", "echo hello > /data/file")
+
+	out, _ := dockerCmd(c, "volume", "inspect", "--format={{.Driver}}", "mountvol")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "local")
+
+	out, _ = dockerCmd(c, "run", "--name", "mountvoltest", "--mount", "type=volume,source=mountvol,target=/data,readonly", "busybox", "cat", "/data/file")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "hello")
+
+	out, _ = dockerCmd(c, "inspect", "--format={{range .Mounts}}{{.Name}} {{.Destination}} {{.RW}}{{end}}", "mountvoltest")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "mountvol /data false")
+}
+
+func (s *DockerSuite) TestRunMountTmpfs(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	out, _ := dockerCmd(c, "run", "--mount", "type=tmpfs,target=/scratch,tmpfs-size=1m,tmpfs-mode=1770", "busybox", "sh", "-c", "stat -c %a /scratch && grep /scratch /proc/mounts")
+	c.Assert(out, checker.Contains, "1770")
+	c.Assert(out, checker.Contains, "size=1024k")
+	c.Assert(out, checker.Contains, "noexec")
+
+	_, _, err := dockerCmdWithError("run", "--mount", "type=tmpfs,target=/scratch,readonly", "busybox", "touch", "/scratch/file")
+	c.Assert(err, checker.NotNil, check.Commentf("a readonly tmpfs should not be writable"))
+
+	out, _, err = dockerCmdWithError("run", "--mount", "type=tmpfs,target=/scratch,volume-driver=local", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "cannot mix 'volume-*' options with mount type 'tmpfs'")
+}
+
+func (s *DockerSuite) TestRunInit(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	out, _ := dockerCmd(c, "run", "--init", "busybox", "sh", "-c", "cat /proc/1/comm && echo $$")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(lines, checker.HasLen, 2)
+	c.Assert(lines[0], checker.Equals, "init")
+	c.Assert(lines[1], checker.Not(checker.Equals), "1", check.Commentf("the command should not run as PID 1"))
+
+	out, _ = dockerCmd(c, "run", "busybox", "cat", "/proc/1/comm")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "cat")
+
+	// The exit status of the command is the exit status of the container.
+	_, exitCode, err := dockerCmdWithError("run", "--init", "busybox", "sh", "-c", "exit 3")
+	c.Assert(err, checker.NotNil)
+	c.Assert(exitCode, checker.Equals, 3)
+
+	// Orphaned processes are reaped instead of being left as zombies.
+	out, _ = dockerCmd(c, "run", "--init", "busybox", "sh", "-c", "sh -c 'sleep 1 &' && sleep 2 && ps -o stat | grep -c Z || true")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "0")
+}
+
+func (s *DockerSuite) TestRunInitForwardsSignals(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	out, _ := dockerCmd(c, "run", "-d", "--init", "busybox", "sh", "-c", "trap 'exit 42' TERM; while true; do sleep 1; done")
+	id := strings.TrimSpace(out)
+	c.Assert(waitRun(id), checker.IsNil)
+
+	dockerCmd(c, "kill", "-s", "TERM", id)
+	out, _ = dockerCmd(c, "wait", id)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "42")
+
+	initSet := inspectField(c, id, "HostConfig.Init")
+	c.Assert(initSet, checker.Equals, "true")
+}
+
 // TestRunSeccompProfileDenyUnshare checks that 'docker run --security-opt seccomp:/tmp/profile.json debian:jessie unshare' exits with operation not permitted.
 func (s *DockerSuite) TestRunSeccompProfileDenyUnshare(c *check.C) {
 	testRequires(c, SameHostDaemon, seccompEnabled, NotArm, Apparmor)
@@ -907,6 +1020,26 @@ func (s *DockerSuite) TestRunNoNewPrivSetuid(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestRunCapAmbient(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, ambientCapabilities)
+
+	// NET_BIND_SERVICE is capability 10
+	out, _ := dockerCmd(c, "run", "--user", "nobody", "--cap-ambient", "NET_BIND_SERVICE", "busybox", "grep", "CapEff", "/proc/self/status")
+	c.Assert(out, checker.Contains, "0000000000000400")
+
+	out, _, err := dockerCmdWithError("run", "--user", "nobody", "--cap-drop", "NET_BIND_SERVICE", "--cap-ambient", "NET_BIND_SERVICE", "busybox", "true")
+	c.Assert(err, checker.NotNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "not one of the capabilities of the container")
+}
+
+func (s *DockerSuite) TestRunCapAmbientUnknown(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, ambientCapabilities)
+
+	out, _, err := dockerCmdWithError("create", "--cap-ambient", "NOT_A_CAP", "busybox")
+	c.Assert(err, checker.NotNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "Unknown ambient capability")
+}
+
 func (s *DockerSuite) TestRunApparmorProcDirectory(c *check.C) {
 	testRequires(c, SameHostDaemon, Apparmor)
 
@@ -932,6 +1065,44 @@ func (s *DockerSuite) TestRunSeccompWithDefaultProfile(c *check.C) {
 	c.Assert(strings.TrimSpace(out), checker.Equals, "unshare: unshare failed: Operation not permitted")
 }
 
+// TestRunSeccompLearn checks that a container running with
+// '--security-opt seccomp=learn' stores a profile allowing the syscalls it
+// made, which runs the same command, and that 'seccomp=learn:<output>' also
+// writes it to the daemon root.
+func (s *DockerSuite) TestRunSeccompLearn(c *check.C) {
+	testRequires(c, SameHostDaemon, seccompEnabled, seccompLogAction)
+	tmpDir, err := ioutil.TempDir("", "seccomp-learn")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+	profile := filepath.Join(tmpDir, "profile.json")
+
+	dockerCmd(c, "run", "--name", "learner", "--security-opt", "seccomp=learn", "busybox", "sh", "-c", "ls / && chmod 400 /etc/hostname")
+
+	b := inspectFieldJSON(c, "learner", "LearnedSeccompProfile.Profile")
+	c.Assert(b, checker.Contains, `"defaultAction":"SCMP_ACT_ERRNO"`)
+	c.Assert(b, checker.Contains, `"name":"execve"`)
+	c.Assert(b, checker.Contains, `"name":"chmod"`)
+	c.Assert(b, checker.Not(checker.Contains), `"name":"unshare"`)
+	c.Assert(ioutil.WriteFile(profile, []byte(b), 0644), checker.IsNil)
+
+	dockerCmd(c, "run", "--rm", "--security-opt", "seccomp:"+profile, "busybox", "sh", "-c", "ls / && chmod 400 /etc/hostname")
+	out, _, err := dockerCmdWithError("run", "--rm", "--security-opt", "seccomp:"+profile, "busybox", "unshare", "-n", "true")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Operation not permitted")
+
+	dockerCmd(c, "run", "--rm", "--security-opt", "seccomp=learn:learner.json", "busybox", "chmod", "400", "/etc/hostname")
+	output := filepath.Join(dockerBasePath, "seccomp", "learner.json")
+	defer os.Remove(output)
+	b2, err := ioutil.ReadFile(output)
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(b2), checker.Contains, `"name": "chmod"`)
+
+	// the profile can't be written to a path of the daemon host
+	out, _, err = dockerCmdWithError("run", "--rm", "--security-opt", "seccomp=learn:/etc/profile.json", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "it must be a file name")
+}
+
 // TestRunDeviceSymlink checks run with device that follows symlink (#13840)
 func (s *DockerSuite) TestRunDeviceSymlink(c *check.C) {
 	testRequires(c, DaemonIsLinux, NotUserNamespace, NotArm, SameHostDaemon)
@@ -311,3 +311,58 @@ func (s *DockerSuite) TestLoadZeroSizeLayer(c *check.C) {
 
 	dockerCmd(c, "load", "-i", "fixtures/load/emptyLayer.tar")
 }
+
+func (s *DockerSuite) TestSaveExcludeBaseAndLoad(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	baseName := "foobar-save-exclude-base"
+	_, err := buildImage(baseName, "FROM busybox\nRUN echo base > /base", true)
+	c.Assert(err, checker.IsNil)
+
+	repoName := "foobar-save-exclude-base-child"
+	_, err = buildImage(repoName, fmt.Sprintf("FROM %s\nRUN echo child > /child", baseName), true)
+	c.Assert(err, checker.IsNil)
+
+	layers := func(name string, flags ...string) int {
+		args := append([]string{"save"}, flags...)
+		out, _, err := runCommandPipelineWithOutput(
+			exec.Command(dockerBinary, append(args, name)...),
+			exec.Command("tar", "t"),
+			exec.Command("grep", "layer.tar"))
+		c.Assert(err, checker.IsNil, check.Commentf("failed to save repo: %s, %v", out, err))
+		return len(strings.Split(strings.TrimSpace(out), "\n"))
+	}
+	c.Assert(layers(repoName), checker.GreaterThan, 2)
+	c.Assert(layers(repoName, "--exclude-base", baseName), checker.Equals, 1, check.Commentf("expected only the child layer in the archive"))
+
+	before, _ := dockerCmd(c, "inspect", repoName)
+	out, _, err := runCommandPipelineWithOutput(
+		exec.Command(dockerBinary, "save", "--exclude-base", baseName, repoName),
+		exec.Command(dockerBinary, "load"))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to save and load repo: %s, %v", out, err))
+
+	after, _ := dockerCmd(c, "inspect", repoName)
+	c.Assert(before, checker.Equals, after, check.Commentf("inspect is not the same after a save / load"))
+}
+
+func (s *DockerSuite) TestLoadExcludedBaseMissing(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	baseName := "foobar-load-excluded-base"
+	_, err := buildImage(baseName, "FROM busybox\nRUN echo base > /base", true)
+	c.Assert(err, checker.IsNil)
+
+	repoName := "foobar-load-excluded-base-child"
+	_, err = buildImage(repoName, fmt.Sprintf("FROM %s\nRUN echo child > /child", baseName), true)
+	c.Assert(err, checker.IsNil)
+
+	tmpDir, err := ioutil.TempDir("", "save-exclude-base")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+	tarFile := filepath.Join(tmpDir, "child.tar")
+
+	dockerCmd(c, "save", "-o", tarFile, "--exclude-base", baseName, repoName)
+	deleteImages(repoName, baseName)
+
+	out, _, err := dockerCmdWithError("load", "-i", tarFile)
+	c.Assert(err, checker.NotNil, check.Commentf("load should fail without the base layers: %s", out))
+	c.Assert(out, checker.Contains, "was excluded from the archive")
+}
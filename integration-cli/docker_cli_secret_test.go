@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestSecretCliCreateLsRm(c *check.C) {
+	createCmd := exec.Command(dockerBinary, "secret", "create", "--label", "env=test", "db_password", "-")
+	createCmd.Stdin = strings.NewReader("correct horse battery")
+	out, _, err := runCommandWithOutput(createCmd)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "db_password")
+
+	out, _, err = runCommandWithOutput(exec.Command(dockerBinary, "secret", "create", "db_password", "/etc/hostname"))
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "already exists")
+
+	out, _, err = dockerCmdWithError("secret", "create", "invalid/name", "/etc/hostname")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "invalid secret name")
+
+	out, _ = dockerCmd(c, "secret", "ls")
+	c.Assert(out, checker.Contains, "db_password")
+	c.Assert(out, checker.Not(checker.Contains), "correct horse")
+	out, _ = dockerCmd(c, "secret", "ls", "-q")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "db_password")
+
+	dockerCmd(c, "secret", "rm", "db_password")
+	out, _ = dockerCmd(c, "secret", "ls", "-q")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "")
+
+	out, _, err = dockerCmdWithError("secret", "rm", "db_password")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "no such secret")
+}
+
+func (s *DockerSuite) TestSecretRunMounted(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	createCmd := exec.Command(dockerBinary, "secret", "create", "db_password")
+	createCmd.Stdin = strings.NewReader("correct horse battery")
+	out, _, err := runCommandWithOutput(createCmd)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, _ = dockerCmd(c, "run", "--secret", "db_password", "busybox", "cat", "/run/secrets/db_password")
+	c.Assert(out, checker.Equals, "correct horse battery")
+
+	out, _ = dockerCmd(c, "run", "--name", "test", "--secret", "db_password,target=/etc/db/password,mode=0400,uid=1000", "busybox",
+		"sh", "-c", "stat -c '%a %u' /etc/db/password && grep /etc/db/password /proc/mounts && echo changed > /data")
+	c.Assert(out, checker.Contains, "400 1000")
+	c.Assert(out, checker.Contains, " ro,")
+
+	// the secret can't be removed while a container references it
+	out, _, err = dockerCmdWithError("secret", "rm", "db_password")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "in use by container")
+
+	out, _ = dockerCmd(c, "inspect", "test")
+	c.Assert(out, checker.Contains, "/etc/db/password")
+	c.Assert(out, checker.Not(checker.Contains), "correct horse")
+
+	out, _ = dockerCmd(c, "export", "test")
+	c.Assert(out, checker.Not(checker.Contains), "correct horse")
+	c.Assert(out, checker.Not(checker.Contains), "etc/db/password")
+
+	dockerCmd(c, "commit", "test", "secretimage")
+	out, _ = dockerCmd(c, "run", "--rm", "secretimage", "sh", "-c", "cat /data; ls /etc/db 2>&1")
+	c.Assert(out, checker.Contains, "changed")
+	c.Assert(out, checker.Not(checker.Contains), "password")
+	c.Assert(out, checker.Not(checker.Contains), "correct horse")
+
+	dockerCmd(c, "rm", "test")
+	dockerCmd(c, "secret", "rm", "db_password")
+}
+
+func (s *DockerSuite) TestSecretRunInvalid(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	out, _, err := dockerCmdWithError("run", "--secret", "nosuchsecret", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "no such secret")
+
+	out, _, err = dockerCmdWithError("run", "--secret", "db_password,mode=999", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "invalid value for mode")
+
+	createCmd := exec.Command(dockerBinary, "secret", "create", "db_password")
+	createCmd.Stdin = strings.NewReader("correct horse battery")
+	out, _, err = runCommandWithOutput(createCmd)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, _, err = dockerCmdWithError("run", "--secret", "db_password,target=relative", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "must be an absolute file path")
+}
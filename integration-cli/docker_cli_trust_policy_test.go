@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+// startTrustPolicyDaemon starts a daemon requiring signed images from the
+// private test registry.
+func (s *DockerTrustSuite) startTrustPolicyDaemon(c *check.C) *Daemon {
+	tmp, err := ioutil.TempDir("", "trust-policy")
+	c.Assert(err, checker.IsNil)
+	policyFile := filepath.Join(tmp, "policy.json")
+	policy := fmt.Sprintf(`{"scopes": [{"name": %q, "server": %q}]}`, privateRegistryURL, notaryURL)
+	c.Assert(ioutil.WriteFile(policyFile, []byte(policy), 0644), checker.IsNil)
+
+	d := NewDaemon(c)
+	c.Assert(d.StartWithBusybox("--trust-policy", policyFile), checker.IsNil)
+	return d
+}
+
+func (s *DockerTrustSuite) TestTrustPolicyPullSigned(c *check.C) {
+	repoName := s.setupTrustedImage(c, "trust-policy-pull")
+	d := s.startTrustPolicyDaemon(c)
+	defer d.Stop()
+
+	out, err := d.Cmd("pull", repoName)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Tagging", check.Commentf(out))
+
+	out, err = d.Cmd("run", "--rm", repoName, "true")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+}
+
+func (s *DockerTrustSuite) TestTrustPolicyPullUnsigned(c *check.C) {
+	repoName := fmt.Sprintf("%v/dockercli/trust-policy-unsigned:latest", privateRegistryURL)
+	dockerCmd(c, "tag", "busybox", repoName)
+	dockerCmd(c, "push", repoName)
+
+	d := s.startTrustPolicyDaemon(c)
+	defer d.Stop()
+
+	out, err := d.Cmd("pull", repoName)
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "trust policy violation", check.Commentf(out))
+}
+
+func (s *DockerTrustSuite) TestTrustPolicyCreateUnsigned(c *check.C) {
+	d := s.startTrustPolicyDaemon(c)
+	defer d.Stop()
+
+	repoName := fmt.Sprintf("%v/dockercli/trust-policy-local:latest", privateRegistryURL)
+	out, err := d.Cmd("tag", "busybox", repoName)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, err = d.Cmd("create", repoName)
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "trust policy violation", check.Commentf(out))
+
+	// images outside of the policy scopes are not affected
+	out, err = d.Cmd("create", "busybox")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+}
+
+func (s *DockerTrustSuite) TestTrustPolicyInvalidFile(c *check.C) {
+	tmp, err := ioutil.TempDir("", "trust-policy")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmp)
+	policyFile := filepath.Join(tmp, "policy.json")
+	c.Assert(ioutil.WriteFile(policyFile, []byte(`{"scopes": [{"name": ""}]}`), 0644), checker.IsNil)
+
+	d := NewDaemon(c)
+	c.Assert(d.Start("--trust-policy", policyFile), checker.NotNil)
+}
@@ -43,6 +43,24 @@ func (s *DockerSuite) TestUpdateRunningContainerWithRestart(c *check.C) {
 	c.Assert(strings.TrimSpace(out), checker.Equals, "524288000")
 }
 
+func (s *DockerSuite) TestUpdateCPUs(c *check.C) {
+	testRequires(c, DaemonIsLinux, cpuCfsQuota, cpuCfsPeriod)
+
+	name := "test-update-cpus"
+	dockerCmd(c, "run", "-d", "--name", name, "--cpus", "0.5", "busybox", "top")
+	dockerCmd(c, "update", "--cpus", "0.8", name)
+
+	c.Assert(inspectField(c, name, "HostConfig.NanoCPUs"), checker.Equals, "800000000")
+
+	file := "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	out, _ := dockerCmd(c, "exec", name, "cat", file)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "80000")
+
+	out, _, err := dockerCmdWithError("update", "--cpu-quota", "80000", name)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Conflicting options: CPU period or quota cannot be updated as CPUs has already been set")
+}
+
 func (s *DockerSuite) TestUpdateStoppedContainer(c *check.C) {
 	testRequires(c, DaemonIsLinux)
 	testRequires(c, memoryLimitSupport)
@@ -63,3 +63,80 @@ func (s *DockerDaemonSuite) TestDaemonUserNamespaceRootSetting(c *check.C) {
 	c.Assert(stat.UID(), checker.Equals, uint32(uid), check.Commentf("Touched file not owned by remapped root UID"))
 	c.Assert(stat.GID(), checker.Equals, uint32(gid), check.Commentf("Touched file not owned by remapped root GID"))
 }
+
+// user namespaces test: run a container with a private user namespace
+// allocated from the pool of the daemon
+// 1. validate the allocated range is reported by inspect and set as the uid/gid maps
+// 2. verify that the range is released when the container is removed
+func (s *DockerDaemonSuite) TestDaemonUserNamespacePool(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, UserNamespaceInKernel)
+
+	c.Assert(s.d.StartWithBusybox("--userns-pool", "default", "--storage-driver", "vfs"), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "-d", "--name", "private", "--userns", "private", "busybox", "sh", "-c", "touch /testfile; top")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+
+	out, err = s.d.Cmd("inspect", "--format", "{{(index .UsernsMappings.UIDMaps 0).HostID}} {{(index .UsernsMappings.GIDMaps 0).HostID}} {{.State.Pid}}", "private")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	fields := strings.Fields(out)
+	c.Assert(fields, checker.HasLen, 3)
+	uid, gid, pid := fields[0], fields[1], fields[2]
+
+	uidMap, err := ioutil.ReadFile("/proc/" + pid + "/uid_map")
+	c.Assert(err, checker.IsNil)
+	c.Assert(strings.Fields(string(uidMap)), checker.DeepEquals, []string{"0", uid, "65536"})
+	gidMap, err := ioutil.ReadFile("/proc/" + pid + "/gid_map")
+	c.Assert(err, checker.IsNil)
+	c.Assert(strings.Fields(string(gidMap)), checker.DeepEquals, []string{"0", gid, "65536"})
+
+	// the files of the root filesystem are owned by the root of the container
+	out, err = s.d.Cmd("exec", "private", "stat", "-c", "%u:%g", "/testfile", "/bin")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	c.Assert(strings.Fields(out), checker.DeepEquals, []string{"0:0", "0:0"})
+
+	// the range is released when the container is removed
+	out, err = s.d.Cmd("rm", "-f", "private")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	out, err = s.d.Cmd("create", "--name", "private2", "--userns", "private", "busybox")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	out, err = s.d.Cmd("inspect", "--format", "{{(index .UsernsMappings.UIDMaps 0).HostID}}", "private2")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, uid)
+}
+
+// a container whose range can't be reserved when the daemon restarts is
+// restored, but can't be started
+func (s *DockerDaemonSuite) TestDaemonUserNamespacePoolRestore(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, UserNamespaceInKernel)
+
+	c.Assert(s.d.StartWithBusybox("--userns-pool", "default", "--storage-driver", "vfs"), checker.IsNil)
+	out, err := s.d.Cmd("create", "--name", "private", "--userns", "private", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+
+	c.Assert(s.d.Restart("--storage-driver", "vfs"), checker.IsNil)
+	out, err = s.d.Cmd("ps", "-a", "--format", "{{.Names}}")
+	c.Assert(err, checker.IsNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "private")
+
+	out, err = s.d.Cmd("start", "private")
+	c.Assert(err, checker.NotNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "could not be reserved")
+}
+
+func (s *DockerDaemonSuite) TestDaemonUserNamespacePrivateWithoutPool(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	c.Assert(s.d.StartWithBusybox(), checker.IsNil)
+
+	out, err := s.d.Cmd("create", "--userns", "private", "busybox")
+	c.Assert(err, checker.NotNil, check.Commentf("Output: %s", out))
+	c.Assert(out, checker.Contains, "--userns-pool")
+}
+
+func (s *DockerDaemonSuite) TestDaemonRootlessAsRoot(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	c.Assert(s.d.Start("--rootless"), checker.NotNil)
+	content, _ := ioutil.ReadFile(s.d.logFile.Name())
+	c.Assert(string(content), checker.Contains, "--rootless")
+}
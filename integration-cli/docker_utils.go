@@ -344,6 +344,32 @@ func getAllVolumes() ([]*types.Volume, error) {
 	return volumes.Volumes, nil
 }
 
+func deleteAllSecrets() error {
+	var secrets []*types.Secret
+	_, b, err := sockRequest("GET", "/secrets", nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &secrets); err != nil {
+		return err
+	}
+	var errors []string
+	for _, s := range secrets {
+		status, b, err := sockRequest("DELETE", "/secrets/"+s.Name, nil)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		if status != http.StatusNoContent {
+			errors = append(errors, fmt.Sprintf("error deleting secret %s: %s", s.Name, string(b)))
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "\n"))
+	}
+	return nil
+}
+
 var protectedImages = map[string]struct{}{}
 
 func deleteAllImages() error {
@@ -3,6 +3,9 @@
 package main
 
 import (
+	"io/ioutil"
+	"strings"
+
 	"github.com/docker/docker/pkg/sysinfo"
 )
 
@@ -21,6 +24,12 @@ var (
 		},
 		"Test requires an environment that supports cgroup cfs quota.",
 	}
+	cpuRealtime = testRequirement{
+		func() bool {
+			return SysInfo.CPURealtimePeriod && SysInfo.CPURealtimeRuntime
+		},
+		"Test requires an environment that supports cgroup cpu real-time scheduling.",
+	}
 	cpuShare = testRequirement{
 		func() bool {
 			return SysInfo.CPUShares
@@ -87,6 +96,20 @@ var (
 		},
 		"Test requires that seccomp support be enabled in the daemon.",
 	}
+	seccompLogAction = testRequirement{
+		func() bool {
+			actions, err := ioutil.ReadFile("/proc/sys/kernel/seccomp/actions_avail")
+			return err == nil && strings.Contains(string(actions), "log")
+		},
+		"Test requires a kernel supporting the seccomp log action.",
+	}
+	ambientCapabilities = testRequirement{
+		func() bool {
+			status, err := ioutil.ReadFile("/proc/self/status")
+			return err == nil && strings.Contains(string(status), "CapAmb:")
+		},
+		"Test requires a kernel supporting ambient capabilities.",
+	}
 	bridgeNfIptables = testRequirement{
 		func() bool {
 			return !SysInfo.BridgeNFCallIPTablesDisabled
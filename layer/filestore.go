@@ -2,6 +2,7 @@ package layer
 
 import (
 	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -228,6 +229,17 @@ func (fms *fileMetadataStore) SetMountParent(mount string, parent ChainID) error
 	return ioutil.WriteFile(fms.getMountFilename(mount, "parent"), []byte(digest.Digest(parent).String()), 0644)
 }
 
+func (fms *fileMetadataStore) SetMountIDMappings(mount string, idMappings *IDMappings) error {
+	if err := os.MkdirAll(fms.getMountDirectory(mount), 0755); err != nil {
+		return err
+	}
+	content, err := json.Marshal(idMappings)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fms.getMountFilename(mount, "id-mappings"), content, 0644)
+}
+
 func (fms *fileMetadataStore) GetMountID(mount string) (string, error) {
 	contentBytes, err := ioutil.ReadFile(fms.getMountFilename(mount, "mount-id"))
 	if err != nil {
@@ -276,6 +288,23 @@ func (fms *fileMetadataStore) GetMountParent(mount string) (ChainID, error) {
 	return ChainID(dgst), nil
 }
 
+func (fms *fileMetadataStore) GetMountIDMappings(mount string) (*IDMappings, error) {
+	content, err := ioutil.ReadFile(fms.getMountFilename(mount, "id-mappings"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var idMappings IDMappings
+	if err := json.Unmarshal(content, &idMappings); err != nil {
+		return nil, err
+	}
+
+	return &idMappings, nil
+}
+
 func (fms *fileMetadataStore) List() ([]ChainID, []string, error) {
 	var ids []ChainID
 	for _, algorithm := range supportedAlgorithms {
@@ -0,0 +1,93 @@
+package layer
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+// IDMappings are the user and group ID mappings of the user namespace a
+// read-write layer is used in. The files of such a layer are owned by the
+// host IDs the container IDs are mapped to.
+type IDMappings struct {
+	UIDMaps []idtools.IDMap
+	GIDMaps []idtools.IDMap
+}
+
+// validate checks that the host IDs of the mappings don't overlap with the
+// container IDs, so that the shifted files can be told from the others.
+func (m *IDMappings) validate() error {
+	for _, maps := range [][]idtools.IDMap{m.UIDMaps, m.GIDMaps} {
+		if len(maps) == 0 {
+			return fmt.Errorf("Invalid empty ID mappings")
+		}
+		for _, h := range maps {
+			for _, c := range maps {
+				if h.HostID < c.ContainerID+c.Size && c.ContainerID < h.HostID+h.Size {
+					return fmt.Errorf("Invalid ID mappings %v: the host IDs overlap with the container IDs", maps)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// shiftID returns the host ID container ID id is mapped to. IDs which are
+// already host IDs of the mappings, or which can't be mapped, are returned
+// unchanged.
+func shiftID(id int, maps []idtools.IDMap) int {
+	if _, err := idtools.ToContainer(id, maps); err == nil {
+		return id
+	}
+	if hostID, err := idtools.ToHost(id, maps); err == nil {
+		return hostID
+	}
+	return id
+}
+
+// unshiftID returns the container ID host ID id is mapped to, or id if it
+// isn't a host ID of the mappings.
+func unshiftID(id int, maps []idtools.IDMap) int {
+	if contID, err := idtools.ToContainer(id, maps); err == nil {
+		return contID
+	}
+	return id
+}
+
+// unshiftTarStream returns a tar stream with the owners of the entries of in
+// mapped back to container IDs.
+func unshiftTarStream(in io.ReadCloser, m *IDMappings) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		tr := tar.NewReader(in)
+		tw := tar.NewWriter(pw)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			hdr.Uid = unshiftID(hdr.Uid, m.UIDMaps)
+			hdr.Gid = unshiftID(hdr.Gid, m.GIDMaps)
+			if err := tw.WriteHeader(hdr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+	return ioutils.NewReadCloserWrapper(pr, func() error {
+		pr.Close()
+		return in.Close()
+	})
+}
@@ -0,0 +1,107 @@
+package layer
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/system"
+)
+
+const (
+	// capabilityXattr holds the file capabilities, which the kernel removes
+	// when the owner of the file changes.
+	capabilityXattr = "security.capability"
+	// vfsCapRevision2 and vfsCapRevision3 are the revisions of the file
+	// capabilities, the latter applying to the user namespaces whose root is
+	// mapped to its root ID.
+	vfsCapRevision2    = 0x02000000
+	vfsCapRevision3    = 0x03000000
+	vfsCapRevisionMask = 0xff000000
+	vfsCapSize2        = 20
+)
+
+// shiftOwnership changes the owner of the files under root from the
+// container IDs to the host IDs they are mapped to. Files already owned by
+// host IDs of the mappings are left untouched, so that hard links are only
+// shifted once. The set-user-ID and set-group-ID bits and the capabilities
+// of the files, which are cleared by the change of owner, are restored.
+func shiftOwnership(root string, m *IDMappings) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	rootUID, _, err := idtools.GetRootUIDGID(m.UIDMaps, m.GIDMaps)
+	if err != nil {
+		return err
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return shiftFile(path, info, m, rootUID)
+	})
+}
+
+// shiftRootOwnership changes the owner of the directory root itself, which
+// the graph drivers create owned by their own root.
+func shiftRootOwnership(root string, m *IDMappings) error {
+	rootUID, _, err := idtools.GetRootUIDGID(m.UIDMaps, m.GIDMaps)
+	if err != nil {
+		return err
+	}
+	info, err := os.Lstat(root)
+	if err != nil {
+		return err
+	}
+	return shiftFile(root, info, m, rootUID)
+}
+
+func shiftFile(path string, info os.FileInfo, m *IDMappings, rootUID int) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	uid, gid := shiftID(int(st.Uid), m.UIDMaps), shiftID(int(st.Gid), m.GIDMaps)
+	if uid == int(st.Uid) && gid == int(st.Gid) {
+		return nil
+	}
+
+	var caps []byte
+	if info.Mode().IsRegular() {
+		// Errors are ignored as the file system may not support extended
+		// attributes.
+		caps, _ = system.Lgetxattr(path, capabilityXattr)
+	}
+	if err := os.Lchown(path, uid, gid); err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink == 0 && info.Mode()&(os.ModeSetuid|os.ModeSetgid) != 0 {
+		if err := os.Chmod(path, info.Mode()); err != nil {
+			return err
+		}
+	}
+	if caps != nil {
+		return system.Lsetxattr(path, capabilityXattr, namespacedCaps(caps, rootUID), 0)
+	}
+	return nil
+}
+
+// namespacedCaps converts file capabilities to the revision applying to the
+// user namespaces whose root is mapped to rootUID.
+func namespacedCaps(caps []byte, rootUID int) []byte {
+	if len(caps) < vfsCapSize2 {
+		return caps
+	}
+	magic := binary.LittleEndian.Uint32(caps)
+	if rev := magic & vfsCapRevisionMask; rev != vfsCapRevision2 && rev != vfsCapRevision3 {
+		return caps
+	}
+	ns := make([]byte, vfsCapSize2+4)
+	copy(ns, caps[:vfsCapSize2])
+	binary.LittleEndian.PutUint32(ns, vfsCapRevision3|magic&^vfsCapRevisionMask)
+	binary.LittleEndian.PutUint32(ns[vfsCapSize2:], uint32(rootUID))
+	return ns
+}
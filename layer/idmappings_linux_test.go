@@ -0,0 +1,180 @@
+package layer
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
+)
+
+func TestMountIDMappings(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("Changing the owner of files requires root")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer, err := createLayer(ls, "", initWithFiles(
+		newTestFile("file", []byte("file"), 0644),
+		newTestFile("setuid", []byte("setuid"), 0755|os.ModeSetuid),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idMappings := &IDMappings{
+		UIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		GIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 65536}},
+	}
+	overlapping := &IDMappings{
+		UIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 1000, Size: 65536}},
+		GIDMaps: idMappings.GIDMaps,
+	}
+	if _, err := ls.CreateRWLayer("overlapping-mount", layer.ChainID(), "", nil, overlapping); err == nil {
+		t.Fatal("Expected an error creating a mount with overlapping ID mappings")
+	}
+
+	m, err := ls.CreateRWLayer("shifted-mount", layer.ChainID(), "", nil, idMappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := m.Mount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{".", "file", "setuid"} {
+		fi, err := os.Lstat(filepath.Join(path, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := fi.Sys().(*syscall.Stat_t)
+		if st.Uid != 100000 || st.Gid != 200000 {
+			t.Fatalf("Expected %s to be owned by 100000:200000, got %d:%d", name, st.Uid, st.Gid)
+		}
+		if name == "setuid" && fi.Mode()&os.ModeSetuid == 0 {
+			t.Fatalf("Expected the set-user-ID bit of %s to be kept, got mode %s", name, fi.Mode())
+		}
+	}
+
+	// A file created by uid 5 of the container.
+	if err := newTestFile("new", []byte("new"), 0644).ApplyFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Lchown(filepath.Join(path, "new"), 100005, 200005); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The shifted files are not part of the changes of the layer.
+	changes, err := m.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Path != "/new" {
+		t.Fatalf("Expected only /new to be changed, got %v", changes)
+	}
+
+	// The owners are mapped back to container IDs in the tar stream, and
+	// the mappings are restored with the store.
+	ls2, err := NewStoreFromGraphDriver(ls.(*layerStore).store, ls.(*layerStore).driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := ls2.GetRWLayer("shifted-mount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := m2.TarStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	tr := tar.NewReader(ts)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name == "new" && (hdr.Uid != 5 || hdr.Gid != 5) {
+			t.Fatalf("Expected new to be owned by 5:5 in the tar stream, got %d:%d", hdr.Uid, hdr.Gid)
+		}
+	}
+}
+
+func TestMountIDMappingsCleanup(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("Changing the owner of files requires root")
+	}
+	td, err := ioutil.TempDir("", "graph-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(td)
+	driver, err := newVFSGraphDriver(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fms, err := NewFSMetadataStore(filepath.Join(td, "metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, err := NewStoreFromGraphDriver(fms, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layer, err := createLayer(ls, "", initWithFiles(newTestFile("file", []byte("file"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := ioutil.ReadDir(filepath.Join(td, "vfs", "dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idMappings := &IDMappings{
+		UIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+		GIDMaps: []idtools.IDMap{{ContainerID: 0, HostID: 200000, Size: 65536}},
+	}
+	initErr := errors.New("init failure")
+	if _, err := ls.CreateRWLayer("failed-mount", layer.ChainID(), "", func(string) error { return initErr }, idMappings); err != initErr {
+		t.Fatalf("Expected the init error, got %v", err)
+	}
+
+	// The driver layers of the mount are removed.
+	after, err := ioutil.ReadDir(filepath.Join(td, "vfs", "dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("Expected %d driver layers, got %d", len(before), len(after))
+	}
+	if _, err := ls.GetRWLayer("failed-mount"); err != ErrMountDoesNotExist {
+		t.Fatalf("Expected the failed mount not to exist, got %v", err)
+	}
+}
+
+func TestNamespacedCaps(t *testing.T) {
+	// cap_net_raw+ep with the revision 2 format.
+	v2 := []byte{0x01, 0x00, 0x00, 0x02, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	v3 := namespacedCaps(v2, 100000)
+	expected := []byte{0x01, 0x00, 0x00, 0x03, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa0, 0x86, 0x01, 0x00}
+	if string(v3) != string(expected) {
+		t.Fatalf("Expected %x, got %x", expected, v3)
+	}
+	if v := namespacedCaps(v3, 200000); string(v[:20]) != string(expected[:20]) || v[20] != 0x40 {
+		t.Fatalf("Expected the root ID of revision 3 capabilities to be replaced, got %x", v)
+	}
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package layer
+
+import "fmt"
+
+func shiftOwnership(root string, m *IDMappings) error {
+	return fmt.Errorf("ID mappings are only supported on Linux")
+}
+
+func shiftRootOwnership(root string, m *IDMappings) error {
+	return fmt.Errorf("ID mappings are only supported on Linux")
+}
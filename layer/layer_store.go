@@ -170,10 +170,16 @@ func (ls *layerStore) loadMount(mount string) error {
 		return err
 	}
 
+	idMappings, err := ls.store.GetMountIDMappings(mount)
+	if err != nil {
+		return err
+	}
+
 	ml := &mountedLayer{
 		name:       mount,
 		mountID:    mountID,
 		initID:     initID,
+		idMappings: idMappings,
 		layerStore: ls,
 		references: map[RWLayer]*referencedRWLayer{},
 	}
@@ -414,7 +420,7 @@ func (ls *layerStore) Release(l Layer) ([]Metadata, error) {
 	return ls.releaseLayer(layer)
 }
 
-func (ls *layerStore) CreateRWLayer(name string, parent ChainID, mountLabel string, initFunc MountInit) (RWLayer, error) {
+func (ls *layerStore) CreateRWLayer(name string, parent ChainID, mountLabel string, initFunc MountInit, idMappings *IDMappings) (RWLayer, error) {
 	ls.mountL.Lock()
 	defer ls.mountL.Unlock()
 	m, ok := ls.mounts[name]
@@ -423,6 +429,17 @@ func (ls *layerStore) CreateRWLayer(name string, parent ChainID, mountLabel stri
 	}
 
 	var err error
+	if idMappings != nil {
+		// the files of the parent layers are chowned in the init layer,
+		// which would copy them all up with the other drivers
+		if ls.driver.String() != "vfs" {
+			return nil, fmt.Errorf("ID mappings are not supported by the %s storage driver", ls.driver)
+		}
+		if err = idMappings.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	var pid string
 	var p *roLayer
 	if string(parent) != "" {
@@ -446,23 +463,51 @@ func (ls *layerStore) CreateRWLayer(name string, parent ChainID, mountLabel stri
 		name:       name,
 		parent:     p,
 		mountID:    ls.mountID(name),
+		idMappings: idMappings,
 		layerStore: ls,
 		references: map[RWLayer]*referencedRWLayer{},
 	}
 
-	if initFunc != nil {
-		pid, err = ls.initMount(m.mountID, pid, mountLabel, initFunc)
+	if initFunc != nil || idMappings != nil {
+		pid, err = ls.initMount(m.mountID, pid, mountLabel, initFunc, idMappings)
 		if err != nil {
 			return nil, err
 		}
 		m.initID = pid
+
+		// Remove the init layer if error
+		defer func() {
+			if err != nil {
+				if err := ls.driver.Remove(m.initID); err != nil {
+					logrus.Errorf("Error removing init layer %s: %s", m.name, err)
+				}
+			}
+		}()
 	}
 
 	if err = ls.driver.Create(m.mountID, pid, ""); err != nil {
 		return nil, err
 	}
 
+	// Remove the mounted layer if error
+	defer func() {
+		if err != nil {
+			if err := ls.driver.Remove(m.mountID); err != nil {
+				logrus.Errorf("Error removing mounted layer %s: %s", m.name, err)
+			}
+		}
+	}()
+
+	if idMappings != nil {
+		if err = ls.shiftMountRoot(m.mountID, idMappings); err != nil {
+			return nil, err
+		}
+	}
+
 	if err = ls.saveMount(m); err != nil {
+		if err := ls.store.RemoveMount(m.name); err != nil {
+			logrus.Errorf("Error removing mount metadata: %s: %s", m.name, err)
+		}
 		return nil, err
 	}
 
@@ -544,12 +589,18 @@ func (ls *layerStore) saveMount(mount *mountedLayer) error {
 		}
 	}
 
+	if mount.idMappings != nil {
+		if err := ls.store.SetMountIDMappings(mount.name, mount.idMappings); err != nil {
+			return err
+		}
+	}
+
 	ls.mounts[mount.name] = mount
 
 	return nil
 }
 
-func (ls *layerStore) initMount(graphID, parent, mountLabel string, initFunc MountInit) (string, error) {
+func (ls *layerStore) initMount(graphID, parent, mountLabel string, initFunc MountInit, idMappings *IDMappings) (string, error) {
 	// Use "<graph-id>-init" to maintain compatibility with graph drivers
 	// which are expecting this layer with this special name. If all
 	// graph drivers can be updated to not rely on knowing about this layer
@@ -564,9 +615,28 @@ func (ls *layerStore) initMount(graphID, parent, mountLabel string, initFunc Mou
 		return "", err
 	}
 
-	if err := initFunc(p); err != nil {
-		ls.driver.Put(initID)
-		return "", err
+	if initFunc != nil {
+		if err := initFunc(p); err != nil {
+			ls.driver.Put(initID)
+			if err := ls.driver.Remove(initID); err != nil {
+				logrus.Errorf("Error removing init layer %s: %s", initID, err)
+			}
+			return "", err
+		}
+	}
+
+	// The files of the parent layers are shifted in the init layer, so
+	// that the shift is part of neither the images nor the changes of the
+	// read-write layer. With vfs, the only driver supporting ID mappings,
+	// the init layer is already a full copy of its parent.
+	if idMappings != nil {
+		if err := shiftOwnership(p, idMappings); err != nil {
+			ls.driver.Put(initID)
+			if err := ls.driver.Remove(initID); err != nil {
+				logrus.Errorf("Error removing init layer %s: %s", initID, err)
+			}
+			return "", err
+		}
 	}
 
 	if err := ls.driver.Put(initID); err != nil {
@@ -576,6 +646,20 @@ func (ls *layerStore) initMount(graphID, parent, mountLabel string, initFunc Mou
 	return initID, nil
 }
 
+// shiftMountRoot changes the owner of the root directory of a mounted layer
+// to the host IDs the root of the container is mapped to.
+func (ls *layerStore) shiftMountRoot(graphID string, idMappings *IDMappings) error {
+	p, err := ls.driver.Get(graphID, "")
+	if err != nil {
+		return err
+	}
+	if err := shiftRootOwnership(p, idMappings); err != nil {
+		ls.driver.Put(graphID)
+		return err
+	}
+	return ls.driver.Put(graphID)
+}
+
 func (ls *layerStore) assembleTarTo(graphID string, metadata io.ReadCloser, size *int64, w io.Writer) error {
 	diffDriver, ok := ls.driver.(graphdriver.DiffGetterDriver)
 	if !ok {
@@ -11,6 +11,7 @@ type mountedLayer struct {
 	name       string
 	mountID    string
 	initID     string
+	idMappings *IDMappings
 	parent     *roLayer
 	layerStore *layerStore
 
@@ -32,6 +33,9 @@ func (ml *mountedLayer) TarStream() (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
+	if ml.idMappings != nil {
+		return unshiftTarStream(archiver, ml.idMappings), nil
+	}
 	return archiver, nil
 }
 
@@ -0,0 +1,121 @@
+// Package audit records the requests changing the state of the daemon.
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session stages of the records of the exec and attach sessions, which are
+// recorded when they start and when they end.
+const (
+	StageStart = "start"
+	StageEnd   = "end"
+)
+
+// Record is an entry of the audit log.
+type Record struct {
+	Time        time.Time   `json:"time"`
+	User        string      `json:"user,omitempty"`
+	Groups      []string    `json:"groups,omitempty"`
+	AuthMethod  string      `json:"authMethod,omitempty"`
+	PeerAddr    string      `json:"peerAddr,omitempty"`
+	Method      string      `json:"method"`
+	Route       string      `json:"route"`
+	ResourceIDs []string    `json:"resourceIDs,omitempty"`
+	Body        interface{} `json:"body,omitempty"`
+	Session     string      `json:"session,omitempty"`
+	Stage       string      `json:"stage,omitempty"`
+	Status      int         `json:"status,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Duration    string      `json:"duration,omitempty"`
+}
+
+// Resolver returns the IDs of the resources referenced by name or ID in a
+// request path. The type of the resources is the first segment of the route,
+// e.g. containers or networks.
+type Resolver interface {
+	ResourceIDs(resourceType, ref string) []string
+}
+
+// Logger writes the audit records to a sink, one JSON object per write.
+type Logger struct {
+	mu   sync.Mutex
+	sink io.WriteCloser
+	enc  *json.Encoder
+}
+
+// New returns a logger writing to sink.
+func New(sink io.WriteCloser) *Logger {
+	return &Logger{sink: sink, enc: json.NewEncoder(sink)}
+}
+
+// Log writes the record to the sink.
+func (l *Logger) Log(r *Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now().UTC()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc.Encode(r)
+}
+
+// Close closes the sink.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sink.Close()
+}
+
+const redacted = "*****"
+
+// sensitiveKeys are the lower cased names of the fields whose values are
+// never recorded.
+var sensitiveKeys = map[string]bool{
+	"auth":          true,
+	"data":          true,
+	"identitytoken": true,
+	"password":      true,
+	"registrytoken": true,
+	"secret":        true,
+	"token":         true,
+}
+
+// Summarize returns the JSON request body with the values of credentials
+// fields redacted and only the names of the environment variables kept. It
+// returns nil if the body isn't a JSON object.
+func Summarize(body []byte) interface{} {
+	var v map[string]interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil
+	}
+	return redact("", v)
+}
+
+func redact(key string, v interface{}) interface{} {
+	if v != nil && sensitiveKeys[strings.ToLower(key)] {
+		return redacted
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = redact(k, e)
+		}
+		return v
+	case []interface{}:
+		env := strings.EqualFold(key, "env")
+		for i, e := range v {
+			if s, ok := e.(string); ok && env {
+				v[i] = strings.SplitN(s, "=", 2)[0] + "=" + redacted
+				continue
+			}
+			v[i] = redact(key, e)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type closeBuffer struct {
+	bytes.Buffer
+}
+
+func (*closeBuffer) Close() error {
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	sink := &closeBuffer{}
+	l := New(sink)
+	if err := l.Log(&Record{User: "alice", Method: "POST", Route: "/containers/create", Status: 201}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log(&Record{Method: "DELETE", Route: "/containers/web", Status: 404, Error: "No such container: web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dec := json.NewDecoder(sink)
+	var r Record
+	if err := dec.Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.User != "alice" || r.Route != "/containers/create" || r.Status != 201 || r.Time.IsZero() {
+		t.Fatalf("unexpected first record %+v", r)
+	}
+	r = Record{}
+	if err := dec.Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.User != "" || r.Status != 404 || r.Error != "No such container: web" {
+		t.Fatalf("unexpected second record %+v", r)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	body := `{
+		"Image": "busybox",
+		"Env": ["PATH=/bin", "DB_PASSWORD=hunter2", "EMPTY"],
+		"HostConfig": {"Memory": 1024},
+		"password": "hunter2",
+		"AuthConfig": {"username": "alice", "Password": "hunter2", "auth": {"nested": "secret"}},
+		"Labels": {"token": "abc"}
+	}`
+	expected := map[string]interface{}{
+		"Image":      "busybox",
+		"Env":        []interface{}{"PATH=*****", "DB_PASSWORD=*****", "EMPTY=*****"},
+		"HostConfig": map[string]interface{}{"Memory": float64(1024)},
+		"password":   "*****",
+		"AuthConfig": map[string]interface{}{"username": "alice", "Password": "*****", "auth": "*****"},
+		"Labels":     map[string]interface{}{"token": "*****"},
+	}
+	if s := Summarize([]byte(body)); !reflect.DeepEqual(s, expected) {
+		t.Fatalf("expected %v, got %v", expected, s)
+	}
+
+	if s := Summarize([]byte("not json")); s != nil {
+		t.Fatalf("expected no summary of an invalid body, got %v", s)
+	}
+}
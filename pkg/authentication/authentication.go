@@ -0,0 +1,72 @@
+// Package authentication provides the mechanisms identifying the callers of
+// the daemon API. The identity of a request is handed to the authorization
+// plugins and attached to the events the request causes.
+package authentication
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+// Identity is the authenticated caller of an API request.
+type Identity struct {
+	// User is the name of the caller
+	User string
+	// Groups are the groups the caller is a member of
+	Groups []string
+	// Method is the name of the mechanism which authenticated the caller
+	Method string
+}
+
+// Authenticator extracts the identity of the caller of an API request.
+type Authenticator interface {
+	// Name returns the name of the authentication method.
+	Name() string
+	// Authenticate returns the identity of the caller of the request, or
+	// nil if the request carries no credentials for this method. An error
+	// is returned if the request carries invalid credentials.
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+type identityKey struct{}
+
+// NewContext returns a copy of ctx carrying the identity id.
+func NewContext(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity carried by ctx, or nil if the request
+// was not authenticated.
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// Attempt is the outcome of the authentication of a request.
+type Attempt struct {
+	// Identity is the identity of the caller, nil if the request is
+	// anonymous or its credentials were rejected.
+	Identity *Identity
+	// Method is the name of the method which rejected the credentials.
+	Method string
+	// Err is the error rejecting the credentials.
+	Err error
+}
+
+type attemptKey struct{}
+
+// WithAttempt returns a copy of ctx in which the outcome of the
+// authentication of the request is recorded, and the attempt recording it.
+// It lets the middlewares running before the authentication know about it.
+func WithAttempt(ctx context.Context) (context.Context, *Attempt) {
+	a := &Attempt{}
+	return context.WithValue(ctx, attemptKey{}, a), a
+}
+
+// AttemptFromContext returns the attempt in which the outcome of the
+// authentication of the request is recorded, or nil.
+func AttemptFromContext(ctx context.Context) *Attempt {
+	a, _ := ctx.Value(attemptKey{}).(*Attempt)
+	return a
+}
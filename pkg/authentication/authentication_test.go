@@ -0,0 +1,125 @@
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestContext(t *testing.T) {
+	if id := FromContext(context.Background()); id != nil {
+		t.Fatalf("Expected no identity, got %v", id)
+	}
+	id := &Identity{User: "alice"}
+	if got := FromContext(NewContext(context.Background(), id)); got != id {
+		t.Fatalf("Expected %v, got %v", id, got)
+	}
+}
+
+func TestTLSAuthenticator(t *testing.T) {
+	a := NewTLSAuthenticator()
+	r, _ := http.NewRequest("GET", "/info", nil)
+	if id, err := a.Authenticate(r); id != nil || err != nil {
+		t.Fatalf("Expected no identity without TLS, got %v, %v", id, err)
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{
+		CommonName:         "alice",
+		Organization:       []string{"acme"},
+		OrganizationalUnit: []string{"team-a"},
+	}}
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	id, err := a.Authenticate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Identity{User: "alice", Groups: []string{"acme", "team-a"}, Method: TLSMethod}
+	if !reflect.DeepEqual(id, expected) {
+		t.Fatalf("Expected %v, got %v", expected, id)
+	}
+
+	cert.Subject.CommonName = ""
+	if id, _ = a.Authenticate(r); id.User != "O=acme,OU=team-a" {
+		t.Fatalf("Expected the subject as user, got %s", id.User)
+	}
+
+	// Certificates which were not verified don't identify the caller
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if id, err := a.Authenticate(r); id != nil || err != nil {
+		t.Fatalf("Expected no identity without verified chains, got %v, %v", id, err)
+	}
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	f, err := ioutil.TempFile("", "tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("# token user groups\n\nsecret1 alice team-a,ops\nsecret2 bob\n")
+	f.Close()
+
+	a, err := NewTokenAuthenticator(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		header   string
+		expected *Identity
+		err      bool
+	}{
+		{"", nil, false},
+		{"Basic YWxpY2U6c2VjcmV0", nil, false},
+		{"Bearer secret1", &Identity{User: "alice", Groups: []string{"team-a", "ops"}, Method: TokenMethod}, false},
+		{"bearer secret2", &Identity{User: "bob", Method: TokenMethod}, false},
+		{"Bearer wrong", nil, true},
+	}
+	for _, c := range cases {
+		r, _ := http.NewRequest("GET", "/info", nil)
+		if c.header != "" {
+			r.Header.Set("Authorization", c.header)
+		}
+		id, err := a.Authenticate(r)
+		if c.err != (err != nil) {
+			t.Fatalf("%q: unexpected error %v", c.header, err)
+		}
+		if !reflect.DeepEqual(id, c.expected) {
+			t.Fatalf("%q: expected %v, got %v", c.header, c.expected, id)
+		}
+	}
+}
+
+func TestTokenAuthenticatorInvalidFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("secret1\n")
+	f.Close()
+
+	if _, err := NewTokenAuthenticator(f.Name()); err == nil {
+		t.Fatal("Expected an error for a token without user")
+	}
+}
+
+func TestParsePeerCredAddr(t *testing.T) {
+	addr := peerCredAddr{pid: 42, uid: 1000, gid: 100}
+	parsed, ok := parsePeerCredAddr(addr.String())
+	if !ok || parsed != addr {
+		t.Fatalf("Expected %v, got %v", addr, parsed)
+	}
+	for _, s := range []string{"", "@", "127.0.0.1:4243", "peercred:uid=x", "peercred:foo=1"} {
+		if _, ok := parsePeerCredAddr(s); ok {
+			t.Fatalf("Expected %q not to be parsed", s)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package authentication
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/user"
+)
+
+// PeerCredMethod is the name of the Unix socket peer credentials
+// authentication method.
+const PeerCredMethod = "peercred"
+
+// peerCredPrefix starts the remote address of the connections whose peer
+// credentials were read by a peer credentials listener.
+const peerCredPrefix = "peercred:"
+
+// peerCredAddr is the remote address of a connection on a Unix socket,
+// carrying the credentials of the peer process.
+type peerCredAddr struct {
+	pid, uid, gid int
+}
+
+func (a peerCredAddr) Network() string {
+	return "unix"
+}
+
+func (a peerCredAddr) String() string {
+	return fmt.Sprintf("%spid=%d,uid=%d,gid=%d", peerCredPrefix, a.pid, a.uid, a.gid)
+}
+
+func parsePeerCredAddr(addr string) (peerCredAddr, bool) {
+	var a peerCredAddr
+	if !strings.HasPrefix(addr, peerCredPrefix) {
+		return a, false
+	}
+	for _, field := range strings.Split(strings.TrimPrefix(addr, peerCredPrefix), ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return a, false
+		}
+		v, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return a, false
+		}
+		switch kv[0] {
+		case "pid":
+			a.pid = v
+		case "uid":
+			a.uid = v
+		case "gid":
+			a.gid = v
+		default:
+			return a, false
+		}
+	}
+	return a, true
+}
+
+type peerCredAuthenticator struct{}
+
+// NewPeerCredAuthenticator returns an authenticator identifying the callers
+// connected to a Unix socket by the credentials of their process, read with
+// SO_PEERCRED by the listener returned by NewPeerCredListener. The user is
+// the name of the peer uid and the groups are the names of its primary and
+// supplementary groups. Unknown ids are reported as numbers.
+func NewPeerCredAuthenticator() Authenticator {
+	return peerCredAuthenticator{}
+}
+
+func (peerCredAuthenticator) Name() string {
+	return PeerCredMethod
+}
+
+func (peerCredAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	addr, ok := parsePeerCredAddr(r.RemoteAddr)
+	if !ok {
+		return nil, nil
+	}
+
+	id := &Identity{
+		User:   strconv.Itoa(addr.uid),
+		Method: PeerCredMethod,
+	}
+	if u, err := user.LookupUid(addr.uid); err == nil {
+		id.User = u.Name
+	}
+	primary := strconv.Itoa(addr.gid)
+	if g, err := user.LookupGid(addr.gid); err == nil {
+		primary = g.Name
+	}
+	id.Groups = append(id.Groups, primary)
+
+	// Supplementary groups list their members by name
+	groups, _ := user.ParseGroupFileFilter("/etc/group", func(g user.Group) bool {
+		if g.Name == primary {
+			return false
+		}
+		for _, m := range g.List {
+			if m == id.User {
+				return true
+			}
+		}
+		return false
+	})
+	for _, g := range groups {
+		id.Groups = append(id.Groups, g.Name)
+	}
+	return id, nil
+}
@@ -0,0 +1,63 @@
+package authentication
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+)
+
+type peerCredListener struct {
+	net.Listener
+}
+
+// peerCredConn overrides the remote address of a Unix socket connection to
+// carry the credentials of the peer.
+type peerCredConn struct {
+	*net.UnixConn
+	addr peerCredAddr
+}
+
+func (c *peerCredConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// NewPeerCredListener wraps a Unix socket listener so that the credentials
+// of the peer process of each connection are read on accept and reported as
+// the remote address of its requests.
+func NewPeerCredListener(l net.Listener) net.Listener {
+	return &peerCredListener{l}
+}
+
+func (l *peerCredListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return c, nil
+	}
+	cred, err := getPeerCred(uc)
+	if err != nil {
+		logrus.Warnf("Failed to read the peer credentials of a connection: %v", err)
+		return c, nil
+	}
+	return &peerCredConn{
+		UnixConn: uc,
+		addr:     peerCredAddr{pid: int(cred.Pid), uid: int(cred.Uid), gid: int(cred.Gid)},
+	}, nil
+}
+
+func getPeerCred(c *net.UnixConn) (*syscall.Ucred, error) {
+	f, err := c.File()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fd := int(f.Fd())
+	// The duplicate shares the file status flags of the connection, which
+	// must stay non-blocking for the runtime poller.
+	defer syscall.SetNonblock(fd, true)
+	return syscall.GetsockoptUcred(fd, syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+}
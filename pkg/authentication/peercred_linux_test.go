@@ -0,0 +1,60 @@
+package authentication
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/opencontainers/runc/libcontainer/user"
+)
+
+func TestPeerCredListener(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peercred")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l = NewPeerCredListener(l)
+	defer l.Close()
+
+	go func() {
+		if c, err := net.Dial("unix", l.Addr().String()); err == nil {
+			c.Close()
+		}
+	}()
+
+	c, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	r, _ := http.NewRequest("GET", "/info", nil)
+	r.RemoteAddr = c.RemoteAddr().String()
+	id, err := NewPeerCredAuthenticator().Authenticate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == nil || id.Method != PeerCredMethod {
+		t.Fatalf("Expected a peer credentials identity, got %v", id)
+	}
+	expected := strconv.Itoa(os.Getuid())
+	if u, err := user.LookupUid(os.Getuid()); err == nil {
+		expected = u.Name
+	}
+	if id.User != expected {
+		t.Fatalf("Expected user %s, got %s", expected, id.User)
+	}
+	if len(id.Groups) == 0 {
+		t.Fatal("Expected the primary group of the peer")
+	}
+}
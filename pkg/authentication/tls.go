@@ -0,0 +1,62 @@
+package authentication
+
+import (
+	"crypto/x509/pkix"
+	"net/http"
+	"strings"
+)
+
+// TLSMethod is the name of the TLS client certificate authentication method.
+const TLSMethod = "tls"
+
+type tlsAuthenticator struct{}
+
+// NewTLSAuthenticator returns an authenticator identifying the callers by
+// the verified TLS client certificate they present. The user is the common
+// name of the certificate subject, or the whole subject if it has none, and
+// the groups are its organizations and organizational units.
+func NewTLSAuthenticator() Authenticator {
+	return tlsAuthenticator{}
+}
+
+func (tlsAuthenticator) Name() string {
+	return TLSMethod
+}
+
+func (tlsAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	// Only the chains verified against the daemon CA identify a caller,
+	// which requires --tlsverify.
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	subject := r.TLS.VerifiedChains[0][0].Subject
+
+	id := &Identity{
+		User:   subject.CommonName,
+		Method: TLSMethod,
+	}
+	if id.User == "" {
+		id.User = subjectString(subject)
+	}
+	id.Groups = append(id.Groups, subject.Organization...)
+	id.Groups = append(id.Groups, subject.OrganizationalUnit...)
+	return id, nil
+}
+
+// subjectString formats the distinguished name of a subject without common
+// name, most significant attribute first.
+func subjectString(subject pkix.Name) string {
+	var parts []string
+	add := func(key string, values ...string) {
+		for _, v := range values {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	add("C", subject.Country...)
+	add("O", subject.Organization...)
+	add("OU", subject.OrganizationalUnit...)
+	if subject.SerialNumber != "" {
+		add("SERIALNUMBER", subject.SerialNumber)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,89 @@
+package authentication
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TokenMethod is the name of the bearer token authentication method.
+const TokenMethod = "token"
+
+type tokenEntry struct {
+	token  string
+	user   string
+	groups []string
+}
+
+type tokenAuthenticator struct {
+	entries []tokenEntry
+}
+
+// NewTokenAuthenticator returns an authenticator identifying the callers by
+// the bearer token sent in their Authorization header. The tokens are read
+// from the file at path, which holds one token per line followed by the name
+// of its user and optionally a comma separated list of groups:
+//
+//	# token                 user   groups
+//	4f6c0e5b2a9d3c71e8a4    alice  team-a,ops
+//
+// Empty lines and lines starting with # are ignored.
+func NewTokenAuthenticator(path string) (Authenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	a := &tokenAuthenticator{}
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid token file %s line %d: expected TOKEN USER [GROUP[,GROUP...]]", path, n)
+		}
+		e := tokenEntry{token: fields[0], user: fields[1]}
+		if len(fields) == 3 {
+			e.groups = strings.Split(fields[2], ",")
+		}
+		a.entries = append(a.entries, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *tokenAuthenticator) Name() string {
+	return TokenMethod
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, nil
+	}
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return nil, nil
+	}
+	token := strings.TrimSpace(parts[1])
+
+	for _, e := range a.entries {
+		if subtle.ConstantTimeCompare([]byte(e.token), []byte(token)) == 1 {
+			return &Identity{
+				User:   e.user,
+				Groups: append([]string(nil), e.groups...),
+				Method: TokenMethod,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid bearer token")
+}
@@ -16,6 +16,9 @@ type Request struct {
 	// User holds the user extracted by AuthN mechanism
 	User string `json:"User,omitempty"`
 
+	// UserGroups holds the groups of the user extracted by AuthN mechanism
+	UserGroups []string `json:"UserGroups,omitempty"`
+
 	// UserAuthNMethod holds the mechanism used to extract user details (e.g., krb)
 	UserAuthNMethod string `json:"UserAuthNMethod,omitempty"`
 
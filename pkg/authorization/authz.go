@@ -30,10 +30,11 @@ const maxBodySize = 1048576 // 1MB
 // If multiple authZ plugins are specified, the block/allow decision is based on ANDing all plugin results
 // For response manipulation, the response from each plugin is piped between plugins. Plugin execution order
 // is determined according to daemon parameters
-func NewCtx(authZPlugins []Plugin, user, userAuthNMethod, requestMethod, requestURI string) *Ctx {
+func NewCtx(authZPlugins []Plugin, user string, userGroups []string, userAuthNMethod, requestMethod, requestURI string) *Ctx {
 	return &Ctx{
 		plugins:         authZPlugins,
 		user:            user,
+		userGroups:      userGroups,
 		userAuthNMethod: userAuthNMethod,
 		requestMethod:   requestMethod,
 		requestURI:      requestURI,
@@ -43,6 +44,7 @@ func NewCtx(authZPlugins []Plugin, user, userAuthNMethod, requestMethod, request
 // Ctx stores a a single request-response interaction context
 type Ctx struct {
 	user            string
+	userGroups      []string
 	userAuthNMethod string
 	requestMethod   string
 	requestURI      string
@@ -69,6 +71,7 @@ func (ctx *Ctx) AuthZRequest(w http.ResponseWriter, r *http.Request) error {
 
 	ctx.authReq = &Request{
 		User:            ctx.user,
+		UserGroups:      ctx.userGroups,
 		UserAuthNMethod: ctx.userAuthNMethod,
 		RequestMethod:   ctx.requestMethod,
 		RequestURI:      ctx.requestURI,
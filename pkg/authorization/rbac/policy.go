@@ -0,0 +1,191 @@
+package rbac
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// nameSegment is the route pattern segment matching the name or ID of the
+// resource targeted by a request.
+const nameSegment = "{name}"
+
+// Policy is the set of rules loaded from a policy file. A request is allowed
+// when at least one of the rules matches it.
+type Policy struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule grants a set of users and groups access to some API routes.
+type Rule struct {
+	// Name identifies the rule in denial messages and logs.
+	Name string `json:"name,omitempty"`
+	// Users holds the users the rule applies to, "*" matches any
+	// authenticated user.
+	Users []string `json:"users,omitempty"`
+	// Groups holds the groups the rule applies to.
+	Groups []string `json:"groups,omitempty"`
+	// Methods holds the allowed HTTP methods, all methods are allowed
+	// when it is empty.
+	Methods []string `json:"methods,omitempty"`
+	// Routes holds the allowed route patterns, e.g. /containers/{name}/exec.
+	// A "*" segment matches any path segment, a {name} segment matches the
+	// resource targeted by the request and "*" alone matches every route.
+	Routes []string `json:"routes"`
+	// Resources holds name patterns, in path.Match syntax, the resource
+	// targeted by the request must match.
+	Resources []string `json:"resources,omitempty"`
+	// Labels holds the labels the resource targeted by the request must
+	// have.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// LoadPolicy reads and validates the policy file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy %s: %v", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy %s: %v", path, err)
+	}
+	return p, nil
+}
+
+func (p *Policy) validate() error {
+	for i, r := range p.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("#%d", i)
+		}
+		if len(r.Users) == 0 && len(r.Groups) == 0 {
+			return fmt.Errorf("rule %s: no users or groups", r.Name)
+		}
+		if len(r.Routes) == 0 {
+			return fmt.Errorf("rule %s: no routes", r.Name)
+		}
+		for _, route := range r.Routes {
+			if route != "*" && !strings.HasPrefix(route, "/") {
+				return fmt.Errorf("rule %s: route %q must start with /", r.Name, route)
+			}
+		}
+		for _, pattern := range r.Resources {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %s: invalid resource pattern %q", r.Name, pattern)
+			}
+		}
+		for j, m := range r.Methods {
+			r.Methods[j] = strings.ToUpper(m)
+		}
+	}
+	return nil
+}
+
+// matchSubject returns whether the rule applies to the user or one of its
+// groups.
+func (r *Rule) matchSubject(user string, groups []string) bool {
+	if user == "" {
+		return false
+	}
+	for _, u := range r.Users {
+		if u == "*" || u == user {
+			return true
+		}
+	}
+	for _, g := range r.Groups {
+		for _, ug := range groups {
+			if g == ug {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchMethod(method string) bool {
+	if len(r.Methods) == 0 {
+		return true
+	}
+	for _, m := range r.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRoute returns whether the request path matches one of the rule's
+// routes, along with the resource reference captured by its {name}
+// segment, if any.
+func (r *Rule) matchRoute(p string) (bool, string) {
+	for _, route := range r.Routes {
+		if ok, ref := matchRoute(route, p); ok {
+			return true, ref
+		}
+	}
+	return false, ""
+}
+
+func matchRoute(route, p string) (bool, string) {
+	if route == "*" {
+		return true, ""
+	}
+	rs := strings.Split(strings.Trim(route, "/"), "/")
+	ps := strings.Split(strings.Trim(p, "/"), "/")
+	if len(rs) != len(ps) {
+		return false, ""
+	}
+	var ref string
+	for i := range rs {
+		switch rs[i] {
+		case "*":
+		case nameSegment:
+			ref = ps[i]
+		default:
+			if rs[i] != ps[i] {
+				return false, ""
+			}
+		}
+	}
+	return true, ref
+}
+
+// matchResource returns whether the resource satisfies the rule's resource
+// name patterns and labels. Rules without such constraints match any
+// request, including those without a resource.
+func (r *Rule) matchResource(res *Resource) bool {
+	if len(r.Resources) == 0 && len(r.Labels) == 0 {
+		return true
+	}
+	if res == nil {
+		return false
+	}
+	if len(r.Resources) > 0 {
+		name := strings.TrimPrefix(res.Name, "/")
+		matched := false
+		for _, pattern := range r.Resources {
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = true
+				break
+			}
+			if ok, _ := path.Match(pattern, res.ID); ok && res.ID != "" {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for k, v := range r.Labels {
+		if lv, ok := res.Labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
+}
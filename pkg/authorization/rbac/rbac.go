@@ -0,0 +1,183 @@
+// Package rbac implements a built-in authorization plugin granting access to
+// the API according to a role based policy file.
+package rbac
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/authorization"
+)
+
+// Name is the name the authorizer is registered with in the authorization
+// chain.
+const Name = "rbac"
+
+var versionPrefix = regexp.MustCompile(`^/v[0-9.]+/`)
+
+// Resource describes the object targeted by a request.
+type Resource struct {
+	ID     string
+	Name   string
+	Labels map[string]string
+}
+
+// Types of the resources referenced in request paths, named after the first
+// segment of their routes.
+const (
+	ContainerResource = "containers"
+	ExecResource      = "exec"
+	ImageResource     = "images"
+	NetworkResource   = "networks"
+	VolumeResource    = "volumes"
+)
+
+// Resolver looks up the resource of the given type referenced by name or ID
+// in a request path.
+type Resolver interface {
+	ResolveResource(resourceType, ref string) (*Resource, error)
+}
+
+// Authorizer is an authorization.Plugin evaluating requests against a
+// policy file.
+type Authorizer struct {
+	mu       sync.RWMutex
+	path     string
+	policy   *Policy
+	resolver Resolver
+}
+
+// NewAuthorizer returns an authorizer enforcing the policy file at path.
+func NewAuthorizer(path string) (*Authorizer, error) {
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Authorizer{path: path, policy: policy}, nil
+}
+
+// SetResolver sets the resolver used to look up the name and labels of the
+// resources referenced in request paths.
+func (a *Authorizer) SetResolver(r Resolver) {
+	a.mu.Lock()
+	a.resolver = r
+	a.mu.Unlock()
+}
+
+// Reload replaces the policy with the one read from path, or from the
+// current policy file if path is empty. The current policy is kept if the
+// new one can't be loaded.
+func (a *Authorizer) Reload(path string) error {
+	if path == "" {
+		a.mu.RLock()
+		path = a.path
+		a.mu.RUnlock()
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.path = path
+	a.policy = policy
+	a.mu.Unlock()
+	logrus.Infof("Loaded authorization policy %s with %d rules", path, len(policy.Rules))
+	return nil
+}
+
+// Name returns the name of the authorizer.
+func (a *Authorizer) Name() string {
+	return Name
+}
+
+// AuthZRequest allows the request if one of the policy rules matches it.
+func (a *Authorizer) AuthZRequest(req *authorization.Request) (*authorization.Response, error) {
+	u, err := url.ParseRequestURI(req.RequestURI)
+	if err != nil {
+		return nil, err
+	}
+	p := versionPrefix.ReplaceAllString(u.Path, "/")
+
+	a.mu.RLock()
+	policy, resolver := a.policy, a.resolver
+	a.mu.RUnlock()
+
+	resources := make(map[string]*Resource)
+	resource := func(ref string) *Resource {
+		res, ok := resources[ref]
+		if !ok {
+			res = lookupResource(resolver, p, ref, u.Query(), req.RequestBody)
+			resources[ref] = res
+		}
+		return res
+	}
+
+	for _, r := range policy.Rules {
+		if !r.matchSubject(req.User, req.UserGroups) || !r.matchMethod(req.RequestMethod) {
+			continue
+		}
+		ok, ref := r.matchRoute(p)
+		if !ok || !r.matchResource(resource(ref)) {
+			continue
+		}
+		logrus.Debugf("Request %s %s by %s allowed by rule %s", req.RequestMethod, p, req.User, r.Name)
+		return &authorization.Response{Allow: true}, nil
+	}
+
+	if req.User == "" {
+		return &authorization.Response{Msg: "anonymous requests are not allowed"}, nil
+	}
+	return &authorization.Response{Msg: fmt.Sprintf("%s %s is not allowed for user %s", req.RequestMethod, p, req.User)}, nil
+}
+
+// AuthZResponse allows every response, the decision is made on the request.
+func (a *Authorizer) AuthZResponse(req *authorization.Request) (*authorization.Response, error) {
+	return &authorization.Response{Allow: true}, nil
+}
+
+// lookupResource returns the resource targeted by a request. Resources
+// referenced in the path are looked up with the resolver according to the
+// type of the route, there is no resource for routes of other types.
+// Otherwise the resource is described by the name query parameter and the
+// labels in the request body, as when creating a container.
+func lookupResource(resolver Resolver, p, ref string, query url.Values, body []byte) *Resource {
+	if ref != "" {
+		resourceType := routeResourceType(p)
+		if resourceType == "" {
+			return nil
+		}
+		if resolver != nil {
+			if res, err := resolver.ResolveResource(resourceType, ref); err == nil {
+				return res
+			}
+		}
+		return &Resource{Name: ref}
+	}
+
+	var config struct {
+		Labels map[string]string
+	}
+	if len(body) > 0 {
+		json.Unmarshal(body, &config)
+	}
+	name := query.Get("name")
+	if name == "" && config.Labels == nil {
+		return nil
+	}
+	return &Resource{Name: name, Labels: config.Labels}
+}
+
+// routeResourceType returns the type of the resources referenced in the
+// request path p, or an empty string if it is not a known type.
+func routeResourceType(p string) string {
+	switch t := strings.SplitN(strings.TrimPrefix(p, "/"), "/", 2)[0]; t {
+	case ContainerResource, ExecResource, ImageResource, NetworkResource, VolumeResource:
+		return t
+	}
+	return ""
+}
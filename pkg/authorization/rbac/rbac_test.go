@@ -0,0 +1,189 @@
+package rbac
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/pkg/authorization"
+)
+
+const testPolicy = `{
+	"rules": [
+		{
+			"name": "admins",
+			"groups": ["admin"],
+			"routes": ["*"]
+		},
+		{
+			"name": "read-only",
+			"users": ["*"],
+			"methods": ["get", "head"],
+			"routes": ["/containers/json", "/containers/{name}/json"]
+		},
+		{
+			"name": "team-a-exec",
+			"groups": ["team-a"],
+			"methods": ["POST"],
+			"routes": ["/containers/{name}/exec", "/exec/{name}/start"],
+			"labels": {"team": "a"}
+		},
+		{
+			"name": "team-a-networks",
+			"groups": ["team-a"],
+			"routes": ["/networks/{name}", "/plugins/{name}"],
+			"labels": {"team": "a"}
+		},
+		{
+			"name": "team-a-create",
+			"groups": ["team-a"],
+			"methods": ["POST"],
+			"routes": ["/containers/create"],
+			"resources": ["team-a-*"],
+			"labels": {"team": "a"}
+		}
+	]
+}`
+
+type testResolver map[string]*Resource
+
+func (r testResolver) ResolveResource(resourceType, ref string) (*Resource, error) {
+	if res, ok := r[resourceType+"/"+ref]; ok {
+		return res, nil
+	}
+	return nil, fmt.Errorf("No such %s: %s", resourceType, ref)
+}
+
+func writePolicy(t *testing.T, dir, policy string) string {
+	path := filepath.Join(dir, "policy.json")
+	if err := ioutil.WriteFile(path, []byte(policy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAuthorizer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbac-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := NewAuthorizer(writePolicy(t, dir, testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.SetResolver(testResolver{
+		"containers/web-a": {ID: "abc123", Name: "/web-a", Labels: map[string]string{"team": "a"}},
+		"containers/web-b": {ID: "def456", Name: "/web-b", Labels: map[string]string{"team": "b"}},
+		"exec/e1f2a3b4":    {ID: "abc123", Name: "/web-a", Labels: map[string]string{"team": "a"}},
+		"networks/net-a":   {ID: "fed987", Name: "net-a", Labels: map[string]string{"team": "a"}},
+		"networks/web-a":   {ID: "cba654", Name: "web-a"},
+	})
+
+	cases := []struct {
+		user   string
+		groups []string
+		method string
+		uri    string
+		body   string
+		allow  bool
+	}{
+		{"", nil, "GET", "/v1.24/containers/json", "", false},
+		{"root", []string{"admin"}, "DELETE", "/v1.24/containers/web-b", "", true},
+		{"bob", nil, "GET", "/v1.24/containers/json?all=1", "", true},
+		{"bob", nil, "GET", "/containers/web-b/json", "", true},
+		{"bob", nil, "DELETE", "/containers/web-b", "", false},
+		{"bob", nil, "POST", "/containers/web-a/exec", "", false},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/web-a/exec", "", true},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/web-b/exec", "", false},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/unknown/exec", "", false},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/exec/e1f2a3b4/start", "", true},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/exec/web-a/start", "", false},
+		{"alice", []string{"team-a"}, "DELETE", "/v1.24/networks/net-a", "", true},
+		{"alice", []string{"team-a"}, "DELETE", "/v1.24/networks/web-a", "", false},
+		{"alice", []string{"team-a"}, "DELETE", "/v1.24/plugins/web-a", "", false},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/web-a/kill", "", false},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/create?name=team-a-web", `{"Image":"busybox","Labels":{"team":"a"}}`, true},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/create?name=team-a-web", `{"Image":"busybox","Labels":{"team":"b"}}`, false},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/create?name=web", `{"Image":"busybox","Labels":{"team":"a"}}`, false},
+		{"alice", []string{"team-a"}, "POST", "/v1.24/containers/create", `{"Image":"busybox"}`, false},
+	}
+
+	for _, c := range cases {
+		req := &authorization.Request{
+			User:          c.user,
+			UserGroups:    c.groups,
+			RequestMethod: c.method,
+			RequestURI:    c.uri,
+			RequestBody:   []byte(c.body),
+		}
+		res, err := a.AuthZRequest(req)
+		if err != nil {
+			t.Fatalf("%s %s by %s: %v", c.method, c.uri, c.user, err)
+		}
+		if res.Allow != c.allow {
+			t.Fatalf("%s %s by %s: expected allow=%v, got %v (%s)", c.method, c.uri, c.user, c.allow, res.Allow, res.Msg)
+		}
+		if !res.Allow && res.Msg == "" {
+			t.Fatalf("%s %s by %s: expected a denial message", c.method, c.uri, c.user)
+		}
+	}
+}
+
+func TestAuthorizerReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbac-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writePolicy(t, dir, `{"rules": [{"users": ["alice"], "routes": ["/info"]}]}`)
+	a, err := NewAuthorizer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &authorization.Request{User: "bob", RequestMethod: "GET", RequestURI: "/v1.24/info"}
+	if res, _ := a.AuthZRequest(req); res.Allow {
+		t.Fatal("expected bob to be denied")
+	}
+
+	writePolicy(t, dir, `{"rules": [{"users": ["alice", "bob"], "routes": ["/info"]}]}`)
+	if err := a.Reload(""); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := a.AuthZRequest(req); !res.Allow {
+		t.Fatalf("expected bob to be allowed after reload: %s", res.Msg)
+	}
+
+	// An invalid policy keeps the current one
+	writePolicy(t, dir, `{"rules": [{"routes": ["/info"]}]}`)
+	if err := a.Reload(""); err == nil {
+		t.Fatal("expected an error reloading a rule without users or groups")
+	}
+	if res, _ := a.AuthZRequest(req); !res.Allow {
+		t.Fatalf("expected the previous policy to be kept: %s", res.Msg)
+	}
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbac-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, policy := range []string{
+		`{"rules": [`,
+		`{"rules": [{"users": ["alice"]}]}`,
+		`{"rules": [{"users": ["alice"], "routes": ["info"]}]}`,
+		`{"rules": [{"users": ["alice"], "routes": ["*"], "resources": ["[a-"]}]}`,
+	} {
+		if _, err := LoadPolicy(writePolicy(t, dir, policy)); err == nil {
+			t.Fatalf("expected an error loading %s", policy)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package idtools
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Pool allocates ranges of the same size from the subordinate user and
+// group IDs of a user and group, so that each user namespace created from a
+// range maps to distinct host IDs.
+type Pool struct {
+	mu        sync.Mutex
+	size      int
+	uidRanges ranges
+	gidRanges ranges
+	slots     int
+	used      map[int]bool
+}
+
+// NewPool returns a pool of ranges of size IDs taken from the subuid and
+// subgid ranges of username and groupname, as listed in /etc/subuid and
+// /etc/subgid.
+func NewPool(username, groupname string, size int) (*Pool, error) {
+	subuidRanges, err := parseSubuid(username)
+	if err != nil {
+		return nil, err
+	}
+	subgidRanges, err := parseSubgid(groupname)
+	if err != nil {
+		return nil, err
+	}
+	return newPool(subuidRanges, subgidRanges, size)
+}
+
+func newPool(subuidRanges, subgidRanges ranges, size int) (*Pool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("Invalid ID range size %d", size)
+	}
+	sort.Sort(subuidRanges)
+	sort.Sort(subgidRanges)
+	p := &Pool{
+		size:      size,
+		uidRanges: subuidRanges,
+		gidRanges: subgidRanges,
+		used:      make(map[int]bool),
+	}
+	p.slots = slotCount(subuidRanges, size)
+	if n := slotCount(subgidRanges, size); n < p.slots {
+		p.slots = n
+	}
+	if p.slots == 0 {
+		return nil, fmt.Errorf("No subuid and subgid ranges of at least %d IDs found", size)
+	}
+	return p, nil
+}
+
+// slotCount returns how many ranges of size IDs fit in subidRanges.
+func slotCount(subidRanges ranges, size int) int {
+	n := 0
+	for _, r := range subidRanges {
+		n += r.Length / size
+	}
+	return n
+}
+
+// slotStart returns the first host ID of the slot-th range of size IDs in
+// subidRanges.
+func slotStart(subidRanges ranges, size, slot int) int {
+	for _, r := range subidRanges {
+		n := r.Length / size
+		if slot < n {
+			return r.Start + slot*size
+		}
+		slot -= n
+	}
+	return -1
+}
+
+// Size returns the number of IDs of the ranges allocated by the pool.
+func (p *Pool) Size() int {
+	return p.size
+}
+
+func (p *Pool) maps(slot int) ([]IDMap, []IDMap) {
+	uidMaps := []IDMap{{ContainerID: 0, HostID: slotStart(p.uidRanges, p.size, slot), Size: p.size}}
+	gidMaps := []IDMap{{ContainerID: 0, HostID: slotStart(p.gidRanges, p.size, slot), Size: p.size}}
+	return uidMaps, gidMaps
+}
+
+// slot returns the slot of the pool the maps were allocated from.
+func (p *Pool) slot(uidMaps, gidMaps []IDMap) (int, error) {
+	if len(uidMaps) == 1 && len(gidMaps) == 1 {
+		for slot := 0; slot < p.slots; slot++ {
+			u, g := p.maps(slot)
+			if uidMaps[0] == u[0] && gidMaps[0] == g[0] {
+				return slot, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("The ID mappings %v and %v are not ranges of the pool", uidMaps, gidMaps)
+}
+
+// Allocate returns the user and group ID mappings of a range which isn't
+// used yet.
+func (p *Pool) Allocate() ([]IDMap, []IDMap, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for slot := 0; slot < p.slots; slot++ {
+		if !p.used[slot] {
+			p.used[slot] = true
+			uidMaps, gidMaps := p.maps(slot)
+			return uidMaps, gidMaps, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("All the %d ID ranges of the pool are in use", p.slots)
+}
+
+// Reserve marks the range of the mappings, previously returned by
+// Allocate, as used.
+func (p *Pool) Reserve(uidMaps, gidMaps []IDMap) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slot, err := p.slot(uidMaps, gidMaps)
+	if err != nil {
+		return err
+	}
+	if p.used[slot] {
+		return fmt.Errorf("The ID mappings %v and %v are already in use", uidMaps, gidMaps)
+	}
+	p.used[slot] = true
+	return nil
+}
+
+// Release makes the range of the mappings available again.
+func (p *Pool) Release(uidMaps, gidMaps []IDMap) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if slot, err := p.slot(uidMaps, gidMaps); err == nil {
+		delete(p.used, slot)
+	}
+}
@@ -0,0 +1,73 @@
+package idtools
+
+import "testing"
+
+func TestPoolAllocate(t *testing.T) {
+	uids := ranges{{Start: 300000, Length: 65536}, {Start: 100000, Length: 150000}}
+	gids := ranges{{Start: 100000, Length: 1000000}}
+	p, err := newPool(uids, gids, 65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The uid ranges hold 3 slots, less than the gid ranges.
+	expected := []int{100000, 165536, 300000}
+	var allocated [][]IDMap
+	for i, start := range expected {
+		uidMaps, gidMaps, err := p.Allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(uidMaps) != 1 || uidMaps[0] != (IDMap{ContainerID: 0, HostID: start, Size: 65536}) {
+			t.Fatalf("Expected the uid range starting at %d, got %v", start, uidMaps)
+		}
+		if len(gidMaps) != 1 || gidMaps[0] != (IDMap{ContainerID: 0, HostID: 100000 + i*65536, Size: 65536}) {
+			t.Fatalf("Expected the gid range starting at %d, got %v", 100000+i*65536, gidMaps)
+		}
+		allocated = append(allocated, uidMaps, gidMaps)
+	}
+	if _, _, err := p.Allocate(); err == nil {
+		t.Fatal("Expected an error allocating from an exhausted pool")
+	}
+
+	p.Release(allocated[2], allocated[3])
+	uidMaps, _, err := p.Allocate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uidMaps[0].HostID != 165536 {
+		t.Fatalf("Expected the released range to be allocated again, got %v", uidMaps)
+	}
+}
+
+func TestPoolReserve(t *testing.T) {
+	p, err := newPool(ranges{{Start: 100000, Length: 131072}}, ranges{{Start: 100000, Length: 131072}}, 65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uidMaps := []IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}}
+	gidMaps := []IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}}
+	if err := p.Reserve(uidMaps, gidMaps); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Reserve(uidMaps, gidMaps); err == nil {
+		t.Fatal("Expected an error reserving a range twice")
+	}
+	if err := p.Reserve([]IDMap{{ContainerID: 0, HostID: 100001, Size: 65536}}, gidMaps); err == nil {
+		t.Fatal("Expected an error reserving a range which isn't in the pool")
+	}
+
+	allocated, _, err := p.Allocate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allocated[0].HostID != 165536 {
+		t.Fatalf("Expected the range following the reserved one, got %v", allocated)
+	}
+}
+
+func TestPoolTooSmall(t *testing.T) {
+	if _, err := newPool(ranges{{Start: 100000, Length: 65535}}, ranges{{Start: 100000, Length: 65536}}, 65536); err == nil {
+		t.Fatal("Expected an error creating a pool without a complete range")
+	}
+}
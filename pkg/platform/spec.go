@@ -0,0 +1,57 @@
+package platform
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Spec is the operating system and CPU architecture an image is built for,
+// in the `os/arch[/variant]` format.
+type Spec struct {
+	OS           string
+	Architecture string
+	// Variant is an optional variant of the CPU architecture, for example
+	// `v7` for `arm`.
+	Variant string
+}
+
+// DefaultSpec returns the platform of the running process.
+func DefaultSpec() Spec {
+	return Spec{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+}
+
+// Parse parses a platform in the `os/arch[/variant]` format.
+func Parse(s string) (Spec, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Spec{}, fmt.Errorf("invalid platform %q, expected os/arch[/variant]", s)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return Spec{}, fmt.Errorf("invalid platform %q, expected os/arch[/variant]", s)
+		}
+	}
+	p := Spec{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+func (p Spec) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// Matches reports whether os, arch and variant are the platform's. The
+// variant is only compared if the platform specifies one.
+func (p Spec) Matches(os, arch, variant string) bool {
+	if os != p.OS || arch != p.Architecture {
+		return false
+	}
+	return p.Variant == "" || variant == p.Variant
+}
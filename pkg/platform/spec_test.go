@@ -0,0 +1,52 @@
+package platform
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	valid := map[string]Spec{
+		"linux/amd64":    {OS: "linux", Architecture: "amd64"},
+		"linux/arm/v7":   {OS: "linux", Architecture: "arm", Variant: "v7"},
+		"windows/amd64":  {OS: "windows", Architecture: "amd64"},
+		"linux/arm64/v8": {OS: "linux", Architecture: "arm64", Variant: "v8"},
+	}
+	for s, expected := range valid {
+		p, err := Parse(s)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", s, err)
+		}
+		if p != expected {
+			t.Fatalf("parsing %q: expected %+v, got %+v", s, expected, p)
+		}
+		if p.String() != s {
+			t.Fatalf("expected %q to round trip, got %q", s, p.String())
+		}
+	}
+
+	invalid := []string{"", "linux", "linux/", "/amd64", "linux/arm/", "linux/arm/v7/extra"}
+	for _, s := range invalid {
+		if _, err := Parse(s); err == nil {
+			t.Fatalf("expected an error parsing %q", s)
+		}
+	}
+}
+
+func TestSpecMatches(t *testing.T) {
+	if !(Spec{OS: "linux", Architecture: "arm"}).Matches("linux", "arm", "v7") {
+		t.Fatal("a platform without a variant should match any variant")
+	}
+	if !(Spec{OS: "linux", Architecture: "arm", Variant: "v7"}).Matches("linux", "arm", "v7") {
+		t.Fatal("expected the same variant to match")
+	}
+	if (Spec{OS: "linux", Architecture: "arm", Variant: "v6"}).Matches("linux", "arm", "v7") {
+		t.Fatal("expected a different variant not to match")
+	}
+	if (Spec{OS: "linux", Architecture: "arm", Variant: "v7"}).Matches("linux", "arm", "") {
+		t.Fatal("expected a platform with a variant not to match an image without one")
+	}
+	if (Spec{OS: "linux", Architecture: "amd64"}).Matches("linux", "arm", "v7") {
+		t.Fatal("expected a different architecture not to match")
+	}
+	if (Spec{OS: "windows", Architecture: "arm"}).Matches("linux", "arm", "v7") {
+		t.Fatal("expected a different operating system not to match")
+	}
+}
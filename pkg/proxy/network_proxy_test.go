@@ -157,6 +157,40 @@ func TestTCPDualStackProxy(t *testing.T) {
 	testProxyAt(t, "tcp", proxy, ipv4ProxyAddr.String())
 }
 
+func TestTCP4ProxyDualStack(t *testing.T) {
+	if l, err := net.Listen("tcp6", "[::1]:0"); err != nil {
+		t.Skip("IPv6 is not available")
+	} else {
+		l.Close()
+	}
+	backend := NewEchoServer(t, "tcp", "127.0.0.1:0")
+	defer backend.Close()
+	backend.Run()
+	proxy, err := NewProxy(&net.TCPAddr{IP: net.IPv4zero, Port: 0}, backend.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProxyAt(t, "tcp", proxy, fmt.Sprintf("[::1]:%d", proxy.FrontendAddr().(*net.TCPAddr).Port))
+}
+
+func TestTCP4And6SingleStackProxySamePort(t *testing.T) {
+	backend := NewEchoServer(t, "tcp", "127.0.0.1:0")
+	defer backend.Close()
+	backend.Run()
+	proxy4, err := NewSingleStackProxy(&net.TCPAddr{IP: net.IPv4zero, Port: 0}, backend.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := proxy4.FrontendAddr().(*net.TCPAddr).Port
+	proxy6, err := NewSingleStackProxy(&net.TCPAddr{IP: net.IPv6unspecified, Port: port}, backend.LocalAddr())
+	if err != nil {
+		proxy4.Close()
+		t.Fatal(err)
+	}
+	defer proxy6.Close()
+	testProxyAt(t, "tcp", proxy4, fmt.Sprintf("127.0.0.1:%d", port))
+}
+
 func TestUDP4Proxy(t *testing.T) {
 	backend := NewEchoServer(t, "udp", "127.0.0.1:0")
 	defer backend.Close()
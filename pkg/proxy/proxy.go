@@ -35,3 +35,33 @@ func NewProxy(frontendAddr, backendAddr net.Addr) (Proxy, error) {
 		panic(fmt.Errorf("Unsupported protocol"))
 	}
 }
+
+// NewSingleStackProxy creates a Proxy like NewProxy, whose frontend only
+// accepts the connections of the address family of frontendAddr, even when
+// it is an unspecified address. It lets two proxies serve the IPv4 and IPv6
+// frontends of the same port.
+func NewSingleStackProxy(frontendAddr, backendAddr net.Addr) (Proxy, error) {
+	switch addr := frontendAddr.(type) {
+	case *net.UDPAddr:
+		return newUDPProxy(ListenNetwork("udp", addr.IP, true), addr, backendAddr.(*net.UDPAddr))
+	case *net.TCPAddr:
+		return newTCPProxy(ListenNetwork("tcp", addr.IP, true), addr, backendAddr.(*net.TCPAddr))
+	default:
+		panic(fmt.Errorf("Unsupported protocol"))
+	}
+}
+
+// ListenNetwork returns the network of a listener of proto on ip. A single
+// stack listener is restricted to the address family of ip, otherwise a
+// listener on the unspecified IPv4 address also accepts IPv6 connections on
+// dual-stack hosts.
+func ListenNetwork(proto string, ip net.IP, singleStack bool) string {
+	switch {
+	case !singleStack || ip == nil:
+		return proto
+	case ip.To4() != nil:
+		return proto + "4"
+	default:
+		return proto + "6"
+	}
+}
@@ -18,7 +18,11 @@ type TCPProxy struct {
 
 // NewTCPProxy creates a new TCPProxy.
 func NewTCPProxy(frontendAddr, backendAddr *net.TCPAddr) (*TCPProxy, error) {
-	listener, err := net.ListenTCP("tcp", frontendAddr)
+	return newTCPProxy("tcp", frontendAddr, backendAddr)
+}
+
+func newTCPProxy(network string, frontendAddr, backendAddr *net.TCPAddr) (*TCPProxy, error) {
+	listener, err := net.ListenTCP(network, frontendAddr)
 	if err != nil {
 		return nil, err
 	}
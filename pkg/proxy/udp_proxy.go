@@ -56,7 +56,11 @@ type UDPProxy struct {
 
 // NewUDPProxy creates a new UDPProxy.
 func NewUDPProxy(frontendAddr, backendAddr *net.UDPAddr) (*UDPProxy, error) {
-	listener, err := net.ListenUDP("udp", frontendAddr)
+	return newUDPProxy("udp", frontendAddr, backendAddr)
+}
+
+func newUDPProxy(network string, frontendAddr, backendAddr *net.UDPAddr) (*UDPProxy, error) {
+	listener, err := net.ListenUDP(network, frontendAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,43 @@
+// Package rootless runs the daemon as an unprivileged user, as the root of
+// user, mount and network namespaces it creates.
+package rootless
+
+import "os"
+
+// stateEnv is the environment variable tracking the executions of the daemon
+// setting up the namespaces.
+const stateEnv = "_DOCKER_ROOTLESS_STATE"
+
+const (
+	// stateChild is the state of the daemon started in the namespaces,
+	// waiting for the ID mappings of the user namespace to be set.
+	stateChild = "child"
+	// stateMapped is the state of the daemon executed again as the root of
+	// the user namespace, with its capabilities.
+	stateMapped = "mapped"
+	// stateReady is the state of the daemon once the namespaces are set up.
+	stateReady = "ready"
+)
+
+// Options are the options of the namespaces of a rootless daemon.
+type Options struct {
+	// Slirp4netns is the path of the slirp4netns binary connecting the
+	// network namespace of the daemon to the network of the host. The
+	// network namespace is isolated from the host if it is empty.
+	Slirp4netns string
+	// MTU is the MTU of the tap device created by slirp4netns.
+	MTU int
+}
+
+// Running reports whether the process is a daemon running in the
+// namespaces set up by Run and Init.
+func Running() bool {
+	return os.Getenv(stateEnv) == stateReady
+}
+
+// InNamespaces reports whether the process is a daemon started by Run whose
+// namespaces are not set up yet, and which must call Init.
+func InNamespaces() bool {
+	state := os.Getenv(stateEnv)
+	return state == stateChild || state == stateMapped
+}
@@ -0,0 +1,214 @@
+package rootless
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/opencontainers/runc/libcontainer/user"
+)
+
+// maxSubordinateMaps is the number of subordinate ID ranges mapped in the
+// user namespace, the kernel only accepting five lines in the uid_map and
+// gid_map files of older versions, one of them mapping the user to root.
+const maxSubordinateMaps = 4
+
+// Run starts the daemon again, with the arguments of the current process, in
+// new user, mount and network namespaces. The user running the daemon is
+// mapped to root in the user namespace, and its subordinate IDs, as listed
+// in /etc/subuid and /etc/subgid, to the following IDs. Run forwards the
+// signals it receives to the daemon, waits for it to exit and returns its
+// exit code.
+func Run(opts Options) (int, error) {
+	u, err := user.LookupUid(os.Getuid())
+	if err != nil {
+		return -1, err
+	}
+	g, err := user.LookupGid(os.Getgid())
+	if err != nil {
+		return -1, err
+	}
+	uidMaps, gidMaps, err := idtools.CreateIDMappings(u.Name, g.Name)
+	if err != nil {
+		return -1, fmt.Errorf("Can't read the subordinate IDs of %s:%s: %v", u.Name, g.Name, err)
+	}
+
+	// The daemon waits for the pipe to be closed before using the
+	// namespaces.
+	r, w, err := os.Pipe()
+	if err != nil {
+		return -1, err
+	}
+	defer w.Close()
+
+	cmd := exec.Command("/proc/self/exe", os.Args[1:]...)
+	cmd.Args[0] = os.Args[0]
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), stateEnv+"="+stateChild)
+	cmd.ExtraFiles = []*os.File{r}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET,
+		Pdeathsig:  syscall.SIGKILL,
+	}
+	err = cmd.Start()
+	r.Close()
+	if err != nil {
+		return -1, err
+	}
+
+	if err := setupNamespaces(cmd.Process.Pid, os.Getuid(), os.Getgid(), uidMaps, gidMaps, opts); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return -1, err
+	}
+	w.Close()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP, syscall.SIGUSR1)
+	go func() {
+		for s := range c {
+			cmd.Process.Signal(s)
+		}
+	}()
+	defer signal.Stop(c)
+
+	if err := cmd.Wait(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				return status.ExitStatus(), nil
+			}
+		}
+		return -1, err
+	}
+	return 0, nil
+}
+
+// setupNamespaces sets the ID mappings of the user namespace of the process
+// pid and, if slirp4netns is used, connects its network namespace to the
+// network of the host.
+func setupNamespaces(pid, uid, gid int, uidMaps, gidMaps []idtools.IDMap, opts Options) error {
+	if out, err := exec.Command("newuidmap", mappingArgs(pid, uid, uidMaps)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("Error setting the uid mappings: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	if out, err := exec.Command("newgidmap", mappingArgs(pid, gid, gidMaps)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("Error setting the gid mappings: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	if opts.Slirp4netns != "" {
+		return startSlirp4netns(opts.Slirp4netns, pid, opts.MTU)
+	}
+	return nil
+}
+
+// mappingArgs returns the arguments of newuidmap and newgidmap mapping id to
+// root and the subordinate IDs of maps to the following IDs in the user
+// namespace of the process pid.
+func mappingArgs(pid, id int, maps []idtools.IDMap) []string {
+	args := []string{strconv.Itoa(pid), "0", strconv.Itoa(id), "1"}
+	if len(maps) > maxSubordinateMaps {
+		maps = maps[:maxSubordinateMaps]
+	}
+	for _, m := range maps {
+		args = append(args, strconv.Itoa(m.ContainerID+1), strconv.Itoa(m.HostID), strconv.Itoa(m.Size))
+	}
+	return args
+}
+
+// startSlirp4netns starts slirp4netns, which runs until the process pid
+// exits, and waits for it to configure the tap0 device of the network
+// namespace of pid.
+func startSlirp4netns(path string, pid, mtu int) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	args := []string{"--configure", "--disable-host-loopback", "--ready-fd=3"}
+	if mtu != 0 {
+		args = append(args, "--mtu="+strconv.Itoa(mtu))
+	}
+	cmd := exec.Command(path, append(args, strconv.Itoa(pid), "tap0")...)
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{w}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return fmt.Errorf("Error starting slirp4netns: %v", err)
+	}
+	go cmd.Wait()
+
+	if _, err := r.Read(make([]byte, 1)); err != nil {
+		return fmt.Errorf("slirp4netns failed to configure the network namespace: %v", err)
+	}
+	return nil
+}
+
+// Init finishes setting up the namespaces of a daemon started by Run. The
+// daemon is started without capabilities in the user namespace, as its user
+// isn't mapped yet, so Init executes it again once the mappings are set. It
+// then replaces the run directory, which only root can write to, with a
+// tmpfs private to the mount namespace.
+func Init() error {
+	switch os.Getenv(stateEnv) {
+	case stateChild:
+		pipe := os.NewFile(3, "rootless-sync")
+		_, err := io.Copy(ioutil.Discard, pipe)
+		pipe.Close()
+		if err != nil {
+			return err
+		}
+		if err := os.Setenv(stateEnv, stateMapped); err != nil {
+			return err
+		}
+		return syscall.Exec("/proc/self/exe", os.Args, os.Environ())
+	case stateMapped:
+		if err := setupRunDir(); err != nil {
+			return fmt.Errorf("Error setting up the run directory: %v", err)
+		}
+		return os.Setenv(stateEnv, stateReady)
+	}
+	return nil
+}
+
+// setupRunDir mounts a tmpfs on the run directory, keeping the runtime
+// directory of the user visible when it's under it.
+func setupRunDir() error {
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return err
+	}
+	runDir, err := filepath.EvalSymlinks("/var/run")
+	if err != nil {
+		return err
+	}
+
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" || !strings.HasPrefix(runtimeDir, runDir+"/") {
+		return syscall.Mount("tmpfs", runDir, "tmpfs", 0, "mode=0755")
+	}
+
+	stash, err := ioutil.TempDir("", "docker-rootless")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(stash)
+	if err := syscall.Mount(runtimeDir, stash, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", runDir, "tmpfs", 0, "mode=0755"); err != nil {
+		syscall.Unmount(stash, syscall.MNT_DETACH)
+		return err
+	}
+	if err := os.MkdirAll(runtimeDir, 0700); err != nil {
+		return err
+	}
+	return syscall.Mount(stash, runtimeDir, "", syscall.MS_MOVE, "")
+}
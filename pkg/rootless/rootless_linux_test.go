@@ -0,0 +1,30 @@
+package rootless
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
+)
+
+func TestMappingArgs(t *testing.T) {
+	maps := []idtools.IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 65536},
+		{ContainerID: 65536, HostID: 300000, Size: 1000},
+	}
+	expected := []string{"42", "0", "1000", "1", "1", "100000", "65536", "65537", "300000", "1000"}
+	if args := mappingArgs(42, 1000, maps); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Expected %v, got %v", expected, args)
+	}
+}
+
+func TestMappingArgsTooManyRanges(t *testing.T) {
+	var maps []idtools.IDMap
+	for i := 0; i < 6; i++ {
+		maps = append(maps, idtools.IDMap{ContainerID: i * 10, HostID: 100000 + i*100, Size: 10})
+	}
+	// the pid, the mapping of the user and 4 subordinate ranges
+	if args := mappingArgs(42, 1000, maps); len(args) != 1+3+3*maxSubordinateMaps {
+		t.Fatalf("Expected %d subordinate ranges to be mapped, got %v", maxSubordinateMaps, args)
+	}
+}
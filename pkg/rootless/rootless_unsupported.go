@@ -0,0 +1,15 @@
+// +build !linux
+
+package rootless
+
+import "fmt"
+
+// Run is not supported on this platform.
+func Run(opts Options) (int, error) {
+	return -1, fmt.Errorf("Rootless daemons are only supported on Linux")
+}
+
+// Init is not supported on this platform.
+func Init() error {
+	return fmt.Errorf("Rootless daemons are only supported on Linux")
+}
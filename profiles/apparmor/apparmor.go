@@ -5,6 +5,7 @@ package apparmor
 import (
 	"bufio"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"strings"
@@ -34,9 +35,9 @@ type profileData struct {
 	Version int
 }
 
-// generateDefault creates an apparmor profile from ProfileData.
-func (p *profileData) generateDefault(out io.Writer) error {
-	compiled, err := templates.NewParse("apparmor_profile", baseTemplate)
+// generate creates an apparmor profile from ProfileData and the template tmpl.
+func (p *profileData) generate(out io.Writer, tmpl string) error {
+	compiled, err := templates.NewParse("apparmor_profile", tmpl)
 	if err != nil {
 		return err
 	}
@@ -63,6 +64,22 @@ func macroExists(m string) bool {
 // InstallDefault generates a default profile and installs it in the
 // ProfileDirectory with `apparmor_parser`.
 func InstallDefault(name string) error {
+	return InstallTemplate(name, baseTemplate)
+}
+
+// ValidateTemplate checks that tmpl can be used to generate a profile, it
+// has the same fields available as the default template.
+func ValidateTemplate(tmpl string) error {
+	p := profileData{
+		Name: "validate",
+	}
+	return p.generate(ioutil.Discard, tmpl)
+}
+
+// InstallTemplate generates a profile from the template tmpl and installs it
+// in the ProfileDirectory with `apparmor_parser`. The installed profile is
+// left untouched if the generated one can't be loaded.
+func InstallTemplate(name, tmpl string) error {
 	// Make sure the path where they want to save the profile exists
 	if err := os.MkdirAll(profileDirectory, 0755); err != nil {
 		return err
@@ -72,21 +89,27 @@ func InstallDefault(name string) error {
 		Name: name,
 	}
 
-	f, err := os.OpenFile(defaultProfilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	f, err := ioutil.TempFile(profileDirectory, ".docker-")
 	if err != nil {
 		return err
 	}
-	if err := p.generateDefault(f); err != nil {
+	if err := p.generate(f, tmpl); err != nil {
 		f.Close()
+		os.Remove(f.Name())
 		return err
 	}
 	f.Close()
 
-	if err := aaparser.LoadProfile(defaultProfilePath); err != nil {
+	if err := aaparser.LoadProfile(f.Name()); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
 		return err
 	}
 
-	return nil
+	return os.Rename(f.Name(), defaultProfilePath)
 }
 
 // IsLoaded checks if a passed profile has been loaded into the kernel.
@@ -0,0 +1,114 @@
+// +build linux
+
+package seccomp
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/docker/engine-api/types"
+)
+
+const (
+	// AuditSeccomp is the type of the audit records of the syscalls logged
+	// by a seccomp filter.
+	AuditSeccomp = 1326
+	// actLogCode is the code of the audit records of the syscalls allowed
+	// by the SCMP_ACT_LOG action.
+	actLogCode = 0x7ffc0000
+)
+
+var (
+	auditPID     = regexp.MustCompile(` pid=([0-9]+) `)
+	auditArch    = regexp.MustCompile(` arch=([0-9a-f]+) `)
+	auditSyscall = regexp.MustCompile(` syscall=([0-9]+) `)
+	auditCode    = regexp.MustCompile(` code=0x([0-9a-f]+)`)
+)
+
+// AuditRecord is a syscall logged by the kernel for a process running with a
+// seccomp profile in learning mode.
+type AuditRecord struct {
+	PID     int
+	Arch    uint32
+	Syscall int
+}
+
+// LearnProfile returns the profile logging every syscall, run by the
+// containers learning their profile.
+func LearnProfile() *types.Seccomp {
+	return &types.Seccomp{
+		DefaultAction: types.ActLog,
+		Architectures: learnArches(),
+	}
+}
+
+// GenerateProfile returns a profile allowing only the syscalls, in the
+// format of the default profile.
+func GenerateProfile(syscalls []string) *types.Seccomp {
+	names := make([]string, len(syscalls))
+	copy(names, syscalls)
+	sort.Strings(names)
+
+	profile := &types.Seccomp{
+		DefaultAction: types.ActErrno,
+		Architectures: learnArches(),
+		Syscalls:      []*types.Syscall{},
+	}
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		profile.Syscalls = append(profile.Syscalls, &types.Syscall{
+			Name:   name,
+			Action: types.ActAllow,
+			Args:   []*types.Arg{},
+		})
+	}
+	return profile
+}
+
+func learnArches() []types.Arch {
+	if DefaultProfile == nil {
+		return []types.Arch{}
+	}
+	return DefaultProfile.Architectures
+}
+
+// ParseAuditRecord parses the message of an AuditSeccomp record logging a
+// syscall allowed by the SCMP_ACT_LOG action, such as:
+//
+//	audit(1476781234.123:45): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=1234 comm="sh" exe="/bin/busybox" sig=0 arch=c000003e syscall=59 compat=0 ip=0x4d1c87 code=0x7ffc0000
+//
+// It returns false if the message isn't such a record.
+func ParseAuditRecord(msg string) (*AuditRecord, bool) {
+	code, ok := parseAuditField(auditCode, msg, 16)
+	if !ok || code != actLogCode {
+		return nil, false
+	}
+	pid, ok := parseAuditField(auditPID, msg, 10)
+	if !ok {
+		return nil, false
+	}
+	arch, ok := parseAuditField(auditArch, msg, 16)
+	if !ok {
+		return nil, false
+	}
+	nr, ok := parseAuditField(auditSyscall, msg, 10)
+	if !ok {
+		return nil, false
+	}
+	return &AuditRecord{PID: int(pid), Arch: uint32(arch), Syscall: int(nr)}, true
+}
+
+func parseAuditField(field *regexp.Regexp, msg string, base int) (uint64, bool) {
+	m := field.FindStringSubmatch(msg)
+	if m == nil {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(m[1], base, 32)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,44 @@
+// +build linux,seccomp
+
+package seccomp
+
+import (
+	"fmt"
+
+	libseccomp "github.com/seccomp/libseccomp-golang"
+)
+
+// x32SyscallBit is set in the numbers of the syscalls made with the x32 ABI,
+// which are logged with the x86_64 audit architecture.
+const x32SyscallBit = 0x40000000
+
+// auditArches maps the audit architectures of the kernel to the names of the
+// libseccomp architectures.
+var auditArches = map[uint32]string{
+	0x40000003: "x86",
+	0xc000003e: "amd64",
+	0x40000028: "arm",
+	0xc00000b7: "arm64",
+	0x00000008: "mips",
+	0x80000008: "mips64",
+	0xa0000008: "mips64n32",
+	0x40000008: "mipsel",
+	0xc0000008: "mipsel64",
+	0xe0000008: "mipsel64n32",
+}
+
+// SyscallName returns the name of a syscall logged in an audit record.
+func SyscallName(r *AuditRecord) (string, error) {
+	archName, ok := auditArches[r.Arch]
+	if !ok {
+		return "", fmt.Errorf("unknown audit architecture %x", r.Arch)
+	}
+	if archName == "amd64" && r.Syscall&x32SyscallBit != 0 {
+		archName = "x32"
+	}
+	arch, err := libseccomp.GetArchFromString(archName)
+	if err != nil {
+		return "", err
+	}
+	return libseccomp.ScmpSyscall(r.Syscall).GetNameByArch(arch)
+}
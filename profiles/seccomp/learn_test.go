@@ -0,0 +1,72 @@
+// +build linux
+
+package seccomp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestParseAuditRecord(t *testing.T) {
+	msg := `audit(1476781234.123:45): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=1234 comm="sh" exe="/bin/busybox" sig=0 arch=c000003e syscall=59 compat=0 ip=0x4d1c87 code=0x7ffc0000`
+	r, ok := ParseAuditRecord(msg)
+	if !ok {
+		t.Fatalf("expected %q to be parsed", msg)
+	}
+	expected := &AuditRecord{PID: 1234, Arch: 0xc000003e, Syscall: 59}
+	if !reflect.DeepEqual(r, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, r)
+	}
+
+	invalid := []string{
+		"usb 1-1: new high-speed USB device number 2 using xhci_hcd",
+		`audit(1476781234.123:48): pid=1234 uid=0 auid=4294967295 ses=4294967295 msg='op=test'`,
+		// killed by a seccomp filter, not logged by a learning one
+		`audit(1476781234.123:46): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=1234 comm="sh" exe="/bin/busybox" sig=31 arch=c000003e syscall=59 compat=0 ip=0x4d1c87 code=0x0`,
+		`audit(1476781234.123:47): pid=1234 comm="sh" code=0x7ffc0000`,
+	}
+	for _, msg := range invalid {
+		if r, ok := ParseAuditRecord(msg); ok {
+			t.Fatalf("expected %q not to be parsed, got %+v", msg, r)
+		}
+	}
+}
+
+func TestGenerateProfile(t *testing.T) {
+	p := GenerateProfile([]string{"write", "read", "exit_group", "read"})
+	if p.DefaultAction != types.ActErrno {
+		t.Fatalf("expected the default action %s, got %s", types.ActErrno, p.DefaultAction)
+	}
+	var names []string
+	for _, s := range p.Syscalls {
+		if s.Action != types.ActAllow {
+			t.Fatalf("expected %s to be allowed, got %s", s.Name, s.Action)
+		}
+		names = append(names, s.Name)
+	}
+	if expected := []string{"exit_group", "read", "write"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected the syscalls %v, got %v", expected, names)
+	}
+
+	// the generated profile can be loaded like the default one
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProfile(string(b)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLearnProfile(t *testing.T) {
+	b, err := json.Marshal(LearnProfile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProfile(string(b)); err != nil {
+		t.Fatal(err)
+	}
+}
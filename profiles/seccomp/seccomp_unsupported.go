@@ -2,9 +2,18 @@
 
 package seccomp
 
-import "github.com/docker/engine-api/types"
+import (
+	"fmt"
+
+	"github.com/docker/engine-api/types"
+)
 
 var (
 	// DefaultProfile is a nil pointer on unsupported systems.
 	DefaultProfile *types.Seccomp
 )
+
+// SyscallName returns an error on unsupported systems.
+func SyscallName(r *AuditRecord) (string, error) {
+	return "", fmt.Errorf("seccomp is not supported")
+}
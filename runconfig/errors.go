@@ -35,4 +35,6 @@ var (
 	ErrUnsupportedNetworkNoSubnetAndIP = fmt.Errorf("User specified IP address is supported only when connecting to networks with user configured subnets")
 	// ErrUnsupportedNetworkAndAlias conflict between network mode and alias
 	ErrUnsupportedNetworkAndAlias = fmt.Errorf("Network-scoped alias is supported only for containers in user defined networks")
+	// ErrUnsupportedNetworkAndDNSRRName conflict between network mode and round-robin DNS name
+	ErrUnsupportedNetworkAndDNSRRName = fmt.Errorf("Round-robin DNS name is supported only for containers in user defined networks")
 )
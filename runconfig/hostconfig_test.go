@@ -121,6 +121,27 @@ func TestUTSModeTest(t *testing.T) {
 	}
 }
 
+func TestUsernsModeTest(t *testing.T) {
+	usernsModes := map[container.UsernsMode][]bool{
+		// host, per container, valid
+		"":                {false, false, true},
+		"something:weird": {false, false, false},
+		"host":            {true, false, true},
+		"private":         {false, true, true},
+	}
+	for usernsMode, state := range usernsModes {
+		if usernsMode.IsHost() != state[0] {
+			t.Fatalf("UsernsMode.IsHost for %v should have been %v but was %v", usernsMode, state[0], usernsMode.IsHost())
+		}
+		if usernsMode.IsPerContainer() != state[1] {
+			t.Fatalf("UsernsMode.IsPerContainer for %v should have been %v but was %v", usernsMode, state[1], usernsMode.IsPerContainer())
+		}
+		if usernsMode.Valid() != state[2] {
+			t.Fatalf("UsernsMode.Valid for %v should have been %v but was %v", usernsMode, state[2], usernsMode.Valid())
+		}
+	}
+}
+
 func TestPidModeTest(t *testing.T) {
 	pidModes := map[container.PidMode][]bool{
 		// private, host, valid
@@ -0,0 +1,101 @@
+package opts
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	mounttypes "github.com/docker/engine-api/types/mount"
+)
+
+func TestMountOptValid(t *testing.T) {
+	cases := map[string]mounttypes.Mount{
+		"target=/foo": {
+			Type:   mounttypes.TypeVolume,
+			Target: "/foo",
+		},
+		"type=bind,source=/src,target=/dst,readonly,bind-propagation=rshared": {
+			Type:        mounttypes.TypeBind,
+			Source:      "/src",
+			Target:      "/dst",
+			ReadOnly:    true,
+			BindOptions: &mounttypes.BindOptions{Propagation: mounttypes.PropagationRShared},
+		},
+		`type=bind,"src=/with:colon,and,commas",dst=/dst,ro=false`: {
+			Type:   mounttypes.TypeBind,
+			Source: "/with:colon,and,commas",
+			Target: "/dst",
+		},
+		"type=volume,source=data,destination=/data,volume-driver=local,volume-opt=type=nfs,volume-opt=o=addr=1.2.3.4": {
+			Type:   mounttypes.TypeVolume,
+			Source: "data",
+			Target: "/data",
+			VolumeOptions: &mounttypes.VolumeOptions{
+				DriverConfig: &mounttypes.Driver{
+					Name:    "local",
+					Options: map[string]string{"type": "nfs", "o": "addr=1.2.3.4"},
+				},
+			},
+		},
+		"type=tmpfs,target=/tmp,tmpfs-size=64m,tmpfs-mode=1777": {
+			Type:   mounttypes.TypeTmpfs,
+			Target: "/tmp",
+			TmpfsOptions: &mounttypes.TmpfsOptions{
+				SizeBytes: 64 * 1024 * 1024,
+				Mode:      0777 | os.ModeSticky,
+			},
+		},
+	}
+	for value, expected := range cases {
+		m := NewMountOpt()
+		if err := m.Set(value); err != nil {
+			t.Fatalf("unexpected error for %q: %v", value, err)
+		}
+		mounts := m.Value()
+		if len(mounts) != 1 {
+			t.Fatalf("expected 1 mount for %q, got %d", value, len(mounts))
+		}
+		if !reflect.DeepEqual(mounts[0], expected) {
+			t.Fatalf("expected %+v for %q, got %+v", expected, value, mounts[0])
+		}
+	}
+}
+
+func TestMountOptInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"type=bind",
+		"type=,target=/foo",
+		"source=/foo",
+		"target=/foo,unknown=bar",
+		"target=/foo,noequals",
+		"target=/foo,readonly=maybe",
+		"type=volume,target=/foo,bind-propagation=rshared",
+		"type=bind,source=/src,target=/foo,volume-driver=local",
+		"type=volume,target=/foo,tmpfs-size=1m",
+		"type=tmpfs,target=/foo,tmpfs-size=big",
+		"type=tmpfs,target=/foo,tmpfs-mode=999",
+	}
+	for _, value := range invalid {
+		m := NewMountOpt()
+		if err := m.Set(value); err == nil {
+			t.Fatalf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestMountOptMultiple(t *testing.T) {
+	m := NewMountOpt()
+	if err := m.Set("type=volume,target=/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set("type=tmpfs,target=/bar"); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Value()) != 2 {
+		t.Fatalf("expected 2 mounts, got %d", len(m.Value()))
+	}
+	if s := m.String(); s != "volume  /foo, tmpfs  /bar" {
+		t.Fatalf("unexpected string %q", s)
+	}
+}
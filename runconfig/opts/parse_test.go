@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -358,6 +359,17 @@ func TestParseWithMacAddress(t *testing.T) {
 	}
 }
 
+func TestParseWithDNSRRName(t *testing.T) {
+	_, _, networkingConfig, _, err := parseRun([]string{"--net=web", "--dns-rr-name=frontend", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	epConfig, ok := networkingConfig.EndpointsConfig["web"]
+	if !ok || epConfig.DNSRRName != "frontend" {
+		t.Fatalf("Expected the endpoint config of network web to have 'frontend' as DNSRRName, got %v", networkingConfig.EndpointsConfig)
+	}
+}
+
 func TestParseWithMemory(t *testing.T) {
 	invalidMemory := "--memory=invalid"
 	validMemory := "--memory=1G"
@@ -384,6 +396,13 @@ func TestParseWithMemorySwap(t *testing.T) {
 	}
 }
 
+func TestParseWithCapAmbient(t *testing.T) {
+	_, hostconfig := mustParse(t, "--cap-add=NET_ADMIN --cap-ambient=NET_BIND_SERVICE --cap-ambient=NET_ADMIN")
+	if len(hostconfig.CapAmbient) != 2 || hostconfig.CapAmbient[0] != "NET_BIND_SERVICE" || hostconfig.CapAmbient[1] != "NET_ADMIN" {
+		t.Fatalf("Expected the config to have [NET_BIND_SERVICE NET_ADMIN] as CapAmbient, got %v", hostconfig.CapAmbient)
+	}
+}
+
 func TestParseHostname(t *testing.T) {
 	hostname := "--hostname=hostname"
 	hostnameWithDomain := "--hostname=hostname.domainname"
@@ -576,6 +595,35 @@ func TestParseRestartPolicy(t *testing.T) {
 	}
 }
 
+func TestParseCPUs(t *testing.T) {
+	valids := map[string]int64{
+		"1":     1000000000,
+		"1.5":   1500000000,
+		"0.01":  10000000,
+		"0.001": 1000000,
+	}
+	for value, expected := range valids {
+		_, hostconfig, _, _, err := parseRun([]string{fmt.Sprintf("--cpus=%s", value), "img", "cmd"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hostconfig.NanoCPUs != expected {
+			t.Fatalf("Expected %d NanoCPUs for %s, got %d", expected, value, hostconfig.NanoCPUs)
+		}
+	}
+
+	invalids := map[string]string{
+		"foo":          "invalid value for CPUs: foo",
+		"0.0000000001": "invalid value for CPUs: 0.0000000001 is too precise",
+		"1e20":         "invalid value for CPUs: 1e20 is too large",
+	}
+	for value, expectedError := range invalids {
+		if _, _, _, _, err := parseRun([]string{fmt.Sprintf("--cpus=%s", value), "img", "cmd"}); err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected an error with message '%v' for %v, got %v", expectedError, value, err)
+		}
+	}
+}
+
 func TestParseLoggingOpts(t *testing.T) {
 	// logging opts ko
 	if _, _, _, _, err := parseRun([]string{"--log-driver=none", "--log-opt=anything", "img", "cmd"}); err == nil || err.Error() != "invalid logging opts for driver none" {
@@ -813,3 +861,24 @@ func TestVolumeSplitN(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitSecurityOpt(t *testing.T) {
+	for _, x := range []struct {
+		input    string
+		expected []string
+	}{
+		{`no-new-privileges`, []string{`no-new-privileges`}},
+		{`no-new-privileges=false`, []string{`no-new-privileges`, `false`}},
+		{`label:user:USER`, []string{`label`, `user:USER`}},
+		{`label=user:USER`, []string{`label=user`, `USER`}},
+		{`apparmor:profile=name`, []string{`apparmor`, `profile=name`}},
+		{`seccomp=learn`, []string{`seccomp`, `learn`}},
+		{`seccomp:learn`, []string{`seccomp`, `learn`}},
+		{`seccomp=unconfined`, []string{`seccomp=unconfined`}},
+		{`seccomp:{"defaultAction":"SCMP_ACT_ERRNO","a=b":1}`, []string{`seccomp`, `{"defaultAction":"SCMP_ACT_ERRNO","a=b":1}`}},
+	} {
+		if res := SplitSecurityOpt(x.input); !reflect.DeepEqual(res, x.expected) {
+			t.Fatalf("input: %v, expected: %v, got: %v", x.input, x.expected, res)
+		}
+	}
+}
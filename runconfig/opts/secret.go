@@ -0,0 +1,90 @@
+package opts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/engine-api/types/container"
+)
+
+// SecretOpt defines a list of secret references, set with
+// `--secret name[,target=<path>,mode=<mode>,uid=<uid>]`.
+type SecretOpt struct {
+	values []container.SecretReference
+}
+
+// NewSecretOpt creates a new SecretOpt
+func NewSecretOpt() *SecretOpt {
+	return &SecretOpt{}
+}
+
+// Set parses a secret reference and adds it to the list.
+func (s *SecretOpt) Set(value string) error {
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return err
+	}
+
+	secret := container.SecretReference{}
+	for i, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) == 1 {
+			if i == 0 {
+				secret.Name = field
+				continue
+			}
+			return fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+		}
+
+		key, value := strings.ToLower(parts[0]), parts[1]
+		switch key {
+		case "name", "source", "src":
+			secret.Name = value
+		case "target", "dst", "destination":
+			secret.Target = value
+		case "mode":
+			mode, err := strconv.ParseUint(value, 8, 32)
+			if err != nil || mode > 0777 {
+				return fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+			secret.Mode = os.FileMode(mode)
+		case "uid":
+			uid, err := strconv.Atoi(value)
+			if err != nil || uid < 0 {
+				return fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+			secret.UID = uid
+		default:
+			return fmt.Errorf("unexpected key '%s' in '%s'", key, field)
+		}
+	}
+
+	if secret.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	s.values = append(s.values, secret)
+	return nil
+}
+
+// String returns the secret references as a string.
+func (s *SecretOpt) String() string {
+	secrets := []string{}
+	for _, secret := range s.values {
+		repr := secret.Name
+		if secret.Target != "" {
+			repr += " " + secret.Target
+		}
+		secrets = append(secrets, repr)
+	}
+	return strings.Join(secrets, ", ")
+}
+
+// Value returns the secret references
+func (s *SecretOpt) Value() []container.SecretReference {
+	return s.values
+}
@@ -0,0 +1,74 @@
+package opts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/engine-api/types/container"
+)
+
+func TestSecretOptValid(t *testing.T) {
+	cases := map[string]container.SecretReference{
+		"db": {
+			Name: "db",
+		},
+		"db,target=/etc/db/password,mode=0400,uid=1000": {
+			Name:   "db",
+			Target: "/etc/db/password",
+			Mode:   0400,
+			UID:    1000,
+		},
+		"name=db,dst=/db": {
+			Name:   "db",
+			Target: "/db",
+		},
+	}
+	for value, expected := range cases {
+		s := NewSecretOpt()
+		if err := s.Set(value); err != nil {
+			t.Fatalf("unexpected error for %q: %v", value, err)
+		}
+		secrets := s.Value()
+		if len(secrets) != 1 {
+			t.Fatalf("expected 1 secret for %q, got %d", value, len(secrets))
+		}
+		if !reflect.DeepEqual(secrets[0], expected) {
+			t.Fatalf("expected %+v for %q, got %+v", expected, value, secrets[0])
+		}
+	}
+}
+
+func TestSecretOptInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"target=/foo",
+		"db,noequals",
+		"db,unknown=bar",
+		"db,mode=999",
+		"db,mode=1777",
+		"db,uid=-1",
+		"db,uid=root",
+	}
+	for _, value := range invalid {
+		s := NewSecretOpt()
+		if err := s.Set(value); err == nil {
+			t.Fatalf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestSecretOptMultiple(t *testing.T) {
+	s := NewSecretOpt()
+	if err := s.Set("db"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("tls,target=/etc/tls/key"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Value()) != 2 {
+		t.Fatalf("expected 2 secrets, got %d", len(s.Value()))
+	}
+	if str := s.String(); str != "db, tls /etc/tls/key" {
+		t.Fatalf("unexpected string %q", str)
+	}
+}
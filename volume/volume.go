@@ -8,6 +8,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/system"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 // DefaultDriverName is the driver name used for the driver
@@ -119,6 +120,44 @@ func ParseVolumesFrom(spec string) (string, string, error) {
 	return id, mode, nil
 }
 
+// ConvertTmpfsOptions converts the options of a tmpfs mount to the data
+// string it is mounted with. Like mounts created with --tmpfs, the tmpfs does
+// not allow executables, setuid binaries or devices.
+func ConvertTmpfsOptions(opt *mounttypes.TmpfsOptions, readOnly bool) (string, error) {
+	rawOpts := []string{"noexec", "nosuid", "nodev"}
+	if readOnly {
+		rawOpts = append(rawOpts, "ro")
+	}
+	if opt != nil {
+		if opt.SizeBytes < 0 {
+			return "", fmt.Errorf("invalid tmpfs size: %d", opt.SizeBytes)
+		}
+		if opt.SizeBytes > 0 {
+			rawOpts = append(rawOpts, fmt.Sprintf("size=%d", opt.SizeBytes))
+		}
+		if opt.Mode != 0 {
+			rawOpts = append(rawOpts, fmt.Sprintf("mode=%o", unixMode(opt.Mode)))
+		}
+	}
+	return strings.Join(rawOpts, ","), nil
+}
+
+// unixMode converts a file mode to its unix permission bits, including the
+// setuid, setgid and sticky bits.
+func unixMode(mode os.FileMode) uint32 {
+	m := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		m |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		m |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		m |= 01000
+	}
+	return m
+}
+
 func errInvalidMode(mode string) error {
 	return fmt.Errorf("invalid mode: %v", mode)
 }
@@ -1,9 +1,12 @@
 package volume
 
 import (
+	"os"
 	"runtime"
 	"strings"
 	"testing"
+
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 func TestParseMountSpec(t *testing.T) {
@@ -210,3 +213,30 @@ func TestParseMountSpecSplit(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertTmpfsOptions(t *testing.T) {
+	cases := []struct {
+		opt      *mounttypes.TmpfsOptions
+		readOnly bool
+		expected string
+	}{
+		{nil, false, "noexec,nosuid,nodev"},
+		{nil, true, "noexec,nosuid,nodev,ro"},
+		{&mounttypes.TmpfsOptions{SizeBytes: 64 * 1024 * 1024}, false, "noexec,nosuid,nodev,size=67108864"},
+		{&mounttypes.TmpfsOptions{Mode: 0700}, false, "noexec,nosuid,nodev,mode=700"},
+		{&mounttypes.TmpfsOptions{Mode: 0777 | os.ModeSticky}, true, "noexec,nosuid,nodev,ro,mode=1777"},
+	}
+	for _, c := range cases {
+		data, err := ConvertTmpfsOptions(c.opt, c.readOnly)
+		if err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.opt, err)
+		}
+		if data != c.expected {
+			t.Fatalf("expected %q for %+v, got %q", c.expected, c.opt, data)
+		}
+	}
+
+	if _, err := ConvertTmpfsOptions(&mounttypes.TmpfsOptions{SizeBytes: -1}, false); err == nil {
+		t.Fatal("expected an error for a negative size")
+	}
+}